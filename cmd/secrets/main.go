@@ -0,0 +1,111 @@
+// Command secrets creates and reads the encrypted secrets file loaded through
+// SECRETS_FILE.
+//
+//	secrets keygen                      print a new key for SECRETS_KEY
+//	secrets seal < secrets.json > file  encrypt a JSON object of setting keys to values
+//	secrets keys < file                 list the setting keys held in an encrypted file
+//
+// seal and keys read the key from SECRETS_KEY or SECRETS_KEY_FILE.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MitulShah1/golang-rest-api-template/config"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen()
+	case "seal":
+		err = seal()
+	case "keys":
+		err = keys()
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "secrets:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: secrets keygen | seal | keys")
+	os.Exit(2)
+}
+
+func keygen() error {
+	key, err := config.NewSealKey()
+	if err != nil {
+		return err
+	}
+	fmt.Println(key)
+	return nil
+}
+
+func seal() error {
+	key, err := readKey()
+	if err != nil {
+		return err
+	}
+	secrets := map[string]string{}
+	if err := json.NewDecoder(os.Stdin).Decode(&secrets); err != nil {
+		return fmt.Errorf("reading secrets JSON: %w", err)
+	}
+	sealed, err := config.SealSecrets(key, secrets)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(sealed)
+	return err
+}
+
+// keys lists the setting keys only, so the values never reach the terminal
+func keys() error {
+	key, err := readKey()
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	secrets, err := config.OpenSecrets(key, data)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println(strings.Join(names, "\n"))
+	return nil
+}
+
+func readKey() ([]byte, error) {
+	encoded := os.Getenv(config.SecretsKeyEnv)
+	if path := os.Getenv(config.SecretsKeyFileEnv); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(data)
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("%s or %s is required", config.SecretsKeyEnv, config.SecretsKeyFileEnv)
+	}
+	return config.DecodeSealKey(encoded)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/MitulShah1/golang-rest-api-template/config"
 	"github.com/MitulShah1/golang-rest-api-template/internal/application"
 	_ "github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
 )
@@ -36,6 +38,15 @@ var (
 // @in header
 // @name Authorization
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the access token.
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
@@ -47,6 +58,33 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Check for help flag, listing every configuration flag and environment variable
+	if len(os.Args) > 1 && (os.Args[1] == "--help" || os.Args[1] == "-h") {
+		config.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	// Print the effective configuration with secrets redacted
+	if len(os.Args) > 1 && os.Args[1] == "--print-config" {
+		cnf := config.NewService()
+		if err := cnf.Load(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		out, _ := json.MarshalIndent(cnf.Config(), "", "  ")
+		fmt.Println(string(out))
+		os.Exit(0)
+	}
+
+	// Apply or inspect database migrations: server migrate up|down|status|goto|force
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Create and initialize the application
 	app := application.NewApplication()
 
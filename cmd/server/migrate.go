@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/config"
+	"github.com/MitulShah1/golang-rest-api-template/internal/application"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/migrations"
+)
+
+const migrateUsage = `usage: server migrate <command> [configuration flags]
+
+commands:
+  up         apply every pending migration
+  down [N]   roll back the last N migrations, 1 by default
+  status     print the schema version and the pending migrations
+  goto N     migrate up or down to version N, 0 rolling back everything
+  force N    record version N as applied and clean without running it`
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+// runMigrate runs the migrate subcommand with the migrations built into the
+// binary. The remaining arguments are configuration flags, as for the server.
+func runMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+	command, args := args[0], args[1:]
+
+	var version uint64
+	switch command {
+	case "up", "status":
+	case "down":
+		version = 1
+		if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+			n, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil || n == 0 {
+				return fmt.Errorf("down: invalid step count %q", args[0])
+			}
+			version, args = n, args[1:]
+		}
+	case "goto", "force":
+		if len(args) == 0 {
+			return errMigrateUsage
+		}
+		n, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid version %q", command, args[0])
+		}
+		version, args = n, args[1:]
+	default:
+		return errMigrateUsage
+	}
+
+	cnf := config.NewService()
+	if err := cnf.Load(args); err != nil {
+		return err
+	}
+	dbConfig := application.DatabaseConfig(cnf.GetDBConfig())
+	dbConfig.MultiStatements = true
+	dbConfig.Replicas = nil
+	db, err := database.NewDatabase(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	migrator.LockTimeout = cnf.GetDBConfig().MigrateLockTimeout
+	migrator.OnStep = func(m database.Migration, up bool) {
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		fmt.Fprintf(out, "%s %s\n", m, direction)
+	}
+
+	ctx := context.Background()
+	var steps int
+	switch command {
+	case "up":
+		steps, err = migrator.Up(ctx)
+	case "down":
+		steps, err = migrator.Down(ctx, int(version))
+	case "goto":
+		steps, err = migrator.Goto(ctx, version)
+	case "force":
+		err = migrator.Force(ctx, version)
+	case "status":
+		return printMigrationStatus(ctx, migrator, out)
+	}
+	if err != nil {
+		return err
+	}
+	if command != "force" && steps == 0 {
+		fmt.Fprintln(out, "no change")
+	}
+	return printMigrationStatus(ctx, migrator, out)
+}
+
+// printMigrationStatus writes the schema version and the pending migrations
+func printMigrationStatus(ctx context.Context, migrator *database.Migrator, out io.Writer) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	state := "clean"
+	switch {
+	case status.Dirty:
+		state = "dirty"
+	case status.Ahead():
+		state = "ahead of this binary"
+	}
+	fmt.Fprintf(out, "version %d (%s), latest %d\n", status.Version, state, status.Latest)
+	for _, m := range status.Pending {
+		fmt.Fprintf(out, "pending %s\n", m)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -112,3 +113,36 @@ func TestService_ConfigStructs(t *testing.T) {
 	assert.Equal(t, "jaeger-test", jaegerConfig.AgentHost)
 	assert.Equal(t, "6831", jaegerConfig.AgentPort)
 }
+
+func TestService_LoadConfig_Auth(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret-0123456789abcdef0123456789abcdef")
+	t.Setenv("JWT_ACCESS_TTL", "5m")
+	t.Setenv("AUTH_ADMIN_USERNAME", "root")
+	t.Setenv("AUTH_ADMIN_PASSWORD", "root-password")
+
+	service := NewService()
+	assert.NoError(t, service.LoadConfig())
+
+	authConfig := service.GetAuthConfig()
+	assert.Equal(t, "HS256", authConfig.JWTAlgorithm)
+	assert.Equal(t, "secret-0123456789abcdef0123456789abcdef", authConfig.JWTSecret)
+	assert.Equal(t, "go-rest-api-template", authConfig.JWTIssuer)
+	assert.Equal(t, 5*time.Minute, authConfig.AccessTokenTTL)
+	assert.Equal(t, 168*time.Hour, authConfig.RefreshTokenTTL)
+	assert.Equal(t, "root", authConfig.AdminUsername)
+	assert.Equal(t, "argon2id", authConfig.PasswordHash)
+	assert.Equal(t, 5, authConfig.MaxFailedLogins)
+	assert.Equal(t, 15*time.Minute, authConfig.LockoutDuration)
+	assert.Equal(t, time.Hour, authConfig.ResetTokenTTL)
+
+	t.Setenv("AUTH_MAX_FAILED_LOGINS", "many")
+	assert.Error(t, service.LoadConfig())
+
+	t.Setenv("AUTH_MAX_FAILED_LOGINS", "5")
+	t.Setenv("JWT_REFRESH_TTL", "forever")
+	assert.Error(t, service.LoadConfig())
+
+	t.Setenv("JWT_REFRESH_TTL", "168h")
+	t.Setenv("AUTH_ADMIN_PASSWORD", "")
+	assert.Error(t, service.LoadConfig())
+}
@@ -0,0 +1,119 @@
+// Package config provides configuration management for the application.
+// It handles loading environment variables, database configuration,
+// server settings, and telemetry configuration.
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Change describes a setting that differs between two configurations
+type Change struct {
+	Key string
+	Old string
+	New string
+	// Applied is false for settings that only take effect after a restart
+	Applied bool
+}
+
+// Subscribe registers fn to be called with the new configuration after every
+// successful reload. fn runs on the goroutine calling Reload and must not block.
+func (cnf *Service) Subscribe(fn func(Config)) {
+	cnf.mu.Lock()
+	defer cnf.mu.Unlock()
+	cnf.subscribers = append(cnf.subscribers, fn)
+}
+
+// Reload reads every source again with the arguments given to Load. If the
+// result is valid, the sections that can change at runtime are swapped in and
+// subscribers are notified; other sections keep their current values. It
+// returns every setting that changed. An invalid configuration is rejected
+// with a *ValidationError and leaves the current one untouched.
+func (cnf *Service) Reload() ([]Change, error) {
+	cnf.mu.RLock()
+	args := cnf.args
+	cnf.mu.RUnlock()
+
+	loaded, _, err := load(args, cnf.secretProviders())
+	if err != nil {
+		return nil, err
+	}
+
+	cnf.mu.Lock()
+	current := cnf.cfg
+	next := current
+	changes := diff(&current, &loaded)
+	applied := false
+	loadedFields := fields(&loaded)
+	for i, f := range fields(&next) {
+		if f.reload && !reflect.DeepEqual(f.value.Interface(), loadedFields[i].value.Interface()) {
+			f.value.Set(loadedFields[i].value)
+			applied = true
+		}
+	}
+	cnf.cfg = next
+	subscribers := append([]func(Config){}, cnf.subscribers...)
+	cnf.mu.Unlock()
+
+	if applied {
+		for _, fn := range subscribers {
+			fn(next)
+		}
+	}
+	return changes, nil
+}
+
+// WatchFile polls the configuration file every interval and calls fn when its
+// modification time or size changes, until ctx is done. It returns at once
+// when no configuration file was loaded.
+func (cnf *Service) WatchFile(ctx context.Context, interval time.Duration, fn func()) {
+	cnf.mu.RLock()
+	path := cnf.file
+	cnf.mu.RUnlock()
+	if path == "" {
+		return
+	}
+
+	last, _ := os.Stat(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				// The file may be replaced by a rename; wait for it to reappear
+				continue
+			}
+			if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+				last = info
+				fn()
+			}
+		}
+	}
+}
+
+// diff lists the settings that differ between old and next. Secret values are masked.
+func diff(old, next *Config) []Change {
+	var changes []Change
+	nextFields := fields(next)
+	for i, f := range fields(old) {
+		o, n := f.value.Interface(), nextFields[i].value.Interface()
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		change := Change{Key: f.key, Old: fmt.Sprint(o), New: fmt.Sprint(n), Applied: f.reload}
+		if f.secret {
+			change.Old, change.New = redacted, redacted
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Reload(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+log:
+  level: info
+db:
+  max_open_conns: 10
+auth:
+  jwt_secret: first-0123456789abcdef0123456789abcdef
+`)
+	service := NewService()
+	require.NoError(t, service.Load([]string{"--config", path}))
+
+	var notified []Config
+	service.Subscribe(func(c Config) {
+		notified = append(notified, c)
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+log:
+  level: debug
+db:
+  max_open_conns: 20
+cors:
+  allowed_origins: [https://app.example.com]
+auth:
+  jwt_secret: second-0123456789abcdef0123456789abcdef
+`), 0o600))
+
+	changes, err := service.Reload()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Change{
+		{Key: "db.max_open_conns", Old: "10", New: "20", Applied: false},
+		{Key: "log.level", Old: "info", New: "debug", Applied: true},
+		{Key: "cors.allowed_origins", Old: "[*]", New: "[https://app.example.com]", Applied: true},
+		{Key: "auth.jwt_secret", Old: "***", New: "***", Applied: false},
+	}, changes)
+
+	// only the reloadable sections are swapped in
+	cfg := service.Config()
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, []string{"https://app.example.com"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, 10, cfg.DB.MaxOpenConns)
+	assert.Equal(t, "first-0123456789abcdef0123456789abcdef", cfg.Auth.JWTSecret)
+
+	require.Len(t, notified, 1)
+	assert.Equal(t, "debug", notified[0].Log.Level)
+
+	// reloading again without changes does not notify
+	_, err = service.Reload()
+	require.NoError(t, err)
+	assert.Len(t, notified, 1)
+}
+
+func TestService_Reload_Invalid(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "log:\n  level: warn\nauth:\n  jwt_secret: s-0123456789abcdef0123456789abcdef\n")
+	service := NewService()
+	require.NoError(t, service.Load([]string{"--config", path}))
+
+	notified := false
+	service.Subscribe(func(Config) { notified = true })
+
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: chatty\nauth:\n  jwt_secret: s-0123456789abcdef0123456789abcdef\n"), 0o600))
+
+	_, err := service.Reload()
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{`log.level: must be one of debug, info, warn, error, got "chatty"`}, validationErr.Problems)
+	assert.Equal(t, "warn", service.GetLogConfig().Level)
+	assert.False(t, notified)
+}
+
+func TestService_WatchFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "auth:\n  jwt_secret: s-0123456789abcdef0123456789abcdef\n")
+	service := NewService()
+	require.NoError(t, service.Load([]string{"--config", path}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go service.WatchFile(ctx, 10*time.Millisecond, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	// The watcher may take its first look after a write, so keep writing
+	// (with a growing size) until it notices
+	timeout := time.After(2 * time.Second)
+	content := "auth:\n  jwt_secret: s-0123456789abcdef0123456789abcdef\n"
+	for {
+		content += "# changed\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		select {
+		case <-changed:
+			return
+		case <-timeout:
+			t.Fatal("file change was not noticed")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
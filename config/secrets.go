@@ -0,0 +1,361 @@
+// Package config provides configuration management for the application.
+// It handles loading environment variables, database configuration,
+// server settings, and telemetry configuration.
+package config
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SecretsFileEnv is the environment variable naming the encrypted secrets file
+	SecretsFileEnv = "SECRETS_FILE"
+	// SecretsKeyEnv is the environment variable holding the base64 encoded
+	// 32 byte key of the encrypted secrets file
+	SecretsKeyEnv = "SECRETS_KEY"
+	// SecretsKeyFileEnv is the environment variable naming a file that holds SECRETS_KEY
+	SecretsKeyFileEnv = "SECRETS_KEY_FILE"
+
+	// fileEnvSuffix is appended to the environment variable of a secret to
+	// read its value from a file, as in DB_PASSWORD_FILE=/run/secrets/db
+	fileEnvSuffix = "_FILE"
+
+	// redacted replaces secret values in logs and dumps
+	redacted = "***"
+
+	// secretTimeout bounds each secret provider lookup
+	secretTimeout = 10 * time.Second
+)
+
+var (
+	ErrSecretNotFound = errors.New("secret not found")
+	ErrInvalidSealed  = errors.New("invalid encrypted secrets file")
+	ErrInvalidSealKey = errors.New("secrets key must be 32 bytes, base64 encoded")
+)
+
+// SecretProvider resolves secret references. A secret setting whose value is
+// written as scheme://name, for example "vault://db-password", is looked up
+// with the provider registered for that scheme.
+type SecretProvider interface {
+	GetSecret(ctx context.Context, name string) (string, error)
+}
+
+// RegisterSecretProvider makes p resolve references with the given scheme. It
+// must be called before the configuration is loaded.
+func (cnf *Service) RegisterSecretProvider(scheme string, p SecretProvider) {
+	cnf.mu.Lock()
+	defer cnf.mu.Unlock()
+	if cnf.providers == nil {
+		cnf.providers = map[string]SecretProvider{}
+	}
+	cnf.providers[scheme] = p
+}
+
+// secretProviders returns the registered providers plus the built-in "file" scheme
+func (cnf *Service) secretProviders() map[string]SecretProvider {
+	cnf.mu.RLock()
+	defer cnf.mu.RUnlock()
+	providers := map[string]SecretProvider{"file": FileSecretProvider{}}
+	for scheme, p := range cnf.providers {
+		providers[scheme] = p
+	}
+	return providers
+}
+
+// FileSecretProvider reads a secret from the file named by the reference, so
+// "file:///run/secrets/db" reads /run/secrets/db. Trailing newlines are removed.
+type FileSecretProvider struct{}
+
+func (FileSecretProvider) GetSecret(_ context.Context, name string) (string, error) {
+	return readSecretFile(name)
+}
+
+// LocalSecretProvider is an in-memory provider. It stands in for a remote
+// secret manager in development and tests.
+type LocalSecretProvider struct {
+	mu      sync.RWMutex
+	secrets map[string]string
+}
+
+// NewLocalSecretProvider creates a provider holding a copy of secrets
+func NewLocalSecretProvider(secrets map[string]string) *LocalSecretProvider {
+	p := &LocalSecretProvider{secrets: map[string]string{}}
+	for name, value := range secrets {
+		p.secrets[name] = value
+	}
+	return p
+}
+
+func (p *LocalSecretProvider) GetSecret(_ context.Context, name string) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	value, ok := p.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// Set stores or replaces a secret
+func (p *LocalSecretProvider) Set(name, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets[name] = value
+}
+
+// applySecretFiles sets each secret from the file named by its <ENV>_FILE
+// variable. Setting both the variable and its _FILE form is a problem.
+func applySecretFiles(all []field) []string {
+	var problems []string
+	for _, f := range all {
+		if !f.secret {
+			continue
+		}
+		fileEnv := f.env + fileEnvSuffix
+		path, ok := os.LookupEnv(fileEnv)
+		if !ok {
+			continue
+		}
+		if _, both := os.LookupEnv(f.env); both {
+			problems = append(problems, fmt.Sprintf("%s: set either %s or %s, not both", f.key, f.env, fileEnv))
+			continue
+		}
+		value, err := readSecretFile(path)
+		if err == nil {
+			err = f.set(value)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v (from env %s)", f.key, err, fileEnv))
+		}
+	}
+	return problems
+}
+
+// applySealedFile sets secrets from the encrypted secrets file named by
+// SECRETS_FILE. Its keys are setting keys such as "db.password".
+func applySealedFile(all []field) []string {
+	path := os.Getenv(SecretsFileEnv)
+	if path == "" {
+		return nil
+	}
+
+	key, err := sealKey()
+	if err != nil {
+		return []string{fmt.Sprintf("secrets file: %v", err)}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("secrets file: %v", err)}
+	}
+	secrets, err := OpenSecrets(key, data)
+	if err != nil {
+		return []string{fmt.Sprintf("secrets file %s: %v", path, err)}
+	}
+
+	byKey := make(map[string]field, len(all))
+	for _, f := range all {
+		byKey[f.key] = f
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		f, ok := byKey[name]
+		if !ok || !f.secret {
+			problems = append(problems, fmt.Sprintf("%s: not a secret setting (in %s)", name, path))
+			continue
+		}
+		if err := f.set(secrets[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v (in %s)", name, err, path))
+		}
+	}
+	return problems
+}
+
+// resolveSecrets replaces scheme://name references in secret settings with
+// the value from the provider registered for the scheme
+func resolveSecrets(all []field, providers map[string]SecretProvider) []string {
+	var problems []string
+	for _, f := range all {
+		// References name a single value, so lists of secrets cannot use them
+		if !f.secret || f.value.Kind() != reflect.String {
+			continue
+		}
+		scheme, name, ok := strings.Cut(f.value.String(), "://")
+		if !ok {
+			continue
+		}
+		p, registered := providers[scheme]
+		if !registered {
+			// Not a reference, the secret itself contains "://"
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+		value, err := p.GetSecret(ctx, name)
+		cancel()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: resolving %s secret: %v", f.key, scheme, err))
+			continue
+		}
+		f.value.SetString(value)
+	}
+	return problems
+}
+
+// readSecretFile reads a secret from a file, dropping trailing newlines
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// sealKey reads the key of the encrypted secrets file from SECRETS_KEY or SECRETS_KEY_FILE
+func sealKey() ([]byte, error) {
+	encoded := os.Getenv(SecretsKeyEnv)
+	if path := os.Getenv(SecretsKeyFileEnv); path != "" {
+		var err error
+		if encoded, err = readSecretFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("%s or %s is required to read %s", SecretsKeyEnv, SecretsKeyFileEnv, SecretsFileEnv)
+	}
+	return DecodeSealKey(encoded)
+}
+
+// NewSealKey generates a random key for an encrypted secrets file, base64 encoded
+func NewSealKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// DecodeSealKey decodes a base64 encoded 32 byte key
+func DecodeSealKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidSealKey
+	}
+	return key, nil
+}
+
+// SealSecrets encrypts secrets with AES-256-GCM. The result is base64 text
+// holding the nonce followed by the ciphertext.
+func SealSecrets(key []byte, secrets map[string]string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plain, nil)
+
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(out, sealed)
+	return append(out, '\n'), nil
+}
+
+// OpenSecrets decrypts secrets written by SealSecrets
+func OpenSecrets(key, data []byte) (map[string]string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return nil, ErrInvalidSealed
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong key or corrupted file", ErrInvalidSealed)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, ErrInvalidSealed
+	}
+	return secrets, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidSealKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Redacted returns the settings as a map of setting key to value, with
+// secret values replaced by "***". Unset secrets stay empty and durations are
+// written as in configuration files, such as "5m0s".
+func (c Config) Redacted() map[string]any {
+	out := map[string]any{}
+	for _, f := range fields(&c) {
+		value := f.value.Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		if f.secret && f.value.Len() > 0 {
+			value = redacted
+		}
+		out[f.key] = value
+	}
+	return out
+}
+
+// String formats the configuration with secrets redacted, so that printing
+// or logging a Config never reveals them
+func (c Config) String() string {
+	var b strings.Builder
+	for i, f := range fields(&c) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		value := fmt.Sprint(f.value.Interface())
+		if f.secret && f.value.Len() > 0 {
+			value = redacted
+		}
+		fmt.Fprintf(&b, "%s=%s", f.key, value)
+	}
+	return b.String()
+}
+
+// MarshalJSON encodes the configuration with secrets redacted
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Redacted())
+}
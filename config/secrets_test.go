@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Load_SecretFiles(t *testing.T) {
+	t.Setenv("DB_PASSWORD_FILE", writeConfigFile(t, "db_password", "from-file\n"))
+	t.Setenv("JWT_SECRET", "secret-0123456789abcdef0123456789abcdef")
+
+	service := NewService()
+	require.NoError(t, service.Load(nil))
+	assert.Equal(t, "from-file", service.GetDBConfig().Password)
+
+	// Lists of secrets are read from files too
+	t.Setenv("DB_REPLICAS_FILE", writeConfigFile(t, "db_replicas", "u:p@tcp(a:3306)/app, u:p@tcp(b:3306)/app\n"))
+	require.NoError(t, service.Load(nil))
+	assert.Equal(t, []string{"u:p@tcp(a:3306)/app", "u:p@tcp(b:3306)/app"}, service.GetDBConfig().Replicas)
+
+	t.Setenv("DB_PASSWORD", "from-env")
+	err := service.Load(nil)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"db.password: set either DB_PASSWORD or DB_PASSWORD_FILE, not both"}, validationErr.Problems)
+}
+
+func TestService_Load_SealedFile(t *testing.T) {
+	encoded, err := NewSealKey()
+	require.NoError(t, err)
+	key, err := DecodeSealKey(encoded)
+	require.NoError(t, err)
+
+	sealed, err := SealSecrets(key, map[string]string{
+		"redis.password":  "sealed-redis",
+		"auth.jwt_secret": "sealed-jwt-0123456789abcdef0123456789abcdef",
+	})
+	require.NoError(t, err)
+	t.Setenv(SecretsFileEnv, writeConfigFile(t, "secrets.enc", string(sealed)))
+	t.Setenv(SecretsKeyEnv, encoded)
+
+	service := NewService()
+	require.NoError(t, service.Load(nil))
+	assert.Equal(t, "sealed-redis", service.GetRedisConfig().Password)
+	assert.Equal(t, "sealed-jwt-0123456789abcdef0123456789abcdef", service.GetAuthConfig().JWTSecret)
+
+	// environment variables override the secrets file
+	t.Setenv("REDIS_PASSWORD", "from-env")
+	require.NoError(t, service.Load(nil))
+	assert.Equal(t, "from-env", service.GetRedisConfig().Password)
+
+	other, err := NewSealKey()
+	require.NoError(t, err)
+	t.Setenv(SecretsKeyEnv, other)
+	assert.ErrorContains(t, service.Load(nil), "wrong key or corrupted file")
+}
+
+func TestService_Load_SealedFile_NonSecretKey(t *testing.T) {
+	encoded, err := NewSealKey()
+	require.NoError(t, err)
+	key, err := DecodeSealKey(encoded)
+	require.NoError(t, err)
+
+	sealed, err := SealSecrets(key, map[string]string{"db.host": "db", "auth.jwt_secret": "s-0123456789abcdef0123456789abcdef"})
+	require.NoError(t, err)
+	path := writeConfigFile(t, "secrets.enc", string(sealed))
+	t.Setenv(SecretsFileEnv, path)
+	t.Setenv(SecretsKeyEnv, encoded)
+
+	err = NewService().Load(nil)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"db.host: not a secret setting (in " + path + ")"}, validationErr.Problems)
+}
+
+func TestService_Load_SecretProviders(t *testing.T) {
+	t.Setenv("JWT_SECRET", "vault://jwt")
+	t.Setenv("DB_PASSWORD", "file://"+writeConfigFile(t, "db", "from-file-ref\n"))
+	t.Setenv("REDIS_PASSWORD", "plain://not-a-reference")
+
+	service := NewService()
+	service.RegisterSecretProvider("vault", NewLocalSecretProvider(map[string]string{"jwt": "from-vault-0123456789abcdef0123456789abcdef"}))
+	require.NoError(t, service.Load(nil))
+
+	assert.Equal(t, "from-vault-0123456789abcdef0123456789abcdef", service.GetAuthConfig().JWTSecret)
+	assert.Equal(t, "from-file-ref", service.GetDBConfig().Password)
+	assert.Equal(t, "plain://not-a-reference", service.GetRedisConfig().Password)
+
+	t.Setenv("JWT_SECRET", "vault://missing")
+	err := service.Load(nil)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"auth.jwt_secret: resolving vault secret: secret not found: missing"}, validationErr.Problems)
+}
+
+func TestLocalSecretProvider(t *testing.T) {
+	p := NewLocalSecretProvider(nil)
+	_, err := p.GetSecret(context.Background(), "db")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+
+	p.Set("db", "value")
+	value, err := p.GetSecret(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
+
+func TestSealSecrets(t *testing.T) {
+	encoded, err := NewSealKey()
+	require.NoError(t, err)
+	key, err := DecodeSealKey(encoded)
+	require.NoError(t, err)
+
+	sealed, err := SealSecrets(key, map[string]string{"db.password": "p"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "db.password")
+
+	secrets, err := OpenSecrets(key, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"db.password": "p"}, secrets)
+
+	_, err = OpenSecrets(key, []byte("not base64!"))
+	assert.ErrorIs(t, err, ErrInvalidSealed)
+
+	_, err = DecodeSealKey("c2hvcnQ=")
+	assert.ErrorIs(t, err, ErrInvalidSealKey)
+}
+
+func TestConfig_Redaction(t *testing.T) {
+	cfg := Defaults()
+	cfg.DB.Password = "db-password"
+	cfg.Auth.JWTSecret = "jwt-secret"
+	cfg.DB.Replicas = []string{"reader:replica-password@tcp(replica:3306)/app"}
+
+	for name, out := range map[string]string{
+		"String":  cfg.String(),
+		"Sprintf": fmt.Sprintf("%v", cfg),
+		"JSON":    mustJSON(t, cfg),
+	} {
+		assert.NotContains(t, out, "db-password", name)
+		assert.NotContains(t, out, "jwt-secret", name)
+		assert.NotContains(t, out, "replica-password", name)
+		assert.Contains(t, out, "***", name)
+	}
+
+	redactedCfg := cfg.Redacted()
+	assert.Equal(t, "***", redactedCfg["db.password"])
+	assert.Equal(t, "***", redactedCfg["db.replicas"])
+	assert.Equal(t, "", redactedCfg["redis.password"])
+	assert.Equal(t, "localhost", redactedCfg["db.host"])
+	assert.Equal(t, "15m0s", redactedCfg["auth.access_ttl"])
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(out)
+}
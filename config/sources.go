@@ -0,0 +1,275 @@
+// Package config provides configuration management for the application.
+// It handles loading environment variables, database configuration,
+// server settings, and telemetry configuration.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigFileFlag is the command line flag naming the configuration file
+	ConfigFileFlag = "config"
+	// ConfigFileEnv is the environment variable naming the configuration file
+	ConfigFileEnv = "CONFIG_FILE"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a single setting of Config
+type field struct {
+	key    string
+	env    string
+	usage  string
+	secret bool
+	reload bool
+	value  reflect.Value
+}
+
+// fields lists the settings of cfg in declaration order. The values point into cfg.
+func fields(cfg *Config) []field {
+	var out []field
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Type().Field(i).Tag.Get("config")
+		reload := root.Type().Field(i).Tag.Get("reload") == "true"
+		sv := root.Field(i)
+		for j := 0; j < sv.NumField(); j++ {
+			sf := sv.Type().Field(j)
+			out = append(out, field{
+				key:    section + "." + sf.Tag.Get("config"),
+				env:    sf.Tag.Get("env"),
+				usage:  sf.Tag.Get("usage"),
+				secret: sf.Tag.Get("secret") == "true",
+				reload: reload,
+				value:  sv.Field(j),
+			})
+		}
+	}
+	return out
+}
+
+// set parses raw into the field according to its type
+func (f field) set(raw string) error {
+	switch {
+	case f.value.Type() == durationType:
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, use a value such as 30s or 5m", raw)
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(raw)
+	case f.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.value.SetInt(int64(n))
+	case f.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.value.SetBool(b)
+	case f.value.Kind() == reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+// setAny stores a value decoded from a configuration file
+func (f field) setAny(v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		return fmt.Errorf("expected a value, found a table")
+	case []any:
+		if f.value.Kind() != reflect.Slice {
+			return fmt.Errorf("expected a single value, found a list")
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		f.value.Set(reflect.ValueOf(items))
+		return nil
+	default:
+		return f.set(fmt.Sprint(v))
+	}
+}
+
+// load builds the configuration from every source and validates it. It also
+// returns the configuration file that was read, if any.
+func load(args []string, providers map[string]SecretProvider) (Config, string, error) {
+	// Load .env file if present (optional - env vars may be set by Docker, etc.)
+	_ = godotenv.Load()
+
+	cfg := Defaults()
+	all := fields(&cfg)
+	var problems []string
+
+	// Flags are parsed first so that --config can name the file, but applied last
+	fs, file, flagValues := newFlagSet(all)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, "", fmt.Errorf("invalid command line: %w", err)
+	}
+
+	if *file == "" {
+		*file = os.Getenv(ConfigFileEnv)
+	}
+	if *file != "" {
+		problems = append(problems, applyFile(all, *file)...)
+	}
+
+	problems = append(problems, applySealedFile(all)...)
+
+	for _, f := range all {
+		if raw, ok := os.LookupEnv(f.env); ok {
+			if err := f.set(raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v (from env %s)", f.key, err, f.env))
+			}
+		}
+	}
+
+	problems = append(problems, applySecretFiles(all)...)
+
+	for _, f := range all {
+		if raw, ok := flagValues[f.key]; ok {
+			if err := f.set(raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v (from flag --%s)", f.key, err, f.key))
+			}
+		}
+	}
+
+	problems = append(problems, resolveSecrets(all, providers)...)
+
+	// A setting that could not be read is reported once, not again by the
+	// checks of the value it was left with
+	for _, problem := range cfg.problems() {
+		key, _, _ := strings.Cut(problem, ":")
+		if !slices.ContainsFunc(problems, func(p string) bool { return strings.HasPrefix(p, key+":") }) {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) > 0 {
+		return Config{}, "", &ValidationError{Problems: problems}
+	}
+
+	return cfg, *file, nil
+}
+
+// newFlagSet registers a flag per setting plus --config. Flag values are
+// collected as strings so they can be applied after the other sources.
+func newFlagSet(all []field) (*flag.FlagSet, *string, map[string]string) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	file := fs.String(ConfigFileFlag, "", "configuration file (.yaml, .yml, .toml or .json), also read from "+ConfigFileEnv)
+
+	values := map[string]string{}
+	for _, f := range all {
+		fs.Func(f.key, fmt.Sprintf("%s (env %s)", f.usage, f.env), func(s string) error {
+			values[f.key] = s
+			return nil
+		})
+	}
+	return fs, file, values
+}
+
+// PrintUsage writes the command line flags and their environment variables to w
+func PrintUsage(w io.Writer) {
+	cfg := Defaults()
+	fs, _, _ := newFlagSet(fields(&cfg))
+	fs.SetOutput(w)
+	fmt.Fprintln(w, "Usage of server:")
+	fs.PrintDefaults()
+}
+
+// applyFile decodes a configuration file and applies its settings, returning any problems
+func applyFile(all []field, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("config file: %v", err)}
+	}
+
+	doc := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&doc)
+	default:
+		return []string{fmt.Sprintf("config file: unsupported format %q, use .yaml, .yml, .toml or .json", ext)}
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("config file %s: %v", path, err)}
+	}
+
+	byKey := make(map[string]field, len(all))
+	for _, f := range all {
+		byKey[f.key] = f
+	}
+
+	values := map[string]any{}
+	flatten("", doc, values)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		f, ok := byKey[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown setting (in %s)", key, path))
+			continue
+		}
+		if err := f.setAny(values[key]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v (in %s)", key, err, path))
+		}
+	}
+	return problems
+}
+
+// flatten turns nested tables into dotted keys, so {db: {host: x}} becomes db.host
+func flatten(prefix string, doc map[string]any, out map[string]any) {
+	for k, v := range doc {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok && prefix == "" {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
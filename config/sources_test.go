@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestService_Load_Layers(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: "9000"
+  shutdown_timeout: 10s
+db:
+  host: db.internal
+  max_open_conns: 20
+cache:
+  product_ttl: 1h
+cors:
+  allowed_origins:
+    - https://app.example.com
+auth:
+  jwt_secret: from-file-0123456789abcdef0123456789abcdef
+`)
+	t.Setenv("DB_HOST", "db.env")
+	t.Setenv("REDIS_POOL_SIZE", "25")
+
+	service := NewService()
+	require.NoError(t, service.Load([]string{"--config", path, "--db.host", "db.flag"}))
+
+	cfg := service.Config()
+	// file overrides defaults
+	assert.Equal(t, "9000", cfg.Server.Port)
+	assert.Equal(t, 10*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, 20, cfg.DB.MaxOpenConns)
+	assert.Equal(t, time.Hour, cfg.Cache.ProductTTL)
+	assert.Equal(t, []string{"https://app.example.com"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, "from-file-0123456789abcdef0123456789abcdef", cfg.Auth.JWTSecret)
+	// env overrides defaults, flags override env
+	assert.Equal(t, 25, cfg.Redis.PoolSize)
+	assert.Equal(t, "db.flag", cfg.DB.Host)
+	// untouched settings keep their defaults
+	assert.Equal(t, 5*time.Minute, cfg.Cache.ProductListTTL)
+	assert.Equal(t, "6379", cfg.Redis.Port)
+}
+
+func TestService_Load_FileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "toml", file: "config.toml", content: "[db]\nmax_idle_conns = 2\n[auth]\njwt_secret = \"s-0123456789abcdef0123456789abcdef\"\n"},
+		{name: "json", file: "config.json", content: `{"db": {"max_idle_conns": 2}, "auth": {"jwt_secret": "s-0123456789abcdef0123456789abcdef"}}`},
+		{name: "yml", file: "config.yml", content: "db:\n  max_idle_conns: 2\nauth:\n  jwt_secret: s-0123456789abcdef0123456789abcdef\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigFileEnv, writeConfigFile(t, tt.file, tt.content))
+
+			service := NewService()
+			require.NoError(t, service.Load(nil))
+			assert.Equal(t, 2, service.GetDBConfig().MaxIdleConns)
+			assert.Equal(t, "s-0123456789abcdef0123456789abcdef", service.GetAuthConfig().JWTSecret)
+		})
+	}
+}
+
+func TestService_Load_AggregatesProblems(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+db:
+  hots: typo
+cache:
+  search_ttl: soon
+`)
+	t.Setenv("REDIS_DB", "two")
+	t.Setenv("JWT_SECRET", "")
+
+	service := NewService()
+	err := service.Load([]string{"--config", path, "--server.port", "99999"})
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.ElementsMatch(t, []string{
+		"cache.search_ttl: invalid duration \"soon\", use a value such as 30s or 5m (in " + path + ")",
+		"db.hots: unknown setting (in " + path + ")",
+		"redis.db: invalid integer \"two\" (from env REDIS_DB)",
+		"server.port: must be a port number between 1 and 65535, got \"99999\"",
+		"auth.jwt_secret: is required when auth.jwt_algorithm is HS256",
+	}, validationErr.Problems)
+	assert.Contains(t, err.Error(), "invalid configuration:\n  - ")
+}
+
+func TestService_Load_Errors(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret-0123456789abcdef0123456789abcdef")
+
+	service := NewService()
+	assert.Error(t, service.Load([]string{"--unknown"}))
+	assert.Error(t, service.Load([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")}))
+	assert.Error(t, service.Load([]string{"--config", writeConfigFile(t, "config.ini", "a=b")}))
+}
@@ -0,0 +1,87 @@
+// Package apperror defines the typed errors shared by the repository, the
+// services and the handlers. Each error has a Kind, which the handlers turn
+// into an HTTP status with response.ErrorStatus.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error by what the client can do about it
+type Kind int
+
+const (
+	// Internal is a failure the client cannot act on. It is the kind of every
+	// error that is not an *Error, and its details are not shown to clients.
+	Internal Kind = iota
+	// NotFound means the resource does not exist
+	NotFound
+	// Conflict means the request clashes with the current state of a resource
+	Conflict
+	// Validation means the request itself is invalid
+	Validation
+	// Forbidden means the caller may not do what the request asks
+	Forbidden
+	// PreconditionFailed means a resource changed since the caller read it
+	PreconditionFailed
+	// PreconditionRequired means a conditional request was expected
+	PreconditionRequired
+	// Unauthorized means the caller could not be authenticated
+	Unauthorized
+)
+
+// Error is an error of a given kind. Its message is safe to show to clients.
+type Error struct {
+	Kind    Kind
+	Message string
+	// base is the error Detailf added details to
+	base *Error
+}
+
+// New returns an error of the given kind. Errors are compared by identity, so
+// each is declared once as a package variable and matched with errors.Is.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Detailf returns an error of the kind of base whose message adds the
+// formatted details, such as the value that was rejected, to the message of
+// base. It still matches base with errors.Is.
+func Detailf(base *Error, format string, args ...any) *Error {
+	return &Error{Kind: base.Kind, Message: base.Message + ": " + fmt.Sprintf(format, args...), base: base}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error Detailf added details to, if any
+func (e *Error) Unwrap() error {
+	if e.base == nil {
+		return nil
+	}
+	return e.base
+}
+
+// As returns the first *Error in the chain of err
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// IsInternal reports whether err is an internal failure rather than an
+// answer the client can act on
+func IsInternal(err error) bool {
+	return KindOf(err) == Internal
+}
+
+// KindOf returns the kind of the first *Error in the chain of err, or
+// Internal when there is none
+func KindOf(err error) Kind {
+	if appErr, ok := As(err); ok {
+		return appErr.Kind
+	}
+	return Internal
+}
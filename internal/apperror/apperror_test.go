@@ -0,0 +1,39 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	errNotFound := New(NotFound, "product not found")
+
+	assert.Equal(t, NotFound, KindOf(errNotFound))
+	assert.Equal(t, NotFound, KindOf(fmt.Errorf("get product: %w", errNotFound)))
+	assert.Equal(t, Internal, KindOf(errors.New("connection refused")))
+	assert.Equal(t, Internal, KindOf(nil))
+}
+
+func TestDetailf(t *testing.T) {
+	errUnknownRole := New(Validation, "unknown role")
+	err := Detailf(errUnknownRole, "%q", "auditor")
+
+	assert.ErrorIs(t, err, errUnknownRole)
+	assert.Equal(t, Validation, KindOf(fmt.Errorf("set roles: %w", err)))
+	assert.Equal(t, `unknown role: "auditor"`, err.Error())
+}
+
+func TestAs(t *testing.T) {
+	errConflict := New(Conflict, "user already exists")
+
+	appErr, ok := As(fmt.Errorf("create user: %w", errConflict))
+	assert.True(t, ok)
+	assert.Same(t, errConflict, appErr)
+	assert.Equal(t, "user already exists", appErr.Error())
+
+	_, ok = As(errors.New("boom"))
+	assert.False(t, ok)
+}
@@ -4,6 +4,7 @@ package application
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,13 +14,24 @@ import (
 
 	"github.com/MitulShah1/golang-rest-api-template/config"
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
 	"github.com/MitulShah1/golang-rest-api-template/package/cache"
 	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/migrations"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/MitulShah1/golang-rest-api-template/package/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 )
 
+const (
+	// defaultShutdownTimeout is used when shutting down before the configuration is loaded
+	defaultShutdownTimeout = 30 * time.Second
+	// configWatchInterval is how often the configuration file is checked for changes
+	configWatchInterval = 5 * time.Second
+)
+
 // Application represents the main application instance
 type Application struct {
 	Name         string
@@ -27,9 +39,12 @@ type Application struct {
 	Server       *handlers.Server
 	Config       *config.Service
 	Database     *database.Database
-	Cache        *cache.Cache
+	Cache        cache.Cache
 	Tracer       *tracesdk.TracerProvider
 	ShutdownChan chan os.Signal
+	ReloadChan   chan os.Signal
+	// Redis backs the refresh token store whatever the cache backend is
+	Redis *redis.Client
 }
 
 // NewApplication creates a new application instance
@@ -37,6 +52,7 @@ func NewApplication() *Application {
 	return &Application{
 		Name:         "go-rest-api-template",
 		ShutdownChan: make(chan os.Signal, 1),
+		ReloadChan:   make(chan os.Signal, 1),
 	}
 }
 
@@ -53,6 +69,7 @@ func (app *Application) Initialize() error {
 	}{
 		{"configuration", app.initializeConfiguration},
 		{"database", app.initializeDatabase},
+		{"schema", app.initializeSchema},
 		{"cache", app.initializeCache},
 		{"telemetry", app.initializeTelemetry},
 		{"server", app.initializeServer},
@@ -76,6 +93,29 @@ func (app *Application) Run() error {
 	// Set up signal handling for graceful shutdown
 	signal.Notify(app.ShutdownChan, os.Interrupt, syscall.SIGTERM)
 
+	// Reload the configuration on SIGHUP or when the configuration file changes
+	signal.Notify(app.ReloadChan, syscall.SIGHUP)
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go app.Config.WatchFile(watchCtx, configWatchInterval, func() {
+		app.Logger.Info("Configuration file changed")
+		// A reload already queued will pick up this change too
+		select {
+		case app.ReloadChan <- syscall.SIGHUP:
+		default:
+		}
+	})
+	go func() {
+		for range app.ReloadChan {
+			app.reloadConfiguration()
+		}
+	}()
+
+	// Hard delete soft-deleted products and categories once past the retention
+	if dbConfig := app.Config.GetDBConfig(); dbConfig.DeletedRetention > 0 {
+		go runPurge(watchCtx, repository.NewDBRepository(app.Database), app.Logger, dbConfig.DeletedRetention, dbConfig.PurgeInterval)
+	}
+
 	// Start server in a goroutine
 	go func() {
 		if err := app.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -87,6 +127,7 @@ func (app *Application) Run() error {
 	// Wait for shutdown signal
 	<-app.ShutdownChan
 	app.Logger.Info("Shutting down application...")
+	signal.Stop(app.ReloadChan)
 
 	// Perform graceful shutdown
 	return app.Shutdown()
@@ -102,7 +143,11 @@ func (app *Application) Shutdown() error {
 	app.Logger.Info("Starting graceful shutdown")
 
 	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	timeout := defaultShutdownTimeout
+	if app.Config != nil && app.Config.GetServerConfig().ShutdownTimeout > 0 {
+		timeout = app.Config.GetServerConfig().ShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// Define shutdown components in reverse order
@@ -151,7 +196,7 @@ func (app *Application) GetDatabase() *database.Database {
 }
 
 // GetCache returns the cache instance
-func (app *Application) GetCache() *cache.Cache {
+func (app *Application) GetCache() cache.Cache {
 	return app.Cache
 }
 
@@ -173,45 +218,188 @@ func (app *Application) createTelemetryConfig() middleware.TelemetryConfig {
 // initializeConfiguration sets up the application configuration
 func (app *Application) initializeConfiguration() error {
 	app.Config = config.NewService()
-	return app.Config.Init()
+	err := app.Config.Init()
+	app.logValidationProblems(err)
+	if err != nil {
+		return err
+	}
+
+	if err := app.Logger.SetLevel(app.Config.GetLogConfig().Level); err != nil {
+		return err
+	}
+	app.Config.Subscribe(func(c config.Config) {
+		if err := app.Logger.SetLevel(c.Log.Level); err != nil {
+			app.Logger.Error("failed to change log level", "error", err)
+		}
+	})
+	return nil
+}
+
+// reloadConfiguration re-reads the configuration and logs what changed. An
+// invalid configuration is rejected and the running one is kept.
+func (app *Application) reloadConfiguration() {
+	app.Logger.Info("Reloading configuration")
+
+	changes, err := app.Config.Reload()
+	if err != nil {
+		app.logValidationProblems(err)
+		app.Logger.Error("Configuration reload rejected, keeping the current configuration", "error", err.Error())
+		return
+	}
+
+	if len(changes) == 0 {
+		app.Logger.Info("Configuration reloaded, nothing changed")
+		return
+	}
+	for _, change := range changes {
+		if change.Applied {
+			app.Logger.Info("Configuration changed", "key", change.Key, "old", change.Old, "new", change.New)
+		} else {
+			app.Logger.Warn("Configuration change needs a restart to take effect", "key", change.Key, "old", change.Old, "new", change.New)
+		}
+	}
+}
+
+// logValidationProblems logs every configuration problem on its own line so
+// they are all readable
+func (app *Application) logValidationProblems(err error) {
+	var validationErr *config.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, problem := range validationErr.Problems {
+			app.Logger.Error("invalid configuration", "problem", problem)
+		}
+	}
 }
 
 // initializeDatabase sets up the database connection
 func (app *Application) initializeDatabase() error {
 	app.Logger.Info("Initializing database connection")
 
-	db, err := database.NewDatabase(&database.DBConfig{
-		Host:     app.Config.GetDBConfig().Host,
-		Port:     app.Config.GetDBConfig().Port,
-		User:     app.Config.GetDBConfig().User,
-		Password: app.Config.GetDBConfig().Password,
-		DBName:   app.Config.GetDBConfig().Name,
-	})
+	db, err := database.NewDatabase(DatabaseConfig(app.Config.GetDBConfig()))
 	if err != nil {
 		return err
 	}
 
 	app.Database = db
+	if healthy, total := db.HealthyReplicas(); total > 0 {
+		app.Logger.Info("Read replicas connected", "healthy", healthy, "total", total)
+	}
 	app.Logger.Info("Database connection established successfully")
 	return nil
 }
 
-// initializeCache sets up the Redis cache connection
-func (app *Application) initializeCache() error {
-	app.Logger.Info("Initializing Redis cache connection")
+// DatabaseConfig converts the database settings to a database.DBConfig
+func DatabaseConfig(c config.DBConfig) *database.DBConfig {
+	return &database.DBConfig{
+		Driver:             c.Driver,
+		SSLMode:            c.SSLMode,
+		Host:               c.Host,
+		Port:               c.Port,
+		User:               c.User,
+		Password:           c.Password,
+		DBName:             c.Name,
+		MaxConn:            c.MaxOpenConns,
+		MaxIdle:            c.MaxIdleConns,
+		ConnectionTimeeout: c.ConnMaxLifetime,
+
+		Replicas:             c.Replicas,
+		ReplicaCheckInterval: c.ReplicaCheckInterval,
+		ReplicaMaxLag:        c.ReplicaMaxLag,
+	}
+}
+
+// initializeSchema applies pending migrations when auto migration is enabled
+// and refuses to start when the database schema is dirty or newer than the
+// migrations built into this binary
+func (app *Application) initializeSchema() error {
+	dbConfig := app.Config.GetDBConfig()
+
+	db := app.Database
+	if db.Driver() == database.DriverMySQL {
+		// MySQL only runs multi-statement scripts on connections allowing them
+		cfg := DatabaseConfig(dbConfig)
+		cfg.MultiStatements = true
+		cfg.MaxConn, cfg.MaxIdle = 1, 1
+		cfg.Replicas = nil
+		migrationDB, err := database.NewDatabase(cfg)
+		if err != nil {
+			return err
+		}
+		defer migrationDB.Close()
+		db = migrationDB
+	}
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	migrator.LockTimeout = dbConfig.MigrateLockTimeout
+	migrator.OnStep = func(m database.Migration, up bool) {
+		app.Logger.Info("Applied migration", "migration", m.String())
+	}
 
-	cache, err := cache.NewCache(&cache.RedisConfig{
-		Host:     app.Config.GetRedisConfig().Host,
-		Port:     app.Config.GetRedisConfig().Port,
-		Password: app.Config.GetRedisConfig().Password,
-		DB:       app.Config.GetRedisConfig().DB,
+	ctx := context.Background()
+	if dbConfig.AutoMigrate {
+		app.Logger.Info("Applying database migrations")
+		if _, err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	}
+
+	status, err := migrator.CheckVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if len(status.Pending) > 0 {
+		app.Logger.Warn("Database schema is behind, run the migrate command or enable db.auto_migrate",
+			"version", status.Version, "latest", status.Latest)
+		return nil
+	}
+	app.Logger.Info("Database schema is up to date", "version", status.Version)
+	return nil
+}
+
+// initializeCache sets up the cache backend and the Redis client of the
+// refresh token store
+func (app *Application) initializeCache() error {
+	cacheCfg := app.Config.GetCacheConfig()
+	app.Logger.Info("Initializing cache", "backend", cacheCfg.Backend)
+
+	redisCfg := cache.RedisConfig{
+		Host:           app.Config.GetRedisConfig().Host,
+		Port:           app.Config.GetRedisConfig().Port,
+		Password:       app.Config.GetRedisConfig().Password,
+		DB:             app.Config.GetRedisConfig().DB,
+		PoolSize:       app.Config.GetRedisConfig().PoolSize,
+		ConnectTimeout: app.Config.GetRedisConfig().ConnectTimeout,
+		DefaultTTL:     cacheCfg.DefaultTTL,
+	}
+	c, err := cache.New(&cache.Config{
+		Backend:    cacheCfg.Backend,
+		Fallback:   cacheCfg.Fallback,
+		Redis:      redisCfg,
+		MaxEntries: cacheCfg.MaxEntries,
+		L1TTL:      cacheCfg.L1TTL,
+		DefaultTTL: cacheCfg.DefaultTTL,
 	}, app.Logger)
 	if err != nil {
 		return err
 	}
+	app.Cache = c
 
-	app.Cache = cache
-	app.Logger.Info("Redis cache connection established successfully")
+	if err := cache.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		return err
+	}
+
+	if redisCache, ok := c.(interface{ GetClient() *redis.Client }); ok {
+		app.Redis = redisCache.GetClient()
+	} else {
+		// Refresh tokens must be shared by every instance, so they stay in
+		// Redis; logins fail while it is unreachable
+		app.Redis = cache.NewRedisClient(&redisCfg)
+	}
+
+	app.Logger.Info("Cache initialized successfully", "backend", cache.BackendOf(c))
 	return nil
 }
 
@@ -248,7 +436,7 @@ func (app *Application) initializeServer() error {
 	// Set the tracer provider
 	tmConfig.TraceProvider = tracer
 
-	server, err := handlers.NewServer(serverAddr, app.Logger, app.Database, app.Cache, &tmConfig)
+	server, err := handlers.NewServer(serverAddr, app.Config, app.Logger, app.Database, app.Cache, app.Redis, &tmConfig)
 	if err != nil {
 		return err
 	}
@@ -274,11 +462,17 @@ func (app *Application) shutdownTracer(ctx context.Context) error {
 	return app.Tracer.Shutdown(ctx)
 }
 
-// shutdownCache shuts down the cache
+// shutdownCache shuts down the cache and the Redis client, unless the cache
+// owns it
 func (app *Application) shutdownCache(ctx context.Context) error {
 	if app.Cache == nil {
 		return nil
 	}
+	if _, owned := app.Cache.(interface{ GetClient() *redis.Client }); !owned && app.Redis != nil {
+		if err := app.Redis.Close(); err != nil {
+			return err
+		}
+	}
 	return app.Cache.Close()
 }
 
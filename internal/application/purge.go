@@ -0,0 +1,46 @@
+// Package application provides application lifecycle management.
+// It handles initialization, configuration, and graceful shutdown of all application components.
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+)
+
+// purgeTimeout bounds each purge of soft-deleted rows
+const purgeTimeout = time.Minute
+
+// runPurge hard deletes the products and categories soft deleted more than
+// retention ago, once at start and then every interval until ctx is done
+func runPurge(ctx context.Context, repo repository.TrashRepository, log *logger.Logger, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeDeleted(ctx, repo, log, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeDeleted runs one purge and logs what it removed
+func purgeDeleted(ctx context.Context, repo repository.TrashRepository, log *logger.Logger, retention time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, purgeTimeout)
+	defer cancel()
+
+	products, categories, err := repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.Error("failed to purge deleted rows", "error", err)
+		return
+	}
+	if products > 0 || categories > 0 {
+		log.Info("Purged deleted rows", "products", products, "categories", categories)
+	}
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTrash records the cutoffs it is asked to purge before
+type fakeTrash struct {
+	cutoffs chan time.Time
+	err     error
+}
+
+func (f *fakeTrash) PurgeDeleted(_ context.Context, before time.Time) (int64, int64, error) {
+	f.cutoffs <- before
+	return 2, 1, f.err
+}
+
+func TestRunPurge(t *testing.T) {
+	trash := &fakeTrash{cutoffs: make(chan time.Time, 10), err: errors.New("ignored")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		runPurge(ctx, trash, logger.NewLogger(logger.DefaultOptions()), time.Hour, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// A failed purge is logged and retried on the next tick
+	for range 2 {
+		select {
+		case before := <-trash.cutoffs:
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+		case <-time.After(time.Second):
+			t.Fatal("purge did not run")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge did not stop")
+	}
+}
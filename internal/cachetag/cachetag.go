@@ -0,0 +1,28 @@
+// Package cachetag names the cache tags shared by the product and category
+// services, so that a write through one evicts what the other has cached.
+package cachetag
+
+import "fmt"
+
+const (
+	// ProductList tags the cached product list pages that are not filtered by
+	// category and the search results, which any product write may change
+	ProductList = "product-list"
+	// CategoryTree tags the cached category trees, which any category write
+	// may change
+	CategoryTree = "category-tree"
+)
+
+// Category tags the cached entries that change with a category: the
+// category, its children and the details of its products
+func Category(id int) string {
+	return fmt.Sprintf("category:%d", id)
+}
+
+// CategoryProducts tags the cached product list pages filtered by a category,
+// which change with the products in it. The pages are tagged with Category
+// too, so that category writes drop them, but product writes only drop
+// CategoryProducts and leave the category itself cached.
+func CategoryProducts(id int) string {
+	return fmt.Sprintf("category-products:%d", id)
+}
@@ -0,0 +1,13 @@
+package cachetag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, "category:5", Category(5))
+	assert.NotEqual(t, Category(5), Category(50))
+	assert.NotEqual(t, Category(5), CategoryProducts(5))
+}
@@ -0,0 +1,40 @@
+// Package admin provides HTTP handlers for operational endpoints.
+// They are mounted on the /api/admin group behind authentication and per-route permissions.
+package admin
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/middleware"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// CacheStatsPath is the path for reading cache statistics
+	CacheStatsPath = "/cache/stats"
+	// FlushCachePath is the path for flushing the cache or one of its namespaces
+	FlushCachePath = "/cache/flush"
+)
+
+// CacheAdminAPI provides cache administration endpoints
+type CacheAdminAPI struct {
+	logger *logger.Logger
+	cache  cache.Cache
+}
+
+// NewCacheAdminAPI creates a new cache administration API instance
+func NewCacheAdminAPI(logger *logger.Logger, cache cache.Cache) *CacheAdminAPI {
+	return &CacheAdminAPI{
+		logger: logger,
+		cache:  cache,
+	}
+}
+
+// RegisterHandlers registers the cache administration routes on the admin router
+func (h *CacheAdminAPI) RegisterHandlers(router *mux.Router) {
+	router.HandleFunc(CacheStatsPath, middleware.RequirePermission(auth.PermCacheRead, h.CacheStats)).Methods(http.MethodGet)
+	router.HandleFunc(FlushCachePath, middleware.RequirePermission(auth.PermCacheFlush, h.FlushCache)).Methods(http.MethodPost)
+}
@@ -0,0 +1,131 @@
+// Package admin provides HTTP handlers for operational endpoints.
+// They are mounted on the /api/admin group behind authentication and per-route permissions.
+package admin
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+)
+
+// cacheNamespaces are the key prefixes of cached entries. The same Redis
+// database holds refresh tokens, loader locks and tag sets, which flushing
+// must leave alone.
+var cacheNamespaces = []string{"product", "category"}
+
+// CacheStats godoc
+// @Summary Cache statistics
+// @Description Return the number of keys and the report of the cache backend, the INFO report for Redis
+// @Tags Admin
+// @Produce json
+// @Success      200  {object}  map[string]any
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /admin/cache/stats [get]
+// CacheStats returns cache statistics
+func (h *CacheAdminAPI) CacheStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get the backend report
+	info, err := h.cache.Info(ctx)
+	if err != nil {
+		h.logger.Error("failed to get cache stats", "error", err)
+		response.SendProblem(w, r, http.StatusInternalServerError, "Failed to get cache statistics")
+		return
+	}
+
+	// Get database size
+	dbSize, err := h.cache.DBSize(ctx)
+	if err != nil {
+		response.SendProblem(w, r, http.StatusInternalServerError, "Failed to get database size")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Cache statistics", map[string]any{
+		"backend":   cache.BackendOf(h.cache),
+		"db_size":   dbSize,
+		"timestamp": time.Now().Unix(),
+		"info":      info,
+	})
+}
+
+// FlushCache godoc
+// @Summary Flush cache
+// @Description Remove every cached entry, or only the entries of one namespace (for example "product" removes "product:*").
+// @Description With dry_run=true nothing is removed and the response reports how many keys would be.
+// @Description Refresh tokens and other keys that are not cached entries are never removed.
+// @Tags Admin
+// @Produce json
+// @Param namespace query string false "Key namespace to flush: product or category"
+// @Param dry_run query bool false "Report how many keys would be removed without removing them"
+// @Success      200  {object}  map[string]any
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /admin/cache/flush [post]
+// FlushCache clears all cache data or a single namespace
+func (h *CacheAdminAPI) FlushCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	namespace := q.Get("namespace")
+	namespaces := cacheNamespaces
+	if namespace != "" {
+		if !slices.Contains(cacheNamespaces, namespace) {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid namespace, must be one of product, category")
+			return
+		}
+		namespaces = []string{namespace}
+	}
+
+	dryRun := false
+	if v := q.Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid query parameter: dry_run")
+			return
+		}
+	}
+
+	var keys int64
+	for _, ns := range namespaces {
+		flush := h.cache.FlushPattern
+		if dryRun {
+			flush = h.cache.CountPattern
+		}
+		n, err := flush(ctx, ns+":*")
+		keys += n
+		if err != nil {
+			h.logger.Error("failed to flush cache", "namespace", ns, "error", err)
+			response.SendProblem(w, r, http.StatusInternalServerError, "Failed to flush cache")
+			return
+		}
+	}
+
+	message := "Cache flushed successfully"
+	if dryRun {
+		message = "Dry run, no keys were removed"
+	} else {
+		subject := ""
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			subject = principal.Subject
+		}
+		h.logger.Warn("cache flushed", "namespace", namespace, "keys", keys, "by", subject)
+	}
+
+	response.Success(w, http.StatusOK, message, map[string]any{
+		"namespace": namespace,
+		"dry_run":   dryRun,
+		"keys":      keys,
+		"timestamp": time.Now().Unix(),
+	})
+}
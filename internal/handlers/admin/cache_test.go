@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T) (*cache.RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	c, err := cache.NewRedisCache(&cache.RedisConfig{Host: host, Port: port}, logger.NewLogger(logger.DefaultOptions()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c, mr
+}
+
+// newAdminRouter registers the cache admin API behind a fixed principal
+func newAdminRouter(api *CacheAdminAPI, principal *auth.Principal) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	api.RegisterHandlers(router)
+	return router
+}
+
+func seed(t *testing.T, mr *miniredis.Miniredis) {
+	t.Helper()
+	for _, key := range []string{"product:1", "product:2", "category:1", "auth:refresh:abc", "lock:product:1"} {
+		require.NoError(t, mr.Set(key, "{}"))
+	}
+}
+
+func flushResult(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var res struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	return res.Data
+}
+
+func TestCacheAdminAPI_FlushCache(t *testing.T) {
+	c, mr := newTestCache(t)
+	admin := &auth.Principal{Subject: "admin", Permissions: []auth.Permission{auth.PermCacheFlush}}
+	router := newAdminRouter(NewCacheAdminAPI(logger.NewLogger(logger.DefaultOptions()), c), admin)
+
+	t.Run("Dry Run Namespace", func(t *testing.T) {
+		seed(t, mr)
+		req := httptest.NewRequest(http.MethodPost, FlushCachePath+"?namespace=product&dry_run=true", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		data := flushResult(t, w)
+		assert.Equal(t, float64(2), data["keys"])
+		assert.Equal(t, true, data["dry_run"])
+		assert.Len(t, mr.Keys(), 5)
+	})
+
+	t.Run("Flush Namespace", func(t *testing.T) {
+		seed(t, mr)
+		req := httptest.NewRequest(http.MethodPost, FlushCachePath+"?namespace=product", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, float64(2), flushResult(t, w)["keys"])
+		assert.ElementsMatch(t, []string{"category:1", "auth:refresh:abc", "lock:product:1"}, mr.Keys())
+	})
+
+	t.Run("Dry Run Everything", func(t *testing.T) {
+		seed(t, mr)
+		req := httptest.NewRequest(http.MethodPost, FlushCachePath+"?dry_run=1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, float64(3), flushResult(t, w)["keys"])
+		assert.Len(t, mr.Keys(), 5)
+	})
+
+	t.Run("Flush Everything", func(t *testing.T) {
+		seed(t, mr)
+		req := httptest.NewRequest(http.MethodPost, FlushCachePath, http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		// Refresh tokens and locks are not cached entries
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, float64(3), flushResult(t, w)["keys"])
+		assert.ElementsMatch(t, []string{"auth:refresh:abc", "lock:product:1"}, mr.Keys())
+	})
+
+	t.Run("Invalid Namespace", func(t *testing.T) {
+		for _, namespace := range []string{"*", "auth", "lock"} {
+			seed(t, mr)
+			req := httptest.NewRequest(http.MethodPost, FlushCachePath+"?namespace="+namespace, http.NoBody)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, namespace)
+			assert.Len(t, mr.Keys(), 5)
+		}
+	})
+
+	t.Run("Invalid Dry Run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, FlushCachePath+"?dry_run=maybe", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCacheAdminAPI_Permissions(t *testing.T) {
+	c, mr := newTestCache(t)
+	api := NewCacheAdminAPI(logger.NewLogger(logger.DefaultOptions()), c)
+	reader := &auth.Principal{Subject: "ops", Permissions: []auth.Permission{auth.PermCacheRead}}
+
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"Stats Unauthenticated", nil, http.MethodGet, CacheStatsPath, http.StatusUnauthorized},
+		{"Stats With Read", reader, http.MethodGet, CacheStatsPath, http.StatusOK},
+		{"Flush Unauthenticated", nil, http.MethodPost, FlushCachePath, http.StatusUnauthorized},
+		{"Flush Without Permission", reader, http.MethodPost, FlushCachePath, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed(t, mr)
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			w := httptest.NewRecorder()
+
+			newAdminRouter(api, tt.principal).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Len(t, mr.Keys(), 5)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package apikey provides HTTP handlers for API key management.
+// It includes endpoints for creating, listing and revoking API keys.
+package apikey
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/apikey"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// APIKeysPath is the path for creating and listing API keys
+	APIKeysPath = "/api-keys"
+	// APIKeyPath is the path for revoking an API key
+	APIKeyPath = "/api-keys/{id}"
+)
+
+type APIKeyAPI struct {
+	logger    *logger.Logger
+	apiKeySrv apikey.APIKeyServiceInterface
+}
+
+func NewAPIKeyAPI(logger *logger.Logger, apiKeySrv apikey.APIKeyServiceInterface) *APIKeyAPI {
+	return &APIKeyAPI{
+		logger:    logger,
+		apiKeySrv: apiKeySrv,
+	}
+}
+
+// RegisterHandlers registers the API key endpoints, which require an authenticated caller
+func (a *APIKeyAPI) RegisterHandlers(router *mux.Router) {
+	router.HandleFunc(APIKeysPath, a.CreateAPIKey).Methods(http.MethodPost)
+	router.HandleFunc(APIKeysPath, a.ListAPIKeys).Methods(http.MethodGet)
+	router.HandleFunc(APIKeyPath, a.RevokeAPIKey).Methods(http.MethodDelete)
+}
+
+// caller returns the authenticated principal, writing the error response itself.
+// Keys cannot be managed with an API key so that a leaked key cannot mint more.
+func (a *APIKeyAPI) caller(w http.ResponseWriter, r *http.Request) (*auth.Principal, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		response.SendProblem(w, r, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+	if principal.Method == auth.MethodAPIKey {
+		response.SendProblem(w, r, http.StatusForbidden, "API keys cannot be managed with an API key")
+		return nil, false
+	}
+	return principal, true
+}
+
+// decodeRequest reads and validates a JSON request body, writing the error response itself.
+// It reports whether the handler should continue.
+func (a *APIKeyAPI) decodeRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	if err != nil {
+		a.logger.Error("error while reading request body", err)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
+		return false
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		a.logger.Error("error while parsing request body", err)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	if errs := validation.ValidateStruct(dst); len(errs) > 0 {
+		response.SendValidationProblem(w, r, errs)
+		return false
+	}
+
+	return true
+}
+
+func (a *APIKeyAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
+	resp, err := json.Marshal(data)
+	if err != nil {
+		a.logger.Error("error while marshalling response", err)
+		response.Error(w, http.StatusInternalServerError, response.InternalErrorMessage)
+		return
+	}
+	response.SendResponseRaw(w, status, resp)
+}
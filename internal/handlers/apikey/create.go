@@ -0,0 +1,49 @@
+// Package apikey provides HTTP handlers for API key management.
+// It includes endpoints for creating, listing and revoking API keys.
+package apikey
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+)
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Schemes
+// @Description Create an API key owned by the caller. Each scope must be a permission the caller holds. The key is only returned in this response.
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param apiKey body model.CreateAPIKeyRequest true "API key"
+// @Success      201  {object}  model.StandardResponse{data=model.CreateAPIKeyResponse}
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/api-keys [post]
+// CreateAPIKey handles HTTP requests for creating API keys.
+func (a *APIKeyAPI) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	principal, ok := a.caller(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.CreateAPIKeyRequest
+	if !a.decodeRequest(w, r, &req) {
+		return
+	}
+
+	key, err := a.apiKeySrv.CreateAPIKey(r.Context(), principal, req)
+	if err != nil {
+		response.SendError(w, r, a.logger, err)
+		return
+	}
+	a.sendJSONResponse(w, model.StandardResponse{
+		IsSuccess: true,
+		Message:   "API key created, store it now as it will not be shown again",
+		Data:      key,
+	}, http.StatusCreated)
+}
@@ -0,0 +1,137 @@
+package apikey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/apikey"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/apikey/mocks"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var mockAPIKeyService = new(mocks.APIKeyServiceInterface)
+
+var alice = &auth.Principal{
+	Subject:     "alice",
+	Permissions: []auth.Permission{auth.PermProductWrite},
+	Method:      auth.MethodJWT,
+}
+
+// newAPIKeyRouter registers the API key API behind a fixed principal
+func newAPIKeyRouter(api *APIKeyAPI, principal *auth.Principal) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	api.RegisterHandlers(router)
+	return router
+}
+
+func TestAPIKeyAPI_CreateAPIKey(t *testing.T) {
+	api := NewAPIKeyAPI(logger.NewLogger(logger.DefaultOptions()), mockAPIKeyService)
+	router := newAPIKeyRouter(api, alice)
+	validReq := model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{string(auth.PermProductWrite)}}
+
+	t.Run("Successful Creation", func(t *testing.T) {
+		body, _ := json.Marshal(validReq)
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("CreateAPIKey", mock.Anything, alice, validReq).Return(&model.CreateAPIKeyResponse{
+			APIKeyResponse: model.APIKeyResponse{ID: 1, Name: "ci", Prefix: "ak_0123456789ab", Owner: "alice", Scopes: validReq.Scopes},
+			Key:            "ak_0123456789ab.secret",
+		}, nil).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "ak_0123456789ab.secret")
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Scope Not Granted", func(t *testing.T) {
+		body, _ := json.Marshal(validReq)
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("CreateAPIKey", mock.Anything, alice, validReq).
+			Return(nil, fmt.Errorf("%w: %q", apikey.ErrScopeNotGranted, "cache:flush")).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Scope", func(t *testing.T) {
+		body, _ := json.Marshal(validReq)
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("CreateAPIKey", mock.Anything, alice, validReq).Return(nil, apikey.ErrUnknownScope).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		body, _ := json.Marshal(validReq)
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("CreateAPIKey", mock.Anything, alice, validReq).Return(nil, errors.New("db error")).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		body, _ := json.Marshal(model.CreateAPIKeyRequest{})
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("API Key Callers Are Rejected", func(t *testing.T) {
+		keyCaller := &auth.Principal{Subject: "alice", Method: auth.MethodAPIKey}
+		body, _ := json.Marshal(validReq)
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		newAPIKeyRouter(api, keyCaller).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		body, _ := json.Marshal(validReq)
+		req := httptest.NewRequest(http.MethodPost, APIKeysPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		newAPIKeyRouter(api, nil).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
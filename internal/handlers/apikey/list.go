@@ -0,0 +1,50 @@
+// Package apikey provides HTTP handlers for API key management.
+// It includes endpoints for creating, listing and revoking API keys.
+package apikey
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+)
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Schemes
+// @Description List the caller's API keys, including revoked and expired ones. Secrets are never returned. Callers with user:manage may list another user's keys with the owner parameter.
+// @Tags APIKey
+// @Produce json
+// @Param owner query string false "Username of the key owner"
+// @Success      200  {object}  model.StandardResponse{data=[]model.APIKeyResponse}
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/api-keys [get]
+// ListAPIKeys handles HTTP requests for listing API keys.
+func (a *APIKeyAPI) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	principal, ok := a.caller(w, r)
+	if !ok {
+		return
+	}
+
+	owner := principal.Subject
+	if v := r.URL.Query().Get("owner"); v != "" && v != owner {
+		if !principal.HasPermission(auth.PermUserManage) {
+			response.SendProblem(w, r, http.StatusForbidden, "Forbidden: missing permission "+string(auth.PermUserManage))
+			return
+		}
+		owner = v
+	}
+
+	keys, err := a.apiKeySrv.ListAPIKeys(r.Context(), owner)
+	if err != nil {
+		response.SendError(w, r, a.logger, err)
+		return
+	}
+
+	a.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Data: keys}, http.StatusOK)
+}
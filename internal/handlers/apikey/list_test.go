@@ -0,0 +1,61 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyAPI_ListAPIKeys(t *testing.T) {
+	api := NewAPIKeyAPI(logger.NewLogger(logger.DefaultOptions()), mockAPIKeyService)
+	router := newAPIKeyRouter(api, alice)
+
+	t.Run("Own Keys", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, APIKeysPath, http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("ListAPIKeys", mock.Anything, "alice").
+			Return([]model.APIKeyResponse{{ID: 1, Name: "ci", Prefix: "ak_0123456789ab", Owner: "alice"}}, nil).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+
+		var response struct {
+			Data []model.APIKeyResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Len(t, response.Data, 1)
+	})
+
+	t.Run("Other Owner Requires User Manage", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, APIKeysPath+"?owner=bob", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Admin Lists Other Owner", func(t *testing.T) {
+		admin := &auth.Principal{Subject: "admin", Permissions: []auth.Permission{auth.PermUserManage}}
+		req := httptest.NewRequest(http.MethodGet, APIKeysPath+"?owner=nobody", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("ListAPIKeys", mock.Anything, "nobody").Return(nil, repository.ErrUserNotFound).Once()
+
+		newAPIKeyRouter(api, admin).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,36 @@
+// Package model provides data structures for API key operations.
+// It includes request and response models for the API key endpoints.
+package model
+
+import "time"
+
+type StandardResponse struct {
+	IsSuccess bool   `json:"success"`
+	Message   string `json:"message"`
+	Data      any    `json:"data"`
+}
+
+// Scopes are permission names such as "product:write"; "*" grants everything the owner may do
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,max=16,dive,required,max=64"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Owner      string     `json:"owner"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPIKeyResponse carries the full key, which is only ever returned once
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
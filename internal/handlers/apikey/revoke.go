@@ -0,0 +1,47 @@
+// Package apikey provides HTTP handlers for API key management.
+// It includes endpoints for creating, listing and revoking API keys.
+package apikey
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/gorilla/mux"
+)
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Schemes
+// @Description Revoke one of the caller's API keys. Callers with user:manage may revoke any key.
+// @Tags APIKey
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success      200  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/api-keys/{id} [delete]
+// RevokeAPIKey handles HTTP requests for revoking API keys.
+func (a *APIKeyAPI) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	principal, ok := a.caller(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	if err := a.apiKeySrv.RevokeAPIKey(r.Context(), principal, id); err != nil {
+		response.SendError(w, r, a.logger, err)
+		return
+	}
+	a.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Message: "API key revoked"}, http.StatusOK)
+}
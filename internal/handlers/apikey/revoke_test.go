@@ -0,0 +1,63 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyAPI_RevokeAPIKey(t *testing.T) {
+	api := NewAPIKeyAPI(logger.NewLogger(logger.DefaultOptions()), mockAPIKeyService)
+	router := newAPIKeyRouter(api, alice)
+
+	t.Run("Successful Revocation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api-keys/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("RevokeAPIKey", mock.Anything, alice, 1).Return(nil).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api-keys/2", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("RevokeAPIKey", mock.Anything, alice, 2).Return(repository.ErrAPIKeyNotFound).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api-keys/3", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockAPIKeyService.On("RevokeAPIKey", mock.Anything, alice, 3).Return(errors.New("db error")).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockAPIKeyService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api-keys/abc", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,77 @@
+// Package auth provides HTTP handlers for authentication.
+// It includes endpoints for logging in, refreshing and revoking tokens.
+package auth
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	authService "github.com/MitulShah1/golang-rest-api-template/internal/services/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// LoginPath is the path for exchanging credentials for tokens
+	LoginPath = "/auth/login"
+	// RefreshPath is the path for rotating a refresh token
+	RefreshPath = "/auth/refresh"
+	// LogoutPath is the path for revoking a refresh token
+	LogoutPath = "/auth/logout"
+)
+
+type AuthAPI struct {
+	logger  *logger.Logger
+	authSrv authService.AuthServiceInterface
+}
+
+func NewAuthAPI(logger *logger.Logger, authSrv authService.AuthServiceInterface) *AuthAPI {
+	return &AuthAPI{
+		logger:  logger,
+		authSrv: authSrv,
+	}
+}
+
+func (a *AuthAPI) RegisterHandlers(router *mux.Router) {
+	router.HandleFunc(LoginPath, a.Login).Methods(http.MethodPost)
+	router.HandleFunc(RefreshPath, a.Refresh).Methods(http.MethodPost)
+	router.HandleFunc(LogoutPath, a.Logout).Methods(http.MethodPost)
+}
+
+// decodeRequest reads and validates a JSON request body, writing the error response itself.
+// It reports whether the handler should continue.
+func (a *AuthAPI) decodeRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	if err != nil {
+		a.logger.Error("error while reading request body", err)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
+		return false
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		a.logger.Error("error while parsing request body", err)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	if errs := validation.ValidateStruct(dst); len(errs) > 0 {
+		response.SendValidationProblem(w, r, errs)
+		return false
+	}
+
+	return true
+}
+
+func (a *AuthAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
+	resp, err := json.Marshal(data)
+	if err != nil {
+		a.logger.Error("error while marshalling response", err)
+		response.Error(w, http.StatusInternalServerError, response.InternalErrorMessage)
+		return
+	}
+	response.SendResponseRaw(w, status, resp)
+}
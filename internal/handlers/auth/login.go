@@ -0,0 +1,39 @@
+// Package auth provides HTTP handlers for authentication.
+// It includes endpoints for logging in, refreshing and revoking tokens.
+package auth
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+)
+
+// Login godoc
+// @Summary Log in
+// @Schemes
+// @Description Exchange a username and password for an access token and a refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body model.LoginRequest true "Credentials"
+// @Success      200  {object}  model.StandardResponse{data=model.TokenResponse}
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /auth/login [post]
+// Login handles HTTP requests for authenticating with a username and password.
+func (a *AuthAPI) Login(w http.ResponseWriter, r *http.Request) {
+	var req model.LoginRequest
+	if !a.decodeRequest(w, r, &req) {
+		return
+	}
+
+	tokens, err := a.authSrv.Login(r.Context(), req)
+	if err != nil {
+		response.SendError(w, r, a.logger, err)
+		return
+	}
+
+	a.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Data: tokens}, http.StatusOK)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/auth/mocks"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var mockAuthService = new(mocks.AuthServiceInterface)
+
+func TestAuthAPI_Login(t *testing.T) {
+	api := NewAuthAPI(logger.NewLogger(logger.DefaultOptions()), mockAuthService)
+
+	t.Run("Successful Login", func(t *testing.T) {
+		loginReq := model.LoginRequest{Username: "admin", Password: "password"}
+		body, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest(http.MethodPost, LoginPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		tokens := &model.TokenResponse{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}
+		mockAuthService.On("Login", context.Background(), loginReq).Return(tokens, nil).Once()
+
+		api.Login(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAuthService.AssertExpectations(t)
+
+		var response struct {
+			IsSuccess bool                `json:"success"`
+			Data      model.TokenResponse `json:"data"`
+		}
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.True(t, response.IsSuccess)
+		assert.Equal(t, *tokens, response.Data)
+	})
+
+	t.Run("Invalid Credentials", func(t *testing.T) {
+		loginReq := model.LoginRequest{Username: "admin", Password: "wrong"}
+		body, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest(http.MethodPost, LoginPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAuthService.On("Login", context.Background(), loginReq).Return(nil, auth.ErrInvalidCredentials).Once()
+
+		api.Login(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Locked Account", func(t *testing.T) {
+		loginReq := model.LoginRequest{Username: "admin", Password: "password"}
+		body, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest(http.MethodPost, LoginPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAuthService.On("Login", context.Background(), loginReq).Return(nil, auth.ErrAccountLocked).Once()
+
+		api.Login(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "locked")
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		loginReq := model.LoginRequest{Username: "admin", Password: "password"}
+		body, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest(http.MethodPost, LoginPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAuthService.On("Login", context.Background(), loginReq).Return(nil, errors.New("redis down")).Once()
+
+		api.Login(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, LoginPath, bytes.NewReader([]byte(`{"username":"admin"}`)))
+		w := httptest.NewRecorder()
+
+		api.Login(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, LoginPath, bytes.NewReader([]byte(`{`)))
+		w := httptest.NewRecorder()
+
+		api.Login(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
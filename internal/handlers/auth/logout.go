@@ -0,0 +1,38 @@
+// Package auth provides HTTP handlers for authentication.
+// It includes endpoints for logging in, refreshing and revoking tokens.
+package auth
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+)
+
+// Logout godoc
+// @Summary Log out
+// @Schemes
+// @Description Revoke a refresh token and every token rotated from the same login
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token body model.RefreshRequest true "Refresh token"
+// @Success      200  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /auth/logout [post]
+// Logout handles HTTP requests for revoking a refresh token.
+func (a *AuthAPI) Logout(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if !a.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := a.authSrv.Logout(r.Context(), req); err != nil {
+		response.SendError(w, r, a.logger, err)
+		return
+	}
+
+	a.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Message: "Logged out"}, http.StatusOK)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthAPI_Logout(t *testing.T) {
+	api := NewAuthAPI(logger.NewLogger(logger.DefaultOptions()), mockAuthService)
+
+	t.Run("Successful Logout", func(t *testing.T) {
+		logoutReq := model.RefreshRequest{RefreshToken: "refresh"}
+		body, _ := json.Marshal(logoutReq)
+		req := httptest.NewRequest(http.MethodPost, LogoutPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAuthService.On("Logout", context.Background(), logoutReq).Return(nil).Once()
+
+		api.Logout(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		logoutReq := model.RefreshRequest{RefreshToken: "unknown"}
+		body, _ := json.Marshal(logoutReq)
+		req := httptest.NewRequest(http.MethodPost, LogoutPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAuthService.On("Logout", context.Background(), logoutReq).Return(auth.ErrInvalidRefreshToken).Once()
+
+		api.Logout(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,25 @@
+// Package model provides data structures for authentication operations.
+// It includes request and response models for the auth API endpoints.
+package model
+
+type StandardResponse struct {
+	IsSuccess bool   `json:"success"`
+	Message   string `json:"message"`
+	Data      any    `json:"data"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int64  `json:"expiresIn"`
+}
@@ -0,0 +1,40 @@
+// Package auth provides HTTP handlers for authentication.
+// It includes endpoints for logging in, refreshing and revoking tokens.
+package auth
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+)
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Schemes
+// @Description Exchange a refresh token for a new access token and a rotated refresh token.
+// @Description Presenting a refresh token that was already used revokes the whole session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token body model.RefreshRequest true "Refresh token"
+// @Success      200  {object}  model.StandardResponse{data=model.TokenResponse}
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /auth/refresh [post]
+// Refresh handles HTTP requests for rotating a refresh token.
+func (a *AuthAPI) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if !a.decodeRequest(w, r, &req) {
+		return
+	}
+
+	tokens, err := a.authSrv.Refresh(r.Context(), req)
+	if err != nil {
+		response.SendError(w, r, a.logger, err)
+		return
+	}
+
+	a.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Data: tokens}, http.StatusOK)
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthAPI_Refresh(t *testing.T) {
+	api := NewAuthAPI(logger.NewLogger(logger.DefaultOptions()), mockAuthService)
+
+	t.Run("Successful Refresh", func(t *testing.T) {
+		refreshReq := model.RefreshRequest{RefreshToken: "refresh"}
+		body, _ := json.Marshal(refreshReq)
+		req := httptest.NewRequest(http.MethodPost, RefreshPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		tokens := &model.TokenResponse{AccessToken: "access", RefreshToken: "next", TokenType: "Bearer", ExpiresIn: 900}
+		mockAuthService.On("Refresh", context.Background(), refreshReq).Return(tokens, nil).Once()
+
+		api.Refresh(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Reused Token", func(t *testing.T) {
+		refreshReq := model.RefreshRequest{RefreshToken: "used"}
+		body, _ := json.Marshal(refreshReq)
+		req := httptest.NewRequest(http.MethodPost, RefreshPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockAuthService.On("Refresh", context.Background(), refreshReq).Return(nil, auth.ErrRefreshTokenReused).Once()
+
+		api.Refresh(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, RefreshPath, bytes.NewReader([]byte(`{}`)))
+		w := httptest.NewRecorder()
+
+		api.Refresh(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
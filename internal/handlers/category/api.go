@@ -6,10 +6,11 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
 	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/internal/services/category"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/middleware"
 	"github.com/gorilla/mux"
 )
 
@@ -22,44 +23,62 @@ const (
 	UpdateCategoryPath = "/update-category/{id}"
 	// DeleteCategoryPath is the path for deleting a category
 	DeleteCategoryPath = "/category/{id}"
+	// CategoryChildrenPath is the path for listing the direct children of a category
+	CategoryChildrenPath = "/category/{id}/children"
+	// CategoryBreadcrumbPath is the path for getting the ancestors of a category
+	CategoryBreadcrumbPath = "/category/{id}/path"
+	// RestoreCategoryPath is the path for restoring a deleted category
+	RestoreCategoryPath = "/category/{id}/restore"
+	// CategoryTreePath is the path for getting the nested category tree
+	CategoryTreePath = "/categories/tree"
 )
 
 type CategoryAPI struct {
 	logger  *logger.Logger
 	catSrvc category.CategoryServiceInterface
+	// requireIfMatch refuses updates and deletes without an If-Match header
+	requireIfMatch bool
 }
 
-func NewCategoryAPI(logger *logger.Logger, catSrvc category.CategoryServiceInterface) *CategoryAPI {
+// NewCategoryAPI returns the category handlers. With requireIfMatch, updates
+// and deletes must send the ETag of the category in an If-Match header.
+func NewCategoryAPI(logger *logger.Logger, catSrvc category.CategoryServiceInterface, requireIfMatch bool) *CategoryAPI {
 	return &CategoryAPI{
-		logger:  logger,
-		catSrvc: catSrvc,
+		logger:         logger,
+		catSrvc:        catSrvc,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
 func (c *CategoryAPI) RegisterHandlers(router *mux.Router) {
-	router.HandleFunc(CreateCategoryPath, c.CreateCategoryDetail).Methods(http.MethodPost)
-	router.HandleFunc(CategoryByIDPath, c.GetCategoryByID).Methods(http.MethodGet)
-	router.HandleFunc(UpdateCategoryPath, c.UpdateCategory).Methods(http.MethodPut)
-	router.HandleFunc(DeleteCategoryPath, c.DeleteCategory).Methods(http.MethodDelete)
+	router.HandleFunc(CreateCategoryPath, middleware.RequirePermission(auth.PermCategoryWrite, c.CreateCategoryDetail)).Methods(http.MethodPost)
+	router.HandleFunc(CategoryByIDPath, middleware.IncludeDeleted(auth.PermCategoryDelete, c.GetCategoryByID)).Methods(http.MethodGet)
+	router.HandleFunc(UpdateCategoryPath, middleware.RequirePermission(auth.PermCategoryWrite, c.UpdateCategory)).Methods(http.MethodPut)
+	router.HandleFunc(DeleteCategoryPath, middleware.RequirePermission(auth.PermCategoryDelete, c.DeleteCategory)).Methods(http.MethodDelete)
+	router.HandleFunc(RestoreCategoryPath, middleware.RequirePermission(auth.PermCategoryDelete, c.RestoreCategory)).Methods(http.MethodPost)
+	router.HandleFunc(CategoryChildrenPath, c.GetCategoryChildren).Methods(http.MethodGet)
+	router.HandleFunc(CategoryBreadcrumbPath, c.GetCategoryPath).Methods(http.MethodGet)
+	router.HandleFunc(CategoryTreePath, c.GetCategoryTree).Methods(http.MethodGet)
 }
 
-func (c *CategoryAPI) sendErrorResponse(w http.ResponseWriter, message string, status int) {
-	res := model.StandardResponse{Message: message}
-	resp, err := json.Marshal(res)
+func (c *CategoryAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
+	resp, err := json.Marshal(data)
 	if err != nil {
-		c.logger.Error("error while marshalling error response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		c.logger.Error("error while marshalling response", err)
+		response.Error(w, http.StatusInternalServerError, response.InternalErrorMessage)
 		return
 	}
 	response.SendResponseRaw(w, status, resp)
 }
 
-func (c *CategoryAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
-	resp, err := json.Marshal(data)
+// ifMatch returns the version the If-Match header asks to write at, 0 meaning
+// any. When the header is missing but required, or cannot match, it answers
+// the request itself and returns false.
+func (c *CategoryAPI) ifMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	version, err := response.IfMatch(r, c.requireIfMatch)
 	if err != nil {
-		c.logger.Error("error while marshalling response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
-		return
+		response.SendError(w, r, c.logger, err)
+		return 0, false
 	}
-	response.SendResponseRaw(w, status, resp)
+	return version, true
 }
@@ -21,10 +21,11 @@ import (
 // @Produce json
 // @Param category body model.CreateCategoryRequest true "Category"
 // @Success 	 200  {object}  model.CreateCategoryResponse
-// @Failure      401  {object}  model.StandardResponse
-// @Failure      400  {object}  model.StandardResponse
-// @Failure      404  {string} string "404 page not found"
-// @Failure      500  {object}  model.StandardResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      400  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
 // @Router /v1/create-category [post]
 // CreateCategoryDetail handles HTTP requests for creating new categories.
 // It validates the request, creates the category, and returns the result.
@@ -37,30 +38,27 @@ func (c *CategoryAPI) CreateCategoryDetail(w http.ResponseWriter, r *http.Reques
 	defer r.Body.Close()
 	if err != nil {
 		c.logger.Error("error while reading request body", err)
-		response.SendResponseRaw(w, http.StatusBadRequest, nil)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
 		return
 	}
 
 	var req model.CreateCategoryRequest
 	if err = json.Unmarshal(body, &req); err != nil {
 		c.logger.Error("error while parsing request body", err)
-		c.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Validate request
 	if errors := validation.ValidateStruct(req); len(errors) > 0 {
-		res.Message = "Validation error"
-		res.Data = errors
-		c.sendJSONResponse(w, res, http.StatusBadRequest)
+		response.SendValidationProblem(w, r, errors)
 		return
 	}
 
 	// Create Category
 	cateID, err := c.catSrvc.CreateCategory(ctx, req)
 	if err != nil {
-		c.logger.Error("error while creating category", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendError(w, r, c.logger, err)
 		return
 	}
 
@@ -74,7 +72,7 @@ func (c *CategoryAPI) CreateCategoryDetail(w http.ResponseWriter, r *http.Reques
 	resp, err := json.Marshal(res)
 	if err != nil {
 		c.logger.Error("error while marshalling response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendProblem(w, r, http.StatusInternalServerError, response.InternalErrorMessage)
 		return
 	}
 
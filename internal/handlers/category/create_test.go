@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/internal/services/category/mocks"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/stretchr/testify/assert"
@@ -97,10 +98,10 @@ func TestCategoryAPI_CreateCategoryDetail(t *testing.T) {
 		api.CreateCategoryDetail(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response model.StandardResponse
-		err := json.NewDecoder(w.Body).Decode(&response)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
 		assert.NoError(t, err)
-		assert.Equal(t, "Validation error", response.Message)
-		assert.NotNil(t, response.Data)
+		assert.Equal(t, response.ValidationProblemType, problem.Type)
+		assert.NotEmpty(t, problem.Errors)
 	})
 }
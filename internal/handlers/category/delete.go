@@ -19,46 +19,51 @@ import (
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the category as last read; required when the server requires If-Match"
+// @Param policy query string false "What happens to products and subcategories: restrict (default) refuses when there are any, cascade deletes them, reassign moves them to the parent category" Enums(restrict, cascade, reassign)
 // @Success 	 200  {object}  model.StandardResponse
-// @Failure      401  {object}  model.StandardResponse
-// @Failure      400  {object}  model.StandardResponse
-// @Failure      404  {string} string "404 page not found"
-// @Failure      500  {object}  model.StandardResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      400  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      409  {object}  response.Problem
+// @Failure      412  {object}  response.Problem
+// @Failure      428  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
 // @Router /v1/category/{id} [DELETE]
 // DeleteCategory handles HTTP requests for deleting categories by ID.
-// It validates the ID and removes the category from the database.
+// It validates the ID and removes the category from the database, handling
+// its products and subcategories as the policy query parameter says, provided
+// it is still at the version of the ETag in the If-Match header, if any.
 func (c *CategoryAPI) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	res := model.StandardResponse{}
 
 	categoryID := mux.Vars(r)["id"]
 	if categoryID == "" {
-		c.sendErrorResponse(w, "Category ID is required", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Category ID is required")
 		return
 	}
 
 	cid, err := strconv.Atoi(categoryID)
 	if err != nil || cid <= 0 {
-		c.sendErrorResponse(w, "Invalid category ID", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid category ID")
 		return
 	}
 
-	category, err := c.catSrvc.GetCategoryByID(ctx, cid)
+	policy, err := model.ParseDeletePolicy(r.URL.Query().Get("policy"))
 	if err != nil {
-		c.logger.Error("error while fetching category details", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendError(w, r, c.logger, err)
 		return
 	}
 
-	if category == nil {
-		res.Message = "Category not found"
-		c.sendJSONResponse(w, res, http.StatusOK)
+	version, ok := c.ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	if err := c.catSrvc.DeleteCategory(ctx, cid); err != nil {
-		c.logger.Error("error while delete category", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+	if err := c.catSrvc.DeleteCategory(ctx, cid, policy, version); err != nil {
+		response.SendError(w, r, c.logger, err)
 		return
 	}
 
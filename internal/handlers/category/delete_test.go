@@ -8,7 +8,8 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
-	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -52,47 +53,69 @@ func TestCategoryAPI_DeleteCategory(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
-	t.Run("GetCategoryByID Error", func(t *testing.T) {
+	t.Run("Category Not Found", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/categories/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(nil, errors.New("database error")).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, 1, model.DeleteRestrict, 0).Return(repository.ErrCategoryNotFound).Once()
 
 		api.DeleteCategory(w, req)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		assert.NoError(t, err)
+		assert.Equal(t, "Category not found", problem.Detail)
 		mockCategoryService.AssertExpectations(t)
 	})
 
-	t.Run("Category Not Found", func(t *testing.T) {
+	t.Run("Delete Category Error", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/categories/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(nil, nil).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, 1, model.DeleteRestrict, 0).Return(errors.New("delete error")).Once()
 
 		api.DeleteCategory(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-		var response model.StandardResponse
-		err := json.NewDecoder(w.Body).Decode(&response)
-		assert.NoError(t, err)
-		assert.Equal(t, "Category not found", response.Message)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockCategoryService.AssertExpectations(t)
 	})
 
-	t.Run("Delete Category Error", func(t *testing.T) {
+	t.Run("Invalid Delete Policy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/categories/1?policy=orphan", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		api.DeleteCategory(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Category In Use", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/categories/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(&sqlModel.Category{ID: 1}, nil).Once()
-		mockCategoryService.On("DeleteCategory", mock.Anything, 1).Return(errors.New("delete error")).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, 1, model.DeleteRestrict, 0).Return(repository.ErrCategoryInUse).Once()
 
 		api.DeleteCategory(w, req)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Cascade Delete", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/categories/1?policy=cascade", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockCategoryService.On("DeleteCategory", mock.Anything, 1, model.DeleteCascade, 0).Return(nil).Once()
+
+		api.DeleteCategory(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
 		mockCategoryService.AssertExpectations(t)
 	})
 
@@ -101,8 +124,7 @@ func TestCategoryAPI_DeleteCategory(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(&sqlModel.Category{ID: 1}, nil).Once()
-		mockCategoryService.On("DeleteCategory", mock.Anything, 1).Return(nil).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, 1, model.DeleteRestrict, 0).Return(nil).Once()
 
 		api.DeleteCategory(w, req)
 
@@ -114,4 +136,29 @@ func TestCategoryAPI_DeleteCategory(t *testing.T) {
 		assert.Equal(t, "Category deleted successfully", response.Message)
 		mockCategoryService.AssertExpectations(t)
 	})
+
+	t.Run("Stale If-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/categories/1", http.NoBody)
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockCategoryService.On("DeleteCategory", mock.Anything, 1, model.DeleteRestrict, 1).Return(repository.ErrVersionMismatch).Once()
+
+		api.DeleteCategory(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Missing Required If-Match", func(t *testing.T) {
+		strict := &CategoryAPI{catSrvc: mockCategoryService, logger: testLogger, requireIfMatch: true}
+		req := httptest.NewRequest(http.MethodDelete, "/categories/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		strict.DeleteCategory(w, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
 }
@@ -19,9 +19,13 @@ import (
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param include_deleted query bool false "Also return the category when it is soft deleted; needs category:delete"
+// @Param If-None-Match header string false "ETag of a cached copy; answered with 304 while it is current"
 // @Success 200 {object} model.CategoryByIDResponse
-// @Failure 400 {object} model.StandardResponse
-// @Failure 404 {object} model.StandardResponse
+// @Header 200 {string} ETag "Version of the category, for If-Match and If-None-Match"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.Problem
+// @Failure 404 {object} response.Problem
 // @Router /category/{id} [get]
 func (c *CategoryAPI) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -29,33 +33,36 @@ func (c *CategoryAPI) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 
 	categoryID := mux.Vars(r)["id"]
 	if categoryID == "" {
-		c.sendErrorResponse(w, "Category ID is required", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Category ID is required")
 		return
 	}
 
 	cid, err := strconv.Atoi(categoryID)
 	if err != nil || cid <= 0 {
-		c.sendErrorResponse(w, "Invalid category ID", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid category ID")
 		return
 	}
 
 	category, err := c.catSrvc.GetCategoryByID(ctx, cid)
 	if err != nil {
-		c.logger.Error("error while fetching category details", err, "category_id", cid)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendError(w, r, c.logger, err)
 		return
 	}
 
-	res.IsSuccess = true
-	if category == nil {
-		res.Message = "Category not found"
+	etag := response.ETag(category.Version)
+	if response.NotModified(r, etag) {
+		response.SendNotModified(w, etag)
+		return
 	}
+	w.Header().Set("ETag", etag)
+
+	res.IsSuccess = true
 	res.Data = category
 
 	resp, err := json.Marshal(res)
 	if err != nil {
 		c.logger.Error("error while marshalling response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendProblem(w, r, http.StatusInternalServerError, response.InternalErrorMessage)
 		return
 	}
 
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
 	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -55,16 +57,15 @@ func TestCategoryAPI_GetCategoryById(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(nil, nil).Once()
+		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(nil, repository.ErrCategoryNotFound).Once()
 
 		api.GetCategoryByID(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-		var response model.StandardResponse
-		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
 		assert.NoError(t, err)
-		assert.True(t, response.IsSuccess)
-		assert.Equal(t, "Category not found", response.Message)
+		assert.Equal(t, "Category not found", problem.Detail)
 		mockCategoryService.AssertExpectations(t)
 	})
 
@@ -97,4 +98,24 @@ func TestCategoryAPI_GetCategoryById(t *testing.T) {
 		assert.IsType(t, data, response.Data)
 		mockCategoryService.AssertExpectations(t)
 	})
+
+	t.Run("ETag And Not Modified", func(t *testing.T) {
+		mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(&sqlModel.Category{ID: 1, Version: 2}, nil).Twice()
+
+		req := httptest.NewRequest(http.MethodGet, "/categories/1", http.NoBody)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		req.Header.Set("If-None-Match", `"1"`)
+		w := httptest.NewRecorder()
+		api.GetCategoryByID(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+		req = httptest.NewRequest(http.MethodGet, "/categories/1", http.NoBody)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		req.Header.Set("If-None-Match", `"1", "2"`)
+		w = httptest.NewRecorder()
+		api.GetCategoryByID(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,129 @@
+// Package category provides HTTP handlers for category-related operations.
+// It includes endpoints for creating, reading, updating, and deleting categories.
+package category
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/gorilla/mux"
+)
+
+// GetCategoryChildren godoc
+// @Summary Get category children
+// @Description Get the direct subcategories of a category
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} model.StandardResponse
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /v1/category/{id}/children [get]
+// GetCategoryChildren handles HTTP requests for listing the direct children of a category.
+func (c *CategoryAPI) GetCategoryChildren(w http.ResponseWriter, r *http.Request) {
+	c.sendCategoryList(w, r, c.catSrvc.GetCategoryChildren)
+}
+
+// GetCategoryPath godoc
+// @Summary Get category breadcrumb path
+// @Description Get the ancestors of a category from the root down to the category itself
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} model.StandardResponse
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /v1/category/{id}/path [get]
+// GetCategoryPath handles HTTP requests for the breadcrumb path of a category.
+func (c *CategoryAPI) GetCategoryPath(w http.ResponseWriter, r *http.Request) {
+	c.sendCategoryList(w, r, c.catSrvc.GetCategoryPath)
+}
+
+// GetCategoryTree godoc
+// @Summary Get category tree
+// @Description Get the nested category tree, optionally starting at a category and limited in depth
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param root_id query int false "Root category ID (defaults to all top-level categories)"
+// @Param depth query int false "Number of levels to return, counting the root (0 = all)"
+// @Success 200 {object} model.StandardResponse
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /v1/categories/tree [get]
+// GetCategoryTree handles HTTP requests for the nested category tree used by navigation menus.
+func (c *CategoryAPI) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res := model.StandardResponse{}
+	q := r.URL.Query()
+
+	var rootID *int
+	if v := q.Get("root_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid root category ID")
+			return
+		}
+		rootID = &id
+	}
+
+	depth := 0
+	if v := q.Get("depth"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 0 {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid depth")
+			return
+		}
+		depth = d
+	}
+
+	tree, err := c.catSrvc.GetCategoryTree(ctx, rootID, depth)
+	if err != nil {
+		response.SendError(w, r, c.logger, err)
+		return
+	}
+
+	res.IsSuccess = true
+	res.Data = tree
+	c.sendJSONResponse(w, res, http.StatusOK)
+}
+
+// sendCategoryList validates the category ID in the path, loads the related
+// categories with fetch and writes them as the response.
+func (c *CategoryAPI) sendCategoryList(w http.ResponseWriter, r *http.Request, fetch func(context.Context, int) ([]sqlModel.Category, error)) {
+	res := model.StandardResponse{}
+
+	categoryID := mux.Vars(r)["id"]
+	if categoryID == "" {
+		response.SendProblem(w, r, http.StatusBadRequest, "Category ID is required")
+		return
+	}
+
+	cid, err := strconv.Atoi(categoryID)
+	if err != nil || cid <= 0 {
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	categories, err := fetch(r.Context(), cid)
+	if err != nil {
+		response.SendError(w, r, c.logger, err)
+		return
+	}
+
+	res.IsSuccess = true
+	res.Data = categories
+	c.sendJSONResponse(w, res, http.StatusOK)
+}
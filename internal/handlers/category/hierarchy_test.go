@@ -0,0 +1,169 @@
+package category
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryAPI_GetCategoryChildren(t *testing.T) {
+	testLogger := logger.NewLogger(logger.DefaultOptions())
+	api := &CategoryAPI{
+		catSrvc: mockCategoryService,
+		logger:  testLogger,
+	}
+
+	t.Run("Invalid Category ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/category/abc/children", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+		api.GetCategoryChildren(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Category Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/category/99/children", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "99"})
+		mockCategoryService.On("GetCategoryChildren", mock.Anything, 99).Return(nil, repository.ErrCategoryNotFound).Once()
+
+		api.GetCategoryChildren(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/category/1/children", http.NoBody)
+		w := httptest.NewRecorder()
+
+		parentID := 1
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockCategoryService.On("GetCategoryChildren", mock.Anything, 1).
+			Return([]sqlModel.Category{{ID: 2, Name: "Child", ParentID: &parentID}}, nil).Once()
+
+		api.GetCategoryChildren(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response model.StandardResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.True(t, response.IsSuccess)
+		assert.Len(t, response.Data, 1)
+		mockCategoryService.AssertExpectations(t)
+	})
+}
+
+func TestCategoryAPI_GetCategoryPath(t *testing.T) {
+	testLogger := logger.NewLogger(logger.DefaultOptions())
+	api := &CategoryAPI{
+		catSrvc: mockCategoryService,
+		logger:  testLogger,
+	}
+
+	t.Run("Service Error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/category/3/path", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "3"})
+		mockCategoryService.On("GetCategoryPath", mock.Anything, 3).Return(nil, errors.New("database error")).Once()
+
+		api.GetCategoryPath(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/category/2/path", http.NoBody)
+		w := httptest.NewRecorder()
+
+		parentID := 1
+		req = mux.SetURLVars(req, map[string]string{"id": "2"})
+		mockCategoryService.On("GetCategoryPath", mock.Anything, 2).
+			Return([]sqlModel.Category{{ID: 1, Name: "Root"}, {ID: 2, Name: "Child", ParentID: &parentID}}, nil).Once()
+
+		api.GetCategoryPath(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response model.StandardResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Len(t, response.Data, 2)
+		mockCategoryService.AssertExpectations(t)
+	})
+}
+
+func TestCategoryAPI_GetCategoryTree(t *testing.T) {
+	testLogger := logger.NewLogger(logger.DefaultOptions())
+	api := &CategoryAPI{
+		catSrvc: mockCategoryService,
+		logger:  testLogger,
+	}
+
+	t.Run("Invalid Depth", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/tree?depth=-1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.GetCategoryTree(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Root ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/tree?root_id=x", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.GetCategoryTree(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Root Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/tree?root_id=42", http.NoBody)
+		w := httptest.NewRecorder()
+
+		rootID := 42
+		mockCategoryService.On("GetCategoryTree", mock.Anything, &rootID, 0).Return(nil, repository.ErrCategoryNotFound).Once()
+
+		api.GetCategoryTree(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/tree?depth=2", http.NoBody)
+		w := httptest.NewRecorder()
+
+		tree := []*model.CategoryTreeNode{{
+			ID:       1,
+			Name:     "Root",
+			Children: []*model.CategoryTreeNode{{ID: 2, Name: "Child", Children: []*model.CategoryTreeNode{}}},
+		}}
+		mockCategoryService.On("GetCategoryTree", mock.Anything, (*int)(nil), 2).Return(tree, nil).Once()
+
+		api.GetCategoryTree(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			IsSuccess bool                      `json:"success"`
+			Data      []*model.CategoryTreeNode `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.True(t, response.IsSuccess)
+		assert.Equal(t, tree, response.Data)
+		mockCategoryService.AssertExpectations(t)
+	})
+}
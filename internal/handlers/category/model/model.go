@@ -2,6 +2,8 @@
 // It includes request and response models for category API endpoints.
 package model
 
+import "github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+
 type StandardResponse struct {
 	IsSuccess bool   `json:"success"`
 	Message   string `json:"message"`
@@ -40,3 +42,45 @@ type UpdateCategoryRequest struct {
 	ParentID    *int   `json:"parentId"    validate:"omitempty,required"`
 	Description string `json:"description" validate:"required"`
 }
+
+// CategoryTreeNode is a category together with its nested subcategories
+type CategoryTreeNode struct {
+	ID          int                 `json:"id"`
+	Name        string              `json:"name"`
+	ParentID    *int                `json:"parentId"`
+	Description string              `json:"description"`
+	Children    []*CategoryTreeNode `json:"children"`
+}
+
+// DeletePolicy decides what happens to the products and subcategories of a
+// deleted category
+type DeletePolicy string
+
+const (
+	// DeleteRestrict refuses to delete a category that has products or subcategories
+	DeleteRestrict DeletePolicy = "restrict"
+	// DeleteCascade deletes the category with all its subcategories and their products
+	DeleteCascade DeletePolicy = "cascade"
+	// DeleteReassign moves the products and subcategories to the parent of the
+	// category. Subcategories of a top-level category become top-level; its
+	// products have nowhere to go, so it must have none.
+	DeleteReassign DeletePolicy = "reassign"
+)
+
+// DefaultDeletePolicy is used when no policy is given
+const DefaultDeletePolicy = DeleteRestrict
+
+// ErrInvalidDeletePolicy is returned for a policy other than restrict, cascade or reassign
+var ErrInvalidDeletePolicy = apperror.New(apperror.Validation, "delete policy must be restrict, cascade or reassign")
+
+// ParseDeletePolicy parses a delete policy, returning DefaultDeletePolicy for an empty string
+func ParseDeletePolicy(s string) (DeletePolicy, error) {
+	switch policy := DeletePolicy(s); policy {
+	case "":
+		return DefaultDeletePolicy, nil
+	case DeleteRestrict, DeleteCascade, DeleteReassign:
+		return policy, nil
+	default:
+		return "", ErrInvalidDeletePolicy
+	}
+}
@@ -0,0 +1,46 @@
+// Package category provides HTTP handlers for category-related operations.
+// It includes endpoints for creating, reading, updating, and deleting categories.
+package category
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/gorilla/mux"
+)
+
+// RestoreCategory godoc
+// @Summary Restore a deleted category
+// @Schemes
+// @Description Bring back a soft-deleted category. Its parent must not be deleted; products and subcategories deleted with it are restored separately.
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 	 200  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      409  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /v1/category/{id}/restore [POST]
+// RestoreCategory handles HTTP requests for restoring soft-deleted categories by ID.
+func (c *CategoryAPI) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cid, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || cid <= 0 {
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	if err := c.catSrvc.RestoreCategory(ctx, cid); err != nil {
+		response.SendError(w, r, c.logger, err)
+		return
+	}
+
+	c.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Message: "Category restored successfully"}, http.StatusOK)
+}
@@ -0,0 +1,51 @@
+package category
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/category"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryAPI_RestoreCategory(t *testing.T) {
+	api := &CategoryAPI{
+		catSrvc: mockCategoryService,
+		logger:  logger.NewLogger(logger.DefaultOptions()),
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{"Invalid Category ID", "-1", nil, http.StatusBadRequest},
+		{"Restored", "1", nil, http.StatusOK},
+		{"Not Deleted", "2", repository.ErrCategoryNotFound, http.StatusNotFound},
+		{"Parent Deleted", "3", category.ErrParentDeleted, http.StatusConflict},
+		{"Service Error", "4", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/category/"+tt.id+"/restore", http.NoBody)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+
+			if tt.expectedStatus != http.StatusBadRequest {
+				mockCategoryService.On("RestoreCategory", mock.Anything, mock.AnythingOfType("int")).Return(tt.serviceErr).Once()
+			}
+			api.RestoreCategory(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockCategoryService.AssertExpectations(t)
+		})
+	}
+}
@@ -23,14 +23,19 @@ import (
 // @Produce json
 // @Param id path int true "Category ID"
 // @Param category body model.UpdateCategoryRequest true "Category"
+// @Param If-Match header string false "ETag of the category as last read; required when the server requires If-Match"
 // @Success 	 200  {object}  model.StandardResponse
-// @Failure      401  {object}  model.StandardResponse
-// @Failure      400  {object}  model.StandardResponse
-// @Failure      404  {string} string "404 page not found"
-// @Failure      500  {object}  model.StandardResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      400  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      412  {object}  response.Problem
+// @Failure      428  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
 // @Router /v1/category/{id} [put]
 // UpdateCategory handles HTTP requests for updating existing categories.
-// It validates the request and updates the category in the database.
+// It validates the request and updates the category in the database, provided
+// it is still at the version of the ETag in the If-Match header, if any.
 func (c *CategoryAPI) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	res := model.StandardResponse{}
@@ -38,13 +43,18 @@ func (c *CategoryAPI) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	// Get and validate category ID
 	categoryID := mux.Vars(r)["id"]
 	if categoryID == "" {
-		c.sendErrorResponse(w, "Category ID is required", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Category ID is required")
 		return
 	}
 
 	cid, err := strconv.Atoi(categoryID)
 	if err != nil || cid <= 0 {
-		c.sendErrorResponse(w, "Invalid category ID", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	version, ok := c.ifMatch(w, r)
+	if !ok {
 		return
 	}
 
@@ -54,27 +64,24 @@ func (c *CategoryAPI) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	if err != nil {
 		c.logger.Error("error while reading request body", err)
-		response.SendResponseRaw(w, http.StatusBadRequest, nil)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
 		return
 	}
 
 	if err = json.Unmarshal(body, &req); err != nil {
-		c.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Validate request
-	if errors := validation.ValidateStruct(req); len(errors) > 0 {
-		res.Message = "Validation error"
-		res.Data = errors
-		c.sendJSONResponse(w, res, http.StatusBadRequest)
+	if errs := validation.ValidateStruct(req); len(errs) > 0 {
+		response.SendValidationProblem(w, r, errs)
 		return
 	}
 
 	// Update category
-	if err := c.catSrvc.UpdateCategory(ctx, cid, req); err != nil {
-		c.logger.Error("error while updating category", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+	if err := c.catSrvc.UpdateCategory(ctx, cid, req, version); err != nil {
+		response.SendError(w, r, c.logger, err)
 		return
 	}
 
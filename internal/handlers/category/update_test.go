@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/category"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -74,10 +77,10 @@ func TestCategoryAPI_UpdateCategory(t *testing.T) {
 		api.UpdateCategory(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response model.StandardResponse
-		err := json.NewDecoder(w.Body).Decode(&response)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
 		assert.NoError(t, err)
-		assert.Equal(t, "Validation error", response.Message)
+		assert.Equal(t, response.ValidationProblemType, problem.Type)
 	})
 
 	t.Run("Successful Update", func(t *testing.T) {
@@ -90,7 +93,7 @@ func TestCategoryAPI_UpdateCategory(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockCategoryService.On("UpdateCategory", mock.Anything, 1, validCategory).Return(nil).Once()
+		mockCategoryService.On("UpdateCategory", mock.Anything, 1, validCategory, 0).Return(nil).Once()
 
 		api.UpdateCategory(w, req)
 
@@ -102,4 +105,44 @@ func TestCategoryAPI_UpdateCategory(t *testing.T) {
 		assert.Equal(t, "Category updated successfully", response.Message)
 		mockCategoryService.AssertExpectations(t)
 	})
+
+	t.Run("Parent Would Create Cycle", func(t *testing.T) {
+		parentID := 3
+		cyclicCategory := model.UpdateCategoryRequest{
+			Name:        "Updated Category",
+			ParentID:    &parentID,
+			Description: "Updated Description",
+		}
+		body, _ := json.Marshal(cyclicCategory)
+		req := httptest.NewRequest(http.MethodPut, "/categories/1", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockCategoryService.On("UpdateCategory", mock.Anything, 1, cyclicCategory, 0).Return(category.ErrCategoryCycle).Once()
+
+		api.UpdateCategory(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		assert.NoError(t, err)
+		assert.Equal(t, "Category cannot be its own ancestor", problem.Detail)
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Stale If-Match", func(t *testing.T) {
+		validCategory := model.UpdateCategoryRequest{Name: "Updated Category", Description: "Updated Description"}
+		body, _ := json.Marshal(validCategory)
+		req := httptest.NewRequest(http.MethodPut, "/categories/1", bytes.NewReader(body))
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockCategoryService.On("UpdateCategory", mock.Anything, 1, validCategory, 3).Return(repository.ErrVersionMismatch).Once()
+
+		api.UpdateCategory(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
 }
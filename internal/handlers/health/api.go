@@ -14,8 +14,6 @@ import (
 const (
 	HealthCheckPath = "/health-check"
 	CacheHealthPath = "/cache/health"
-	CacheStatsPath  = "/cache/stats"
-	FlushCachePath  = "/cache/flush"
 )
 
 type HealthAPI struct {
@@ -16,29 +16,38 @@ import (
 // CacheHealthAPI provides cache health check endpoints
 type CacheHealthAPI struct {
 	logger *logger.Logger
-	cache  *cache.Cache
+	cache  cache.Cache
 }
 
 // NewCacheHealthAPI creates a new cache health API instance
-func NewCacheHealthAPI(logger *logger.Logger, cache *cache.Cache) *CacheHealthAPI {
+func NewCacheHealthAPI(logger *logger.Logger, cache cache.Cache) *CacheHealthAPI {
 	return &CacheHealthAPI{
 		logger: logger,
 		cache:  cache,
 	}
 }
 
-// RegisterHandlers registers the cache health check routes
+// RegisterHandlers registers the cache health check route. Statistics and
+// flushing live in the admin API.
 func (h *CacheHealthAPI) RegisterHandlers(router *mux.Router) {
 	router.HandleFunc(CacheHealthPath, h.CacheHealth).Methods(http.MethodGet)
-	router.HandleFunc(CacheStatsPath, h.CacheStats).Methods(http.MethodGet)
-	router.HandleFunc(FlushCachePath, h.FlushCache).Methods(http.MethodPost)
 }
 
-// CacheHealth checks if Redis cache is healthy
+// CacheHealth checks if the cache is healthy
 func (h *CacheHealthAPI) CacheHealth(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
+	backend := cache.BackendOf(h.cache)
+	if backend == cache.BackendNone {
+		response.Success(w, http.StatusOK, "Caching is disabled", map[string]any{
+			"status":    "disabled",
+			"timestamp": time.Now().Unix(),
+			"service":   backend,
+		})
+		return
+	}
+
 	// Test cache connection with a simple ping
 	testKey := "health_check"
 	testValue := "ok"
@@ -46,7 +55,7 @@ func (h *CacheHealthAPI) CacheHealth(w http.ResponseWriter, r *http.Request) {
 	// Try to set a test value
 	if err := h.cache.Set(ctx, testKey, testValue, 1*time.Minute); err != nil {
 		h.logger.Error("cache health check failed - set operation", "error", err)
-		response.Error(w, http.StatusServiceUnavailable, "Cache is unhealthy - set operation failed")
+		response.SendProblem(w, r, http.StatusServiceUnavailable, "Cache is unhealthy - set operation failed")
 		return
 	}
 
@@ -54,7 +63,7 @@ func (h *CacheHealthAPI) CacheHealth(w http.ResponseWriter, r *http.Request) {
 	var retrievedValue string
 	if err := h.cache.Get(ctx, testKey, &retrievedValue); err != nil {
 		h.logger.Error("cache health check failed - get operation", "error", err)
-		response.Error(w, http.StatusServiceUnavailable, "Cache is unhealthy - get operation failed")
+		response.SendProblem(w, r, http.StatusServiceUnavailable, "Cache is unhealthy - get operation failed")
 		return
 	}
 
@@ -65,59 +74,13 @@ func (h *CacheHealthAPI) CacheHealth(w http.ResponseWriter, r *http.Request) {
 
 	if retrievedValue != testValue {
 		h.logger.Error("cache health check failed - value mismatch")
-		response.Error(w, http.StatusServiceUnavailable, "Cache is unhealthy - value mismatch")
+		response.SendProblem(w, r, http.StatusServiceUnavailable, "Cache is unhealthy - value mismatch")
 		return
 	}
 
 	response.Success(w, http.StatusOK, "Cache is healthy", map[string]any{
 		"status":    "healthy",
 		"timestamp": time.Now().Unix(),
-		"service":   "redis",
-	})
-}
-
-// CacheStats returns cache statistics
-func (h *CacheHealthAPI) CacheStats(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	// Get Redis client for advanced stats
-	client := h.cache.GetClient()
-
-	// Get Redis info
-	info, err := client.Info(ctx).Result()
-	if err != nil {
-		h.logger.Error("failed to get cache stats", "error", err)
-		response.Error(w, http.StatusInternalServerError, "Failed to get cache statistics")
-		return
-	}
-
-	// Get database size
-	dbSize, err := client.DBSize(ctx).Result()
-	if err != nil {
-		h.logger.Error("failed to get database size", "error", err)
-		response.Error(w, http.StatusInternalServerError, "Failed to get database size")
-		return
-	}
-
-	response.Success(w, http.StatusOK, "Cache statistics", map[string]any{
-		"db_size":   dbSize,
-		"timestamp": time.Now().Unix(),
-		"info":      info,
-	})
-}
-
-// FlushCache clears all cache data
-func (h *CacheHealthAPI) FlushCache(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	if err := h.cache.FlushDB(ctx); err != nil {
-		h.logger.Error("failed to flush cache", "error", err)
-		response.Error(w, http.StatusInternalServerError, "Failed to flush cache")
-		return
-	}
-
-	response.Success(w, http.StatusOK, "Cache flushed successfully", map[string]any{
-		"message":   "All cache data cleared",
-		"timestamp": time.Now().Unix(),
+		"service":   backend,
 	})
 }
@@ -1,17 +1,21 @@
 package health
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/package/cache"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCacheHealthAPI(t *testing.T) {
 	logger := logger.NewLogger(logger.DefaultOptions())
-	cache := &cache.Cache{}
+	cache := cache.NewMemoryCache(0, 0)
 
 	api := NewCacheHealthAPI(logger, cache)
 
@@ -22,7 +26,7 @@ func TestNewCacheHealthAPI(t *testing.T) {
 
 func TestCacheHealthAPI_RegisterHandlers(t *testing.T) {
 	logger := logger.NewLogger(logger.DefaultOptions())
-	cache := &cache.Cache{}
+	cache := cache.NewMemoryCache(0, 0)
 	api := NewCacheHealthAPI(logger, cache)
 
 	router := mux.NewRouter()
@@ -36,7 +40,7 @@ func TestCacheHealthAPI_RegisterHandlers(t *testing.T) {
 
 func TestCacheHealthAPI_Structure(t *testing.T) {
 	logger := logger.NewLogger(logger.DefaultOptions())
-	cache := &cache.Cache{}
+	cache := cache.NewMemoryCache(0, 0)
 	api := NewCacheHealthAPI(logger, cache)
 
 	// Test that the API has the expected structure
@@ -46,7 +50,7 @@ func TestCacheHealthAPI_Structure(t *testing.T) {
 
 func TestCacheHealthAPI_HandlerMethods(t *testing.T) {
 	logger := logger.NewLogger(logger.DefaultOptions())
-	cache := &cache.Cache{}
+	cache := cache.NewMemoryCache(0, 0)
 	api := NewCacheHealthAPI(logger, cache)
 
 	// Test that the API structure is correct
@@ -56,6 +60,35 @@ func TestCacheHealthAPI_HandlerMethods(t *testing.T) {
 
 	// Test that the methods exist (we won't call them due to nil client)
 	assert.NotNil(t, api.CacheHealth)
-	assert.NotNil(t, api.CacheStats)
-	assert.NotNil(t, api.FlushCache)
+}
+
+func TestCacheHealthAPI_CacheHealth(t *testing.T) {
+	logger := logger.NewLogger(logger.DefaultOptions())
+
+	tests := []struct {
+		name       string
+		cache      cache.Cache
+		wantStatus string
+	}{
+		{name: "Memory", cache: cache.NewMemoryCache(0, 0), wantStatus: "healthy"},
+		{name: "Disabled", cache: cache.NewNoopCache(), wantStatus: "disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewCacheHealthAPI(logger, tt.cache)
+			req := httptest.NewRequest(http.MethodGet, CacheHealthPath, http.NoBody)
+			w := httptest.NewRecorder()
+
+			api.CacheHealth(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var res struct {
+				Data map[string]any `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+			assert.Equal(t, tt.wantStatus, res.Data["status"])
+			assert.Equal(t, cache.BackendOf(tt.cache), res.Data["service"])
+		})
+	}
 }
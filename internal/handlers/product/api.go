@@ -6,10 +6,11 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
 	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/internal/services/product"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/middleware"
 	"github.com/gorilla/mux"
 )
 
@@ -22,44 +23,59 @@ const (
 	UpdateProductPath = "/update-product/{id}"
 	// DeleteProductPath is the path for deleting a product
 	DeleteProductPath = "/product/{id}"
+	// ListProductsPath is the path for listing products
+	ListProductsPath = "/products"
+	// RestoreProductPath is the path for restoring a deleted product
+	RestoreProductPath = "/product/{id}/restore"
+	// SearchProductsPath is the path for searching products
+	SearchProductsPath = "/products/search"
 )
 
 type ProductAPI struct {
 	logger     *logger.Logger
 	prdService product.ProductServiceInterface
+	// requireIfMatch refuses updates and deletes without an If-Match header
+	requireIfMatch bool
 }
 
-func NewProductAPI(logger *logger.Logger, prdService product.ProductServiceInterface) *ProductAPI {
+// NewProductAPI returns the product handlers. With requireIfMatch, updates and
+// deletes must send the ETag of the product in an If-Match header.
+func NewProductAPI(logger *logger.Logger, prdService product.ProductServiceInterface, requireIfMatch bool) *ProductAPI {
 	return &ProductAPI{
-		logger:     logger,
-		prdService: prdService,
+		logger:         logger,
+		prdService:     prdService,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
 func (p *ProductAPI) RegisterHandlers(router *mux.Router) {
-	router.Handle(ProductDetailPath, http.HandlerFunc(p.GetProductDetail)).Methods(http.MethodGet)
-	router.Handle(CreateProductPath, http.HandlerFunc(p.CreateProductDetail)).Methods(http.MethodPost)
-	router.Handle(UpdateProductPath, http.HandlerFunc(p.UpdateProductDetail)).Methods(http.MethodPut)
-	router.Handle(DeleteProductPath, http.HandlerFunc(p.DeleteProduct)).Methods(http.MethodDelete)
+	router.Handle(ProductDetailPath, middleware.IncludeDeleted(auth.PermProductDelete, p.GetProductDetail)).Methods(http.MethodGet)
+	router.Handle(CreateProductPath, middleware.RequirePermission(auth.PermProductWrite, p.CreateProductDetail)).Methods(http.MethodPost)
+	router.Handle(UpdateProductPath, middleware.RequirePermission(auth.PermProductWrite, p.UpdateProductDetail)).Methods(http.MethodPut)
+	router.Handle(DeleteProductPath, middleware.RequirePermission(auth.PermProductDelete, p.DeleteProduct)).Methods(http.MethodDelete)
+	router.Handle(RestoreProductPath, middleware.RequirePermission(auth.PermProductDelete, p.RestoreProduct)).Methods(http.MethodPost)
+	router.Handle(ListProductsPath, middleware.IncludeDeleted(auth.PermProductDelete, p.ListProducts)).Methods(http.MethodGet)
+	router.Handle(SearchProductsPath, http.HandlerFunc(p.SearchProducts)).Methods(http.MethodGet)
 }
 
-func (p *ProductAPI) sendErrorResponse(w http.ResponseWriter, message string, status int) {
-	res := model.StandardResponse{Message: message}
-	resp, err := json.Marshal(res)
+func (p *ProductAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
+	resp, err := json.Marshal(data)
 	if err != nil {
-		p.logger.Error("error while marshalling error response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		p.logger.Error("error while marshalling response", err)
+		response.Error(w, http.StatusInternalServerError, response.InternalErrorMessage)
 		return
 	}
 	response.SendResponseRaw(w, status, resp)
 }
 
-func (p *ProductAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
-	resp, err := json.Marshal(data)
+// ifMatch returns the version the If-Match header asks to write at, 0 meaning
+// any. When the header is missing but required, or cannot match, it answers
+// the request itself and returns false.
+func (p *ProductAPI) ifMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	version, err := response.IfMatch(r, p.requireIfMatch)
 	if err != nil {
-		p.logger.Error("error while marshalling response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
-		return
+		response.SendError(w, r, p.logger, err)
+		return 0, false
 	}
-	response.SendResponseRaw(w, status, resp)
+	return version, true
 }
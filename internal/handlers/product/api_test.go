@@ -0,0 +1,51 @@
+package product
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductAPI_RegisterHandlers_Permissions(t *testing.T) {
+	api := NewProductAPI(logger.NewLogger(logger.DefaultOptions()), mockService, false)
+
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			viewer := &auth.Principal{Subject: "viewer"}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), viewer)))
+		})
+	})
+	api.RegisterHandlers(router)
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/create-product"},
+		{http.MethodPut, "/update-product/1"},
+		{http.MethodDelete, "/product/1"},
+		{http.MethodPost, "/product/1/restore"},
+		{http.MethodGet, "/product/1?include_deleted=true"},
+		{http.MethodGet, "/products?include_deleted=true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{}`))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Equal(t, response.ProblemContentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -21,10 +21,11 @@ import (
 // @Produce json
 // @Param product body model.CreateProductRequest true "Product"
 // @Success 	 200  {object}  model.ProductDetailResponse
-// @Failure      401  {object}  model.StandardResponse
-// @Failure      400  {object}  model.StandardResponse
-// @Failure      404  {string} string "404 page not found"
-// @Failure      500  {object}  model.StandardResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      400  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
 // @Router /v1/create-product [post]
 // CreateProductDetail handles HTTP requests for creating new products.
 // It validates the request, creates the product, and returns the result.
@@ -37,29 +38,26 @@ func (p *ProductAPI) CreateProductDetail(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	if err != nil {
 		p.logger.Error("error while reading request body", err)
-		response.SendResponseRaw(w, http.StatusBadRequest, nil)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
 		return
 	}
 
 	var req model.CreateProductRequest
 	if err = json.Unmarshal(body, &req); err != nil {
 		p.logger.Error("error while parsing request body", err)
-		p.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Validate request
 	if errors := validation.ValidateStruct(req); len(errors) > 0 {
-		res.Message = "Validation error"
-		res.Data = errors
-		p.sendJSONResponse(w, res, http.StatusBadRequest)
+		response.SendValidationProblem(w, r, errors)
 		return
 	}
 
 	// Create product
 	if err = p.prdService.CreateProduct(ctx, req); err != nil {
-		p.logger.Error("error while creating product", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendError(w, r, p.logger, err)
 		return
 	}
 
@@ -68,7 +66,7 @@ func (p *ProductAPI) CreateProductDetail(w http.ResponseWriter, r *http.Request)
 	resp, err := json.Marshal(res)
 	if err != nil {
 		p.logger.Error("error while marshalling response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendProblem(w, r, http.StatusInternalServerError, response.InternalErrorMessage)
 		return
 	}
 
@@ -19,46 +19,42 @@ import (
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the product as last read; required when the server requires If-Match"
 // @Success 	 200  {object}  model.StandardResponse
-// @Failure      401  {object}  model.StandardResponse
-// @Failure      400  {object}  model.StandardResponse
-// @Failure      404  {string} string "404 page not found"
-// @Failure      500  {object}  model.StandardResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      400  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      412  {object}  response.Problem
+// @Failure      428  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
 // @Router /v1/product/{id} [DELETE]
 // DeleteProduct handles HTTP requests for deleting products by ID.
-// It validates the ID and removes the product from the database.
+// It validates the ID and removes the product from the database, provided it
+// is still at the version of the ETag in the If-Match header, if any.
 func (p *ProductAPI) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	res := model.StandardResponse{}
 
 	productID := mux.Vars(r)["id"]
 	if productID == "" {
-		p.sendErrorResponse(w, "Product ID is required", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Product ID is required")
 		return
 	}
 
 	pid, err := strconv.Atoi(productID)
 	if err != nil || pid <= 0 {
-		p.sendErrorResponse(w, "Invalid product ID", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid product ID")
 		return
 	}
 
-	product, err := p.prdService.GetProductDetail(ctx, pid)
-	if err != nil {
-		p.logger.Error("error while fetching product details", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+	version, ok := p.ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	if product == nil {
-		res.Message = "Product not found"
-		p.sendJSONResponse(w, res, http.StatusOK)
-		return
-	}
-
-	if err := p.prdService.DeleteProduct(ctx, pid); err != nil {
-		p.logger.Error("error while delete product", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+	if err := p.prdService.DeleteProduct(ctx, pid, version); err != nil {
+		response.SendError(w, r, p.logger, err)
 		return
 	}
 
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -52,12 +54,29 @@ func TestProductAPI_DeleteProduct(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
-	t.Run("Get Product Detail Error", func(t *testing.T) {
+	t.Run("Product Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/products/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockService.On("DeleteProduct", mock.Anything, 1, 0).Return(repository.ErrProductNotFound).Once()
+
+		api.DeleteProduct(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		assert.NoError(t, err)
+		assert.Equal(t, "Product not found", problem.Detail)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Delete Product Error", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/products/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockService.On("GetProductDetail", mock.Anything, 1).Return(nil, errors.New("database error")).Once()
+		mockService.On("DeleteProduct", mock.Anything, 1, 0).Return(errors.New("delete error")).Once()
 
 		api.DeleteProduct(w, req)
 
@@ -65,12 +84,12 @@ func TestProductAPI_DeleteProduct(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("Product Not Found", func(t *testing.T) {
+	t.Run("Successful Delete", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/products/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockService.On("GetProductDetail", mock.Anything, 1).Return(nil, nil).Once()
+		mockService.On("DeleteProduct", mock.Anything, 1, 0).Return(nil).Once()
 
 		api.DeleteProduct(w, req)
 
@@ -78,39 +97,32 @@ func TestProductAPI_DeleteProduct(t *testing.T) {
 		var response model.StandardResponse
 		err := json.NewDecoder(w.Body).Decode(&response)
 		assert.NoError(t, err)
-		assert.Equal(t, "Product not found", response.Message)
+		assert.True(t, response.IsSuccess)
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("Delete Product Error", func(t *testing.T) {
+	t.Run("Stale If-Match", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/products/1", http.NoBody)
+		req.Header.Set("If-Match", `"4"`)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockService.On("GetProductDetail", mock.Anything, 1).Return(&model.ProductDetailResponse{ID: 1}, nil).Once()
-		mockService.On("DeleteProduct", mock.Anything, 1).Return(errors.New("delete error")).Once()
+		mockService.On("DeleteProduct", mock.Anything, 1, 4).Return(repository.ErrVersionMismatch).Once()
 
 		api.DeleteProduct(w, req)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("Successful Delete", func(t *testing.T) {
+	t.Run("Invalid If-Match", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/products/1", http.NoBody)
+		req.Header.Set("If-Match", `"4", "5"`)
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockService.On("GetProductDetail", mock.Anything, 1).Return(&model.ProductDetailResponse{ID: 1}, nil).Once()
-		mockService.On("DeleteProduct", mock.Anything, 1).Return(nil).Once()
-
 		api.DeleteProduct(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-		var response model.StandardResponse
-		err := json.NewDecoder(w.Body).Decode(&response)
-		assert.NoError(t, err)
-		assert.True(t, response.IsSuccess)
-		mockService.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
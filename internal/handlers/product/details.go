@@ -19,11 +19,15 @@ import (
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param include_deleted query bool false "Also return the product when it is soft deleted; needs product:delete"
+// @Param If-None-Match header string false "ETag of a cached copy; answered with 304 while it is current"
 // @Success 200 {object} model.StandardResponse
-// @Failure 400 {object} model.StandardResponse
-// @Failure 401 {object} model.StandardResponse
-// @Failure 404 {string} string "404 page not found"
-// @Failure 500 {object} model.StandardResponse
+// @Header 200 {string} ETag "Version of the product, for If-Match and If-None-Match"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 500 {object} response.Problem
 // @Router /v1/product/{id} [get]
 // GetProductDetail handles HTTP requests for retrieving product details by ID.
 // It validates the ID and returns the product information.
@@ -33,33 +37,36 @@ func (p *ProductAPI) GetProductDetail(w http.ResponseWriter, r *http.Request) {
 
 	productID := mux.Vars(r)["id"]
 	if productID == "" {
-		p.sendErrorResponse(w, "Product ID is required", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Product ID is required")
 		return
 	}
 
 	pid, err := strconv.Atoi(productID)
 	if err != nil || pid <= 0 {
-		p.sendErrorResponse(w, "Invalid product ID", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid product ID")
 		return
 	}
 
 	product, err := p.prdService.GetProductDetail(ctx, pid)
 	if err != nil {
-		p.logger.Error("error while fetching product details", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendError(w, r, p.logger, err)
 		return
 	}
 
-	res.IsSuccess = true
-	if product == nil {
-		res.Message = "Product not found"
+	etag := response.ETag(product.Version)
+	if response.NotModified(r, etag) {
+		response.SendNotModified(w, etag)
+		return
 	}
+	w.Header().Set("ETag", etag)
+
+	res.IsSuccess = true
 	res.Data = product
 
 	resp, err := json.Marshal(res)
 	if err != nil {
 		p.logger.Error("error while marshalling response", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+		response.SendProblem(w, r, http.StatusInternalServerError, response.InternalErrorMessage)
 		return
 	}
 
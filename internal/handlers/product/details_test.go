@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -67,6 +69,23 @@ func TestProductAPI_GetProductDetail(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("Product Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockService.On("GetProductDetail", mock.Anything, 1).Return(nil, repository.ErrProductNotFound).Once()
+
+		api.GetProductDetail(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		assert.NoError(t, err)
+		assert.Equal(t, "Product not found", problem.Detail)
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("Service Returns Error With Custom Message", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/products/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -77,6 +96,29 @@ func TestProductAPI_GetProductDetail(t *testing.T) {
 		api.GetProductDetail(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "custom service error")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("ETag And Not Modified", func(t *testing.T) {
+		expectedProduct := &model.ProductDetailResponse{ID: 1, Name: "Test Product", Version: 3}
+		mockService.On("GetProductDetail", mock.Anything, 1).Return(expectedProduct, nil).Twice()
+
+		req := httptest.NewRequest(http.MethodGet, "/products/1", http.NoBody)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		w := httptest.NewRecorder()
+		api.GetProductDetail(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+		req = httptest.NewRequest(http.MethodGet, "/products/1", http.NoBody)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		req.Header.Set("If-None-Match", `W/"3"`)
+		w = httptest.NewRecorder()
+		api.GetProductDetail(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+		assert.Empty(t, w.Body.String())
 		mockService.AssertExpectations(t)
 	})
 }
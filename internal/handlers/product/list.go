@@ -0,0 +1,117 @@
+// Package product provides HTTP handlers for product-related operations.
+// It includes endpoints for creating, reading, updating, and deleting products.
+package product
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+)
+
+// ListProducts godoc
+// @Summary List products
+// @Description List products with filtering, sorting and offset or cursor pagination
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param category_id query int false "Filter by category ID"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param in_stock query bool false "Only products in stock (true) or out of stock (false)"
+// @Param sort query string false "Sort field" Enums(name, price, created_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param page query int false "Page number for offset pagination"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by a previous page"
+// @Param include_deleted query bool false "Also list soft-deleted products; needs product:delete"
+// @Success 200 {object} model.StandardResponse{data=model.ProductListResponse}
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /v1/products [get]
+// ListProducts handles HTTP requests for listing products.
+// It parses the query parameters and returns one page of matching products.
+func (p *ProductAPI) ListProducts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res := model.StandardResponse{}
+
+	req, field, err := parseListProductsRequest(r.URL.Query())
+	if err != nil {
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid query parameter: "+field)
+		return
+	}
+
+	// Validate request
+	if errs := validation.ValidateStruct(req); len(errs) > 0 {
+		response.SendValidationProblem(w, r, errs)
+		return
+	}
+
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		response.SendProblem(w, r, http.StatusBadRequest, "min_price must not be greater than max_price")
+		return
+	}
+
+	list, err := p.prdService.ListProducts(ctx, req)
+	if err != nil {
+		response.SendError(w, r, p.logger, err)
+		return
+	}
+
+	res.IsSuccess = true
+	res.Data = list
+	p.sendJSONResponse(w, res, http.StatusOK)
+}
+
+// parseListProductsRequest reads the listing options from the query string.
+// On a malformed value it returns the name of the offending parameter.
+func parseListProductsRequest(q url.Values) (req model.ListProductsRequest, field string, err error) {
+	req.Sort = q.Get("sort")
+	req.Order = q.Get("order")
+	req.Cursor = q.Get("cursor")
+
+	if v := q.Get("category_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return req, "category_id", err
+		}
+		req.CategoryID = &id
+	}
+	if v := q.Get("min_price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return req, "min_price", err
+		}
+		req.MinPrice = &price
+	}
+	if v := q.Get("max_price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return req, "max_price", err
+		}
+		req.MaxPrice = &price
+	}
+	if v := q.Get("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, "in_stock", err
+		}
+		req.InStock = &inStock
+	}
+	if v := q.Get("page"); v != "" {
+		if req.Page, err = strconv.Atoi(v); err != nil {
+			return req, "page", err
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return req, "limit", err
+		}
+	}
+
+	return req, "", nil
+}
@@ -0,0 +1,116 @@
+package product
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductAPI_ListProducts(t *testing.T) {
+	testLogger := logger.NewLogger(logger.DefaultOptions())
+	api := &ProductAPI{
+		prdService: mockService,
+		logger:     testLogger,
+	}
+
+	t.Run("Invalid Query Parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?min_price=cheap", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.ListProducts(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var problem response.Problem
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+		assert.Equal(t, "Invalid query parameter: min_price", problem.Detail)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?sort=stock&limit=500", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.ListProducts(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Inverted Price Range", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?min_price=50&max_price=10", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.ListProducts(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?cursor=bogus", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockService.On("ListProducts", mock.Anything, model.ListProductsRequest{Cursor: "bogus"}).
+			Return(nil, repository.ErrInvalidCursor).Once()
+
+		api.ListProducts(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?page=2", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockService.On("ListProducts", mock.Anything, model.ListProductsRequest{Page: 2}).
+			Return(nil, errors.New("database error")).Once()
+
+		api.ListProducts(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Successful List", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet,
+			"/products?category_id=3&in_stock=true&sort=price&order=desc&limit=1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		categoryID := 3
+		inStock := true
+		expected := model.ListProductsRequest{
+			CategoryID: &categoryID,
+			InStock:    &inStock,
+			Sort:       "price",
+			Order:      "desc",
+			Limit:      1,
+		}
+		list := &model.ProductListResponse{
+			Items:      []model.ProductDetailResponse{{ID: 1, Name: "Test Product", Price: 99.99, CategoryID: 3}},
+			Total:      4,
+			Page:       1,
+			Limit:      1,
+			NextCursor: "next",
+		}
+		mockService.On("ListProducts", mock.Anything, expected).Return(list, nil).Once()
+
+		api.ListProducts(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			IsSuccess bool                      `json:"success"`
+			Data      model.ProductListResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.True(t, response.IsSuccess)
+		assert.Equal(t, *list, response.Data)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -2,6 +2,8 @@
 // It includes request and response models for product API endpoints.
 package model
 
+import "time"
+
 type StandardResponse struct {
 	IsSuccess bool   `json:"success"`
 	Message   string `json:"message"`
@@ -31,4 +33,53 @@ type ProductDetailResponse struct {
 	Price       float64 `json:"price"`
 	CategoryID  int     `json:"categoryId"`
 	Stock       int     `json:"stock"`
+	// Version goes up on every change; detail responses also send it as the ETag
+	Version int `json:"version"`
+	// DeletedAt is only set on soft-deleted products, which are listed with include_deleted
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// ListProductsRequest holds the query parameters accepted by the product listing endpoint.
+// Page and Cursor are alternative pagination modes; Cursor wins when both are set.
+type ListProductsRequest struct {
+	CategoryID *int     `json:"category_id" validate:"omitempty,min=1"`
+	MinPrice   *float64 `json:"min_price"   validate:"omitempty,min=0"`
+	MaxPrice   *float64 `json:"max_price"   validate:"omitempty,min=0"`
+	InStock    *bool    `json:"in_stock"`
+	Sort       string   `json:"sort"        validate:"omitempty,oneof=name price created_at"`
+	Order      string   `json:"order"       validate:"omitempty,oneof=asc desc"`
+	Page       int      `json:"page"        validate:"omitempty,min=1"`
+	Limit      int      `json:"limit"       validate:"omitempty,min=1,max=100"`
+	Cursor     string   `json:"cursor"`
+}
+
+type ProductListResponse struct {
+	Items      []ProductDetailResponse `json:"items"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page,omitempty"`
+	Limit      int                     `json:"limit"`
+	NextCursor string                  `json:"nextCursor,omitempty"`
+}
+
+// SearchProductsRequest holds the query parameters accepted by the product search endpoint.
+type SearchProductsRequest struct {
+	Query string `json:"q"     validate:"required,min=2,max=200"`
+	Page  int    `json:"page"  validate:"omitempty,min=1"`
+	Limit int    `json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// ProductSearchHit is a single search result with its relevance score and
+// highlighted snippets of the matching fields.
+type ProductSearchHit struct {
+	ProductDetailResponse
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+type ProductSearchResponse struct {
+	Query string             `json:"query"`
+	Items []ProductSearchHit `json:"items"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
 }
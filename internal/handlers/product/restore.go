@@ -0,0 +1,46 @@
+// Package product provides HTTP handlers for product-related operations.
+// It includes endpoints for creating, reading, updating, and deleting products.
+package product
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/gorilla/mux"
+)
+
+// RestoreProduct godoc
+// @Summary Restore a deleted product
+// @Schemes
+// @Description Bring back a soft-deleted product. Its category must not be deleted.
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 	 200  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      409  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /v1/product/{id}/restore [POST]
+// RestoreProduct handles HTTP requests for restoring soft-deleted products by ID.
+func (p *ProductAPI) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pid, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || pid <= 0 {
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	if err := p.prdService.RestoreProduct(ctx, pid); err != nil {
+		response.SendError(w, r, p.logger, err)
+		return
+	}
+
+	p.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Message: "Product restored successfully"}, http.StatusOK)
+}
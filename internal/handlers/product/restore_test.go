@@ -0,0 +1,51 @@
+package product
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/product"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductAPI_RestoreProduct(t *testing.T) {
+	api := &ProductAPI{
+		prdService: mockService,
+		logger:     logger.NewLogger(logger.DefaultOptions()),
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{"Invalid Product ID", "abc", nil, http.StatusBadRequest},
+		{"Restored", "1", nil, http.StatusOK},
+		{"Not Deleted", "2", repository.ErrProductNotFound, http.StatusNotFound},
+		{"Category Deleted", "3", product.ErrCategoryDeleted, http.StatusConflict},
+		{"Service Error", "4", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/product/"+tt.id+"/restore", http.NoBody)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+
+			if tt.expectedStatus != http.StatusBadRequest {
+				mockService.On("RestoreProduct", mock.Anything, mock.AnythingOfType("int")).Return(tt.serviceErr).Once()
+			}
+			api.RestoreProduct(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
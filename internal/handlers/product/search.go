@@ -0,0 +1,65 @@
+// Package product provides HTTP handlers for product-related operations.
+// It includes endpoints for creating, reading, updating, and deleting products.
+package product
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+)
+
+// SearchProducts godoc
+// @Summary Search products
+// @Description Full-text search over product names and descriptions, ranked by relevance
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param q query string true "Search keywords"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} model.StandardResponse{data=model.ProductSearchResponse}
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 500 {object} response.Problem
+// @Router /v1/products/search [get]
+// SearchProducts handles HTTP requests for keyword product search.
+// It returns ranked matches with highlighted snippets.
+func (p *ProductAPI) SearchProducts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res := model.StandardResponse{}
+	q := r.URL.Query()
+
+	req := model.SearchProductsRequest{Query: q.Get("q")}
+	var err error
+	if v := q.Get("page"); v != "" {
+		if req.Page, err = strconv.Atoi(v); err != nil {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid query parameter: page")
+			return
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid query parameter: limit")
+			return
+		}
+	}
+
+	// Validate request
+	if errs := validation.ValidateStruct(req); len(errs) > 0 {
+		response.SendValidationProblem(w, r, errs)
+		return
+	}
+
+	result, err := p.prdService.SearchProducts(ctx, req)
+	if err != nil {
+		response.SendError(w, r, p.logger, err)
+		return
+	}
+
+	res.IsSuccess = true
+	res.Data = result
+	p.sendJSONResponse(w, res, http.StatusOK)
+}
@@ -0,0 +1,84 @@
+package product
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductAPI_SearchProducts(t *testing.T) {
+	testLogger := logger.NewLogger(logger.DefaultOptions())
+	api := &ProductAPI{
+		prdService: mockService,
+		logger:     testLogger,
+	}
+
+	t.Run("Missing Query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/search", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.SearchProducts(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/search?q=mouse&page=x", http.NoBody)
+		w := httptest.NewRecorder()
+
+		api.SearchProducts(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/search?q=keyboard", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockService.On("SearchProducts", mock.Anything, model.SearchProductsRequest{Query: "keyboard"}).
+			Return(nil, errors.New("database error")).Once()
+
+		api.SearchProducts(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Successful Search", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products/search?q=mouse&limit=5", http.NoBody)
+		w := httptest.NewRecorder()
+
+		result := &model.ProductSearchResponse{
+			Query: "mouse",
+			Items: []model.ProductSearchHit{{
+				ProductDetailResponse: model.ProductDetailResponse{ID: 1, Name: "Wireless Mouse"},
+				Score:                 2.5,
+				Highlights:            map[string]string{"name": "Wireless <em>Mouse</em>"},
+			}},
+			Total: 1,
+			Page:  1,
+			Limit: 5,
+		}
+		mockService.On("SearchProducts", mock.Anything, model.SearchProductsRequest{Query: "mouse", Limit: 5}).
+			Return(result, nil).Once()
+
+		api.SearchProducts(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			IsSuccess bool                        `json:"success"`
+			Data      model.ProductSearchResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.True(t, response.IsSuccess)
+		assert.Equal(t, *result, response.Data)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -22,14 +22,19 @@ import (
 // @Produce json
 // @Param id path int true "Product ID"
 // @Param product body model.UpdateProductRequest true "Product"
+// @Param If-Match header string false "ETag of the product as last read; required when the server requires If-Match"
 // @Success 200 {object} model.StandardResponse
-// @Failure 400 {object} model.StandardResponse
-// @Failure 401 {object} model.StandardResponse
-// @Failure 404 {string} string "404 page not found"
-// @Failure 500 {object} model.StandardResponse
+// @Failure 400 {object} response.Problem
+// @Failure 401 {object} response.Problem
+// @Failure 403 {object} response.Problem
+// @Failure 404 {object} response.Problem
+// @Failure 412 {object} response.Problem
+// @Failure 428 {object} response.Problem
+// @Failure 500 {object} response.Problem
 // @Router /v1/update-product/{id} [put]
 // UpdateProductDetail handles HTTP requests for updating existing products.
-// It validates the request and updates the product in the database.
+// It validates the request and updates the product in the database, provided
+// it is still at the version of the ETag in the If-Match header, if any.
 func (p *ProductAPI) UpdateProductDetail(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	res := model.StandardResponse{}
@@ -37,13 +42,18 @@ func (p *ProductAPI) UpdateProductDetail(w http.ResponseWriter, r *http.Request)
 	// Get and validate product ID
 	productID := mux.Vars(r)["id"]
 	if productID == "" {
-		p.sendErrorResponse(w, "Product ID is required", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Product ID is required")
 		return
 	}
 
 	pid, err := strconv.Atoi(productID)
 	if err != nil || pid <= 0 {
-		p.sendErrorResponse(w, "Invalid product ID", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	version, ok := p.ifMatch(w, r)
+	if !ok {
 		return
 	}
 
@@ -53,27 +63,24 @@ func (p *ProductAPI) UpdateProductDetail(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	if err != nil {
 		p.logger.Error("error while reading request body", err)
-		response.SendResponseRaw(w, http.StatusBadRequest, nil)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
 		return
 	}
 
 	if err = json.Unmarshal(body, &req); err != nil {
-		p.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Validate request
 	if errors := validation.ValidateStruct(req); len(errors) > 0 {
-		res.Message = "Validation error"
-		res.Data = errors
-		p.sendJSONResponse(w, res, http.StatusBadRequest)
+		response.SendValidationProblem(w, r, errors)
 		return
 	}
 
 	// Update product
-	if err := p.prdService.UpdateProduct(ctx, pid, req); err != nil {
-		p.logger.Error("error while updating product", err)
-		response.SendResponseRaw(w, http.StatusInternalServerError, nil)
+	if err := p.prdService.UpdateProduct(ctx, pid, req, version); err != nil {
+		response.SendError(w, r, p.logger, err)
 		return
 	}
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -90,7 +91,7 @@ func TestProductAPI_UpdateProductDetail(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockService.On("UpdateProduct", mock.Anything, 1, validProduct).Return(errors.New("update error")).Once()
+		mockService.On("UpdateProduct", mock.Anything, 1, validProduct, 0).Return(errors.New("update error")).Once()
 
 		api.UpdateProductDetail(w, req)
 
@@ -111,7 +112,7 @@ func TestProductAPI_UpdateProductDetail(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		req = mux.SetURLVars(req, map[string]string{"id": "1"})
-		mockService.On("UpdateProduct", mock.Anything, 1, validProduct).Return(nil).Once()
+		mockService.On("UpdateProduct", mock.Anything, 1, validProduct, 0).Return(nil).Once()
 
 		api.UpdateProductDetail(w, req)
 
@@ -122,4 +123,42 @@ func TestProductAPI_UpdateProductDetail(t *testing.T) {
 		assert.True(t, response.IsSuccess)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("Stale If-Match", func(t *testing.T) {
+		validProduct := model.UpdateProductRequest{Name: "Updated Product"}
+		body, _ := json.Marshal(validProduct)
+		req := httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewReader(body))
+		req.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		mockService.On("UpdateProduct", mock.Anything, 1, validProduct, 2).Return(repository.ErrVersionMismatch).Once()
+
+		api.UpdateProductDetail(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Weak If-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewReader([]byte(`{"name":"x"}`)))
+		req.Header.Set("If-Match", `W/"2"`)
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		api.UpdateProductDetail(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("Missing Required If-Match", func(t *testing.T) {
+		strict := &ProductAPI{prdService: mockService, logger: testLogger, requireIfMatch: true}
+		req := httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewReader([]byte(`{"name":"x"}`)))
+		w := httptest.NewRecorder()
+
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		strict.UpdateProductDetail(w, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
 }
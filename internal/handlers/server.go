@@ -6,32 +6,114 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"time"
 
+	"github.com/MitulShah1/golang-rest-api-template/config"
 	_ "github.com/MitulShah1/golang-rest-api-template/docs"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/admin"
+	apiKeyApi "github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey"
+	authApi "github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth"
 	catApi "github.com/MitulShah1/golang-rest-api-template/internal/handlers/category"
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/health"
 	prodApi "github.com/MitulShah1/golang-rest-api-template/internal/handlers/product"
+	userApi "github.com/MitulShah1/golang-rest-api-template/internal/handlers/user"
+	userModel "github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
 	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/apikey"
+	authService "github.com/MitulShah1/golang-rest-api-template/internal/services/auth"
 	"github.com/MitulShah1/golang-rest-api-template/internal/services/category"
 	"github.com/MitulShah1/golang-rest-api-template/internal/services/product"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/user"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
 	"github.com/MitulShah1/golang-rest-api-template/package/cache"
 	"github.com/MitulShah1/golang-rest-api-template/package/database"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 	"github.com/MitulShah1/golang-rest-api-template/package/middleware"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
+// bootstrapTimeout bounds the startup work done against the database
+const bootstrapTimeout = 10 * time.Second
+
 type Server struct {
 	httpList net.Listener
 	httpSrvr *http.Server
 	logger   *logger.Logger
 }
 
-func NewServer(address string, logger *logger.Logger, db *database.Database, cache *cache.Cache, tm *middleware.TelemetryConfig) (*Server, error) {
+func NewServer(address string, cfg *config.Service, logger *logger.Logger, db *database.Database, c cache.Cache, redisClient redis.Cmdable, tm *middleware.TelemetryConfig) (*Server, error) {
+	authCfg := cfg.GetAuthConfig()
+	cacheCfg := cfg.GetCacheConfig()
+
+	// JWT token manager
+	tokenManager, err := auth.NewTokenManager(auth.JWTConfig{
+		Algorithm:      authCfg.JWTAlgorithm,
+		Secret:         authCfg.JWTSecret,
+		PrivateKeyFile: authCfg.JWTPrivateKeyFile,
+		PublicKeyFile:  authCfg.JWTPublicKeyFile,
+		JWKSFile:       authCfg.JWTJWKSFile,
+		KeyID:          authCfg.JWTKeyID,
+		Issuer:         authCfg.JWTIssuer,
+		Audience:       authCfg.JWTAudience,
+		AccessTTL:      authCfg.AccessTokenTTL,
+		RefreshTTL:     authCfg.RefreshTokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// initialize repository
+	repo := repository.NewDBRepository(db)
+
+	// roles and the permissions they grant
+	policy := auth.DefaultPolicy()
+
+	// initialize user service, which also verifies login credentials
+	hasher, err := auth.NewPasswordHasher(authCfg.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	refreshStore := auth.NewRefreshTokenStore(redisClient, tokenManager.RefreshTTL())
+	userService, err := user.NewUserService(repo, logger, hasher, user.NewLogNotifier(logger), user.Config{
+		MaxFailedLogins: authCfg.MaxFailedLogins,
+		LockoutDuration: authCfg.LockoutDuration,
+		ResetTokenTTL:   authCfg.ResetTokenTTL,
+		Policy:          policy,
+		Sessions:        refreshStore,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// bootstrap the admin account from configuration
+	if authCfg.AdminUsername != "" && authCfg.AdminPassword != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), bootstrapTimeout)
+		err := userService.EnsureUser(ctx, userModel.CreateUserRequest{
+			Username: authCfg.AdminUsername,
+			Email:    authCfg.AdminEmail,
+			Password: authCfg.AdminPassword,
+		}, auth.RoleAdmin)
+		cancel()
+		if err != nil {
+			logger.Warn("could not create admin account", "error", err)
+		}
+	}
+
+	// Errors are problem details unless old clients need the legacy envelope
+	response.UseLegacyErrors(cfg.GetServerConfig().LegacyErrors)
+
 	// Create a new router
 	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.SendProblem(w, r, http.StatusNotFound, "No endpoint matches the request path")
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.SendProblem(w, r, http.StatusMethodNotAllowed, "The endpoint does not support the "+r.Method+" method")
+	})
 
 	// swagger docs
 	// Serve Swagger UI
@@ -44,7 +126,9 @@ func NewServer(address string, logger *logger.Logger, db *database.Database, cac
 
 	mw := func(handler http.Handler) http.Handler {
 		return prometheusMiddleware.Middleware(
-			tm.OpenTelemetryMiddleware(handler),
+			tm.OpenTelemetryMiddleware(
+				middleware.ReadYourWrites(handler),
+			),
 		)
 	}
 
@@ -58,40 +142,94 @@ func NewServer(address string, logger *logger.Logger, db *database.Database, cac
 	healthAPI := health.NewHealthAPI(logger)
 	healthAPI.RegisterHandlers(r)
 
-	// cache health check API
-	cacheHealthAPI := health.NewCacheHealthAPI(logger, cache)
+	// auth API (login, refresh and logout are public)
+	authHandler := authApi.NewAuthAPI(logger, authService.NewAuthService(tokenManager, refreshStore, userService, logger))
+	authHandler.RegisterHandlers(r)
+
+	// user API (password reset is public, account management is protected)
+	userHandler := userApi.NewUserAPI(logger, userService)
+	userHandler.RegisterPublicHandlers(r)
+
+	// cache health check API (statistics and flushing are in the admin API)
+	cacheHealthAPI := health.NewCacheHealthAPI(logger, c)
 	cacheHealthAPI.RegisterHandlers(r)
 
+	// initialize API key service, which also verifies X-API-Key headers
+	apiKeyService := apikey.NewAPIKeyService(repo, logger, policy)
+
+	// CORS origins follow configuration reloads
+	cors := middleware.NewCors(cfg.GetCORSConfig().AllowedOrigins)
+	cfg.Subscribe(func(c config.Config) {
+		cors.SetAllowedOrigins(c.CORS.AllowedOrigins)
+	})
+
 	// Create versioned subrouter (e.g., /v1)
 	apiV1 := r.PathPrefix("/v1").Subrouter()
 
 	// Register all middlewares
 	middlewares := func(handler http.Handler) http.Handler {
-		return middleware.CorsMiddleware(
-			middleware.AuthMiddleware(handler),
+		return cors.Middleware(
+			middleware.APIKeyMiddleware(apiKeyService)(
+				middleware.AuthMiddleware(middleware.AuthConfig{
+					Tokens: tokenManager,
+					Basic:  userService,
+					Policy: policy,
+				})(handler),
+			),
 		)
 	}
 
 	// Protected routes uses authentication middleware
 	apiV1.Use(middlewares)
 
-	// initialize repository
-	repo := repository.NewDBRepository(db)
+	// Register user handlers
+	userHandler.RegisterHandlers(apiV1)
+
+	// Register API key handlers
+	apiKeyHandler := apiKeyApi.NewAPIKeyAPI(logger, apiKeyService)
+	apiKeyHandler.RegisterHandlers(apiV1)
+
+	// Admin group for operational endpoints, with the same authentication and
+	// a permission check on every route
+	adminRouter := r.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(middlewares)
+
+	cacheAdminHandler := admin.NewCacheAdminAPI(logger, c)
+	cacheAdminHandler.RegisterHandlers(adminRouter)
+
+	// Expired entries are reloaded the same way by every service
+	loadOptions := cache.LoadOptions{
+		StaleTTL:             cacheCfg.StaleTTL,
+		StaleWhileRevalidate: cacheCfg.StaleWhileRevalidate,
+		StaleIfError:         cacheCfg.StaleIfError,
+		EarlyExpiry:          cacheCfg.EarlyExpiry,
+		Lock:                 cacheCfg.Lock,
+		LockTimeout:          cacheCfg.LockTimeout,
+		NegativeTTL:          cacheCfg.NegativeTTL,
+	}
 
 	// initialize product service with cache
-	productService := product.NewProductService(repo, logger, cache)
+	productService := product.NewProductService(repo, logger, c, product.Config{
+		DetailTTL: cacheCfg.ProductTTL,
+		ListTTL:   cacheCfg.ProductListTTL,
+		SearchTTL: cacheCfg.SearchTTL,
+		Load:      loadOptions,
+	})
 
 	// initialize product handler
-	productHandler := prodApi.NewProductAPI(logger, productService)
+	productHandler := prodApi.NewProductAPI(logger, productService, cfg.GetServerConfig().RequireIfMatch)
 
 	// Register product handlers
 	productHandler.RegisterHandlers(apiV1)
 
 	// initialize category service with cache
-	categoryService := category.NewCategoryService(repo, logger, cache)
+	categoryService := category.NewCategoryService(repo, logger, c, category.Config{
+		CacheTTL: cacheCfg.CategoryTTL,
+		Load:     loadOptions,
+	})
 
 	// initialize category handler
-	categoryHandler := catApi.NewCategoryAPI(logger, categoryService)
+	categoryHandler := catApi.NewCategoryAPI(logger, categoryService, cfg.GetServerConfig().RequireIfMatch)
 
 	/// Register category handlers
 	categoryHandler.RegisterHandlers(apiV1)
@@ -0,0 +1,91 @@
+// Package user provides HTTP handlers for user accounts.
+// It includes endpoints for creating accounts and changing or resetting passwords.
+package user
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/user"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/middleware"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// CreateUserPath is the path for creating a user account
+	CreateUserPath = "/users"
+	// ChangePasswordPath is the path for changing the caller's password
+	ChangePasswordPath = "/users/me/password"
+	// UserRolesPath is the path for reading and replacing a user's roles
+	UserRolesPath = "/users/{username}/roles"
+	// ForgotPasswordPath is the path for requesting a password reset token
+	ForgotPasswordPath = "/auth/password/forgot"
+	// ResetPasswordPath is the path for setting a new password with a reset token
+	ResetPasswordPath = "/auth/password/reset"
+)
+
+type UserAPI struct {
+	logger  *logger.Logger
+	userSrv user.UserServiceInterface
+}
+
+func NewUserAPI(logger *logger.Logger, userSrv user.UserServiceInterface) *UserAPI {
+	return &UserAPI{
+		logger:  logger,
+		userSrv: userSrv,
+	}
+}
+
+// RegisterHandlers registers the endpoints that require an authenticated caller
+func (u *UserAPI) RegisterHandlers(router *mux.Router) {
+	router.HandleFunc(CreateUserPath, middleware.RequirePermission(auth.PermUserManage, u.CreateUser)).Methods(http.MethodPost)
+	router.HandleFunc(ChangePasswordPath, u.ChangePassword).Methods(http.MethodPut)
+	router.HandleFunc(UserRolesPath, middleware.RequirePermission(auth.PermUserManage, u.GetUserRoles)).Methods(http.MethodGet)
+	router.HandleFunc(UserRolesPath, middleware.RequirePermission(auth.PermUserManage, u.SetUserRoles)).Methods(http.MethodPut)
+}
+
+// RegisterPublicHandlers registers the password reset endpoints, which are used before logging in
+func (u *UserAPI) RegisterPublicHandlers(router *mux.Router) {
+	router.HandleFunc(ForgotPasswordPath, u.ForgotPassword).Methods(http.MethodPost)
+	router.HandleFunc(ResetPasswordPath, u.ResetPassword).Methods(http.MethodPost)
+}
+
+// decodeRequest reads and validates a JSON request body, writing the error response itself.
+// It reports whether the handler should continue.
+func (u *UserAPI) decodeRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	if err != nil {
+		u.logger.Error("error while reading request body", err)
+		response.SendProblem(w, r, http.StatusBadRequest, "Could not read request body")
+		return false
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		u.logger.Error("error while parsing request body", err)
+		response.SendProblem(w, r, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	if errs := validation.ValidateStruct(dst); len(errs) > 0 {
+		response.SendValidationProblem(w, r, errs)
+		return false
+	}
+
+	return true
+}
+
+func (u *UserAPI) sendJSONResponse(w http.ResponseWriter, data any, status int) {
+	resp, err := json.Marshal(data)
+	if err != nil {
+		u.logger.Error("error while marshalling response", err)
+		response.Error(w, http.StatusInternalServerError, response.InternalErrorMessage)
+		return
+	}
+	response.SendResponseRaw(w, status, resp)
+}
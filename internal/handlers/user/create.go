@@ -0,0 +1,49 @@
+// Package user provides HTTP handlers for user accounts.
+// It includes endpoints for creating accounts and changing or resetting passwords.
+package user
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+)
+
+// CreateUser godoc
+// @Summary Create user
+// @Schemes
+// @Description Create a user account with a hashed password
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user body model.CreateUserRequest true "User"
+// @Success      201  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      409  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/users [post]
+// CreateUser handles HTTP requests for creating user accounts.
+func (u *UserAPI) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateUserRequest
+	if !u.decodeRequest(w, r, &req) {
+		return
+	}
+
+	userID, err := u.userSrv.CreateUser(r.Context(), req)
+	if err != nil {
+		response.SendError(w, r, u.logger, err)
+		return
+	}
+
+	u.sendJSONResponse(w, model.StandardResponse{
+		IsSuccess: true,
+		Data: struct {
+			UserID int64 `json:"userId"`
+		}{
+			UserID: userID,
+		},
+	}, http.StatusCreated)
+}
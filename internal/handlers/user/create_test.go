@@ -0,0 +1,81 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/user/mocks"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var mockUserService = new(mocks.UserServiceInterface)
+
+func TestUserAPI_CreateUser(t *testing.T) {
+	api := NewUserAPI(logger.NewLogger(logger.DefaultOptions()), mockUserService)
+	validUser := model.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "password1"}
+
+	t.Run("Successful User Creation", func(t *testing.T) {
+		body, _ := json.Marshal(validUser)
+		req := httptest.NewRequest(http.MethodPost, CreateUserPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("CreateUser", context.Background(), validUser).Return(int64(1), nil).Once()
+
+		api.CreateUser(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUserService.AssertExpectations(t)
+		assert.NotContains(t, w.Body.String(), "password1")
+	})
+
+	t.Run("Duplicate User", func(t *testing.T) {
+		body, _ := json.Marshal(validUser)
+		req := httptest.NewRequest(http.MethodPost, CreateUserPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("CreateUser", context.Background(), validUser).Return(int64(0), repository.ErrUserExists).Once()
+
+		api.CreateUser(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		body, _ := json.Marshal(validUser)
+		req := httptest.NewRequest(http.MethodPost, CreateUserPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("CreateUser", context.Background(), validUser).Return(int64(0), errors.New("db error")).Once()
+
+		api.CreateUser(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		body, _ := json.Marshal(model.CreateUserRequest{Username: "al", Email: "not-an-email", Password: "short"})
+		req := httptest.NewRequest(http.MethodPost, CreateUserPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		api.CreateUser(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var problem response.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		assert.NoError(t, err)
+		assert.Equal(t, response.ValidationProblemType, problem.Type)
+		assert.Len(t, problem.Errors, 3)
+	})
+}
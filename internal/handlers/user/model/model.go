@@ -0,0 +1,48 @@
+// Package model provides data structures for user account operations.
+// It includes request and response models for the user API endpoints.
+package model
+
+import "time"
+
+type StandardResponse struct {
+	IsSuccess bool   `json:"success"`
+	Message   string `json:"message"`
+	Data      any    `json:"data"`
+}
+
+// Passwords are capped at 72 bytes so that bcrypt never silently truncates them
+type CreateUserRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=64,alphanum"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,max=72"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8,max=72"`
+}
+
+type UserResponse struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type UpdateUserRolesRequest struct {
+	Roles []string `json:"roles" validate:"required,max=16,dive,required,max=64"`
+}
+
+type UserRolesResponse struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
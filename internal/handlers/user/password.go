@@ -0,0 +1,102 @@
+// Package user provides HTTP handlers for user accounts.
+// It includes endpoints for creating accounts and changing or resetting passwords.
+package user
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+)
+
+// ChangePassword godoc
+// @Summary Change password
+// @Schemes
+// @Description Change the authenticated user's password
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param password body model.ChangePasswordRequest true "Passwords"
+// @Success      200  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/users/me/password [put]
+// ChangePassword handles HTTP requests for changing the caller's password.
+func (u *UserAPI) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		response.SendProblem(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if !u.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := u.userSrv.ChangePassword(r.Context(), principal.Subject, req); err != nil {
+		response.SendError(w, r, u.logger, err)
+		return
+	}
+	u.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Message: "Password changed"}, http.StatusOK)
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Schemes
+// @Description Send a password reset token to the account registered with the email address.
+// @Description The response is the same whether or not the address belongs to an account.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body model.ForgotPasswordRequest true "Email"
+// @Success      202  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /auth/password/forgot [post]
+// ForgotPassword handles HTTP requests for starting a password reset.
+func (u *UserAPI) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ForgotPasswordRequest
+	if !u.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := u.userSrv.RequestPasswordReset(r.Context(), req); err != nil {
+		response.SendError(w, r, u.logger, err)
+		return
+	}
+
+	u.sendJSONResponse(w, model.StandardResponse{
+		IsSuccess: true,
+		Message:   "If the address belongs to an account, a reset token has been sent",
+	}, http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Schemes
+// @Description Set a new password using a password reset token
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body model.ResetPasswordRequest true "Reset token and new password"
+// @Success      200  {object}  model.StandardResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router /auth/password/reset [post]
+// ResetPassword handles HTTP requests for completing a password reset.
+func (u *UserAPI) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ResetPasswordRequest
+	if !u.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := u.userSrv.ResetPassword(r.Context(), req); err != nil {
+		response.SendError(w, r, u.logger, err)
+		return
+	}
+	u.sendJSONResponse(w, model.StandardResponse{IsSuccess: true, Message: "Password reset"}, http.StatusOK)
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/user"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAPI_ChangePassword(t *testing.T) {
+	api := NewUserAPI(logger.NewLogger(logger.DefaultOptions()), mockUserService)
+	changeReq := model.ChangePasswordRequest{CurrentPassword: "password1", NewPassword: "password2"}
+	principal := &auth.Principal{Subject: "alice"}
+
+	t.Run("Successful Change", func(t *testing.T) {
+		body, _ := json.Marshal(changeReq)
+		ctx := auth.WithPrincipal(context.Background(), principal)
+		req := httptest.NewRequest(http.MethodPut, ChangePasswordPath, bytes.NewReader(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		mockUserService.On("ChangePassword", ctx, "alice", changeReq).Return(nil).Once()
+
+		api.ChangePassword(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		body, _ := json.Marshal(changeReq)
+		ctx := auth.WithPrincipal(context.Background(), principal)
+		req := httptest.NewRequest(http.MethodPut, ChangePasswordPath, bytes.NewReader(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		mockUserService.On("ChangePassword", ctx, "alice", changeReq).Return(auth.ErrInvalidCredentials).Once()
+
+		api.ChangePassword(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("No Principal", func(t *testing.T) {
+		body, _ := json.Marshal(changeReq)
+		req := httptest.NewRequest(http.MethodPut, ChangePasswordPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		api.ChangePassword(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestUserAPI_ForgotPassword(t *testing.T) {
+	api := NewUserAPI(logger.NewLogger(logger.DefaultOptions()), mockUserService)
+
+	t.Run("Accepted", func(t *testing.T) {
+		forgotReq := model.ForgotPasswordRequest{Email: "alice@example.com"}
+		body, _ := json.Marshal(forgotReq)
+		req := httptest.NewRequest(http.MethodPost, ForgotPasswordPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("RequestPasswordReset", context.Background(), forgotReq).Return(nil).Once()
+
+		api.ForgotPassword(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Email", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, ForgotPasswordPath, bytes.NewReader([]byte(`{"email":"nope"}`)))
+		w := httptest.NewRecorder()
+
+		api.ForgotPassword(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUserAPI_ResetPassword(t *testing.T) {
+	api := NewUserAPI(logger.NewLogger(logger.DefaultOptions()), mockUserService)
+	resetReq := model.ResetPasswordRequest{Token: "token", NewPassword: "password2"}
+
+	t.Run("Successful Reset", func(t *testing.T) {
+		body, _ := json.Marshal(resetReq)
+		req := httptest.NewRequest(http.MethodPost, ResetPasswordPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("ResetPassword", context.Background(), resetReq).Return(nil).Once()
+
+		api.ResetPassword(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		body, _ := json.Marshal(resetReq)
+		req := httptest.NewRequest(http.MethodPost, ResetPasswordPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("ResetPassword", context.Background(), resetReq).Return(user.ErrInvalidResetToken).Once()
+
+		api.ResetPassword(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+}
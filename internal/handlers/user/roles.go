@@ -0,0 +1,77 @@
+// Package user provides HTTP handlers for user accounts.
+// It includes endpoints for creating accounts and changing or resetting passwords.
+package user
+
+import (
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/gorilla/mux"
+)
+
+// GetUserRoles godoc
+// @Summary Get user roles
+// @Schemes
+// @Description Get the roles assigned to a user
+// @Tags User
+// @Produce json
+// @Param username path string true "Username"
+// @Success      200  {object}  model.StandardResponse{data=model.UserRolesResponse}
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/users/{username}/roles [get]
+// GetUserRoles handles HTTP requests for reading a user's roles.
+func (u *UserAPI) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	roles, err := u.userSrv.GetUserRoles(r.Context(), username)
+	if err != nil {
+		response.SendError(w, r, u.logger, err)
+		return
+	}
+
+	u.sendJSONResponse(w, model.StandardResponse{
+		IsSuccess: true,
+		Data:      model.UserRolesResponse{Username: username, Roles: roles},
+	}, http.StatusOK)
+}
+
+// SetUserRoles godoc
+// @Summary Set user roles
+// @Schemes
+// @Description Replace the roles assigned to a user. Changes apply to the user's next token refresh.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param username path string true "Username"
+// @Param roles body model.UpdateUserRolesRequest true "Roles"
+// @Success      200  {object}  model.StandardResponse{data=model.UserRolesResponse}
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Security BearerAuth
+// @Router /v1/users/{username}/roles [put]
+// SetUserRoles handles HTTP requests for replacing a user's roles.
+func (u *UserAPI) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	var req model.UpdateUserRolesRequest
+	if !u.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := u.userSrv.SetUserRoles(r.Context(), username, req.Roles); err != nil {
+		response.SendError(w, r, u.logger, err)
+		return
+	}
+	u.sendJSONResponse(w, model.StandardResponse{
+		IsSuccess: true,
+		Data:      model.UserRolesResponse{Username: username, Roles: req.Roles},
+	}, http.StatusOK)
+}
@@ -0,0 +1,122 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/internal/services/user"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRolesRouter registers the user API behind a fixed principal
+func newRolesRouter(api *UserAPI, principal *auth.Principal) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	})
+	api.RegisterHandlers(router)
+	return router
+}
+
+func TestUserAPI_UserRoles(t *testing.T) {
+	api := NewUserAPI(logger.NewLogger(logger.DefaultOptions()), mockUserService)
+	admin := &auth.Principal{Subject: "admin", Permissions: []auth.Permission{auth.PermUserManage}}
+	router := newRolesRouter(api, admin)
+
+	t.Run("Get Roles", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/alice/roles", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockUserService.On("GetUserRoles", mock.Anything, "alice").Return([]string{auth.RoleEditor}, nil).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserService.AssertExpectations(t)
+
+		var response struct {
+			Data model.UserRolesResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, []string{auth.RoleEditor}, response.Data.Roles)
+	})
+
+	t.Run("Get Roles Unknown User", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/nobody/roles", http.NoBody)
+		w := httptest.NewRecorder()
+
+		mockUserService.On("GetUserRoles", mock.Anything, "nobody").Return(nil, repository.ErrUserNotFound).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Set Roles", func(t *testing.T) {
+		body, _ := json.Marshal(model.UpdateUserRolesRequest{Roles: []string{auth.RoleEditor}})
+		req := httptest.NewRequest(http.MethodPut, "/users/alice/roles", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("SetUserRoles", mock.Anything, "alice", []string{auth.RoleEditor}).Return(nil).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Set Unknown Role", func(t *testing.T) {
+		body, _ := json.Marshal(model.UpdateUserRolesRequest{Roles: []string{"superuser"}})
+		req := httptest.NewRequest(http.MethodPut, "/users/alice/roles", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("SetUserRoles", mock.Anything, "alice", []string{"superuser"}).Return(user.ErrUnknownRole).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Missing Permission", func(t *testing.T) {
+		router := newRolesRouter(api, &auth.Principal{Subject: "bob"})
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "/users/alice/roles", http.NoBody),
+			httptest.NewRequest(http.MethodPut, "/users/alice/roles", bytes.NewReader([]byte(`{"roles":["admin"]}`))),
+			httptest.NewRequest(http.MethodPost, CreateUserPath, bytes.NewReader([]byte(`{}`))),
+		} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, req.Method+" "+req.URL.Path)
+		}
+	})
+
+	t.Run("Change Own Password Needs No Permission", func(t *testing.T) {
+		router := newRolesRouter(api, &auth.Principal{Subject: "bob"})
+		changeReq := model.ChangePasswordRequest{CurrentPassword: "password1", NewPassword: "password2"}
+		body, _ := json.Marshal(changeReq)
+		req := httptest.NewRequest(http.MethodPut, ChangePasswordPath, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		mockUserService.On("ChangePassword", mock.Anything, "bob", changeReq).Return(nil).Once()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserService.AssertExpectations(t)
+	})
+}
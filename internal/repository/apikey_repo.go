@@ -0,0 +1,120 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+)
+
+var ErrAPIKeyNotFound = apperror.New(apperror.NotFound, "API key not found")
+
+const APIKeyTableName = "api_keys"
+
+// apiKeyColumns selects a key together with its owner's username
+var apiKeyColumns = []string{
+	"k.id", "k.prefix", "k.salt", "k.key_hash", "k.name", "k.user_id", "u.username AS owner",
+	"k.scopes", "k.expires_at", "k.last_used_at", "k.revoked_at", "k.created_at",
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey) (int64, error)
+	GetAPIKeyByID(ctx context.Context, id int) (*model.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int, revokedAt time.Time) error
+	TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error
+}
+
+// CreateAPIKey inserts a new API key and returns its ID
+func (r *NewRepository) CreateAPIKey(ctx context.Context, key *model.APIKey) (int64, error) {
+	return r.insertReturningID(ctx, r.builder().Insert(APIKeyTableName).
+		Columns("prefix", "salt", "key_hash", "name", "user_id", "scopes", "expires_at").
+		Values(key.Prefix, key.Salt, key.KeyHash, key.Name, key.UserID, key.Scopes, key.ExpiresAt))
+}
+
+// GetAPIKeyByID retrieves an API key by its ID
+func (r *NewRepository) GetAPIKeyByID(ctx context.Context, id int) (*model.APIKey, error) {
+	return r.getAPIKey(ctx, squirrel.Eq{"k.id": id})
+}
+
+// GetAPIKeyByPrefix retrieves an API key by its visible prefix.
+// Keys whose owner no longer exists are not found.
+func (r *NewRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	return r.getAPIKey(ctx, squirrel.Eq{"k.prefix": prefix})
+}
+
+// ListAPIKeys retrieves the API keys owned by a user, newest first, including revoked ones
+func (r *NewRepository) ListAPIKeys(ctx context.Context, userID int) ([]model.APIKey, error) {
+	query, args, err := r.selectAPIKeys().
+		Where(squirrel.Eq{"k.user_id": userID}).
+		OrderBy("k.id DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []model.APIKey{}
+	if err := r.q().SelectContext(ctx, &keys, query, args...); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key as revoked. Revoking a revoked key keeps its original revocation time.
+func (r *NewRepository) RevokeAPIKey(ctx context.Context, id int, revokedAt time.Time) error {
+	query, args, err := r.builder().Update(APIKeyTableName).
+		Set("revoked_at", revokedAt).
+		Where(squirrel.Eq{"id": id, "revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+// TouchAPIKey records when an API key was last used
+func (r *NewRepository) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	query, args, err := r.builder().Update(APIKeyTableName).
+		Set("last_used_at", usedAt).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *NewRepository) getAPIKey(ctx context.Context, where squirrel.Eq) (*model.APIKey, error) {
+	query, args, err := r.selectAPIKeys().Where(where).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var key model.APIKey
+	if err := r.q().GetContext(ctx, &key, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *NewRepository) selectAPIKeys() squirrel.SelectBuilder {
+	return r.builder().Select(apiKeyColumns...).
+		From(APIKeyTableName + " k").
+		Join(UserTableName + " u ON u.id = k.user_id")
+}
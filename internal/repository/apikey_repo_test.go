@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyRowColumns = []string{
+	"id", "prefix", "salt", "key_hash", "name", "user_id", "owner",
+	"scopes", "expires_at", "last_used_at", "revoked_at", "created_at",
+}
+
+func TestRepository_CreateAPIKey(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	key := model.APIKey{Prefix: "ak_0123456789ab", Salt: "salt", KeyHash: "hash", Name: "ci", UserID: 7, Scopes: "product:write"}
+
+	mock.ExpectExec("INSERT INTO api_keys \\(prefix,salt,key_hash,name,user_id,scopes,expires_at\\)").
+		WithArgs(key.Prefix, key.Salt, key.KeyHash, key.Name, key.UserID, key.Scopes, nil).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	id, err := repo.CreateAPIKey(context.Background(), &key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetAPIKeyByPrefix(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		rows := sqlmock.NewRows(apiKeyRowColumns).
+			AddRow(3, "ak_0123456789ab", "salt", "hash", "ci", 7, "alice", "product:write,category:write", nil, nil, nil, now)
+		mock.ExpectQuery("SELECT .+ FROM api_keys k JOIN users u ON u.id = k.user_id WHERE k.prefix = \\?").
+			WithArgs("ak_0123456789ab").
+			WillReturnRows(rows)
+
+		key, err := repo.GetAPIKeyByPrefix(ctx, "ak_0123456789ab")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", key.Owner)
+		assert.Equal(t, []string{"product:write", "category:write"}, key.ScopeList())
+		assert.True(t, key.IsActive(now))
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .+ FROM api_keys").
+			WithArgs("ak_missing").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetAPIKeyByPrefix(ctx, "ak_missing")
+		assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ListAPIKeys(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+
+	now := time.Now()
+	rows := sqlmock.NewRows(apiKeyRowColumns).
+		AddRow(4, "ak_bbbbbbbbbbbb", "salt", "hash", "deploy", 7, "alice", "", nil, now, now, now).
+		AddRow(3, "ak_aaaaaaaaaaaa", "salt", "hash", "ci", 7, "alice", "product:write", nil, nil, nil, now)
+	mock.ExpectQuery("SELECT .+ FROM api_keys k JOIN users u ON u.id = k.user_id WHERE k.user_id = \\? ORDER BY k.id DESC").
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	keys, err := repo.ListAPIKeys(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.False(t, keys[0].IsActive(now))
+	assert.Empty(t, keys[0].ScopeList())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_RevokeAPIKey(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE api_keys SET revoked_at = \\? WHERE id = \\? AND revoked_at IS NULL").
+		WithArgs(now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RevokeAPIKey(context.Background(), 3, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_TouchAPIKey(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE api_keys SET last_used_at = \\? WHERE id = \\?").
+		WithArgs(now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.TouchAPIKey(context.Background(), 3, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,53 +5,69 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
 	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
 )
 
-var ErrCategoryNotFound = errors.New("category not found")
+var (
+	ErrCategoryNotFound = apperror.New(apperror.NotFound, "category not found")
+	// ErrCategoryInUse is returned when deleting a category that products or
+	// other categories still reference
+	ErrCategoryInUse = apperror.New(apperror.Conflict, "category still has products or subcategories")
+	// ErrInvalidCategoryReference is returned when a product or category
+	// refers to a category that does not exist
+	ErrInvalidCategoryReference = apperror.New(apperror.Validation, "referenced category does not exist")
+)
 
 const CategoryTableName = "categories"
 
+// CategoryRepository defines the methods for reading and writing categories.
+// Updates and deletes return ErrCategoryNotFound when there is no live
+// category with the ID. Made at a version other than 0, they only apply to a
+// category still at that version, and return ErrVersionMismatch otherwise.
+// Deleted categories are only marked as deleted and can be restored; reads
+// leave them out unless the context asks for them with database.WithDeleted.
 type CategoryRepository interface {
 	CreateCategory(ctx context.Context, category *model.Category) (int64, error)
 	GetCategoryByID(ctx context.Context, id int) (*model.Category, error)
 	UpdateCategory(ctx context.Context, id int, category *model.Category) error
-	DeleteCategory(ctx context.Context, id int) error
+	DeleteCategory(ctx context.Context, id, version int) error
+	RestoreCategory(ctx context.Context, id int) error
+	GetCategoryChildren(ctx context.Context, parentID int) ([]model.Category, error)
+	ListCategories(ctx context.Context) ([]model.Category, error)
+	CountCategoryProducts(ctx context.Context, categoryID int) (int64, error)
+	MoveCategoryProducts(ctx context.Context, fromID, toID int) error
+	MoveCategoryChildren(ctx context.Context, fromID int, toID *int) error
+	DeleteCategoryProducts(ctx context.Context, categoryIDs []int) error
 }
 
 // CreateCategory creates a new category in the database.
 // It returns the ID of the created category or an error.
 func (r *NewRepository) CreateCategory(ctx context.Context, category *model.Category) (int64, error) {
-	query, args, err := squirrel.Insert(CategoryTableName).
+	id, err := r.insertReturningID(ctx, r.builder().Insert(CategoryTableName).
 		Columns("name", "parent_id", "description").
-		Values(category.Name, category.ParentID, category.Description).
-		ToSql()
-	if err != nil {
-		return 0, err
+		Values(category.Name, category.ParentID, category.Description))
+	if isForeignKeyViolation(err) {
+		return 0, ErrInvalidCategoryReference
 	}
-
-	result, err := r.db.DB.ExecContext(ctx, query, args...)
-	if err != nil {
-		return 0, err
-	}
-
-	return result.LastInsertId()
+	return id, err
 }
 
 // GetCategoryByID retrieves a category by its ID from the database.
 // It returns the category or an error if not found.
 func (r *NewRepository) GetCategoryByID(ctx context.Context, id int) (*model.Category, error) {
-	query, args, err := squirrel.Select("*").From(CategoryTableName).Where(squirrel.Eq{"id": id}).ToSql()
+	query, args, err := r.builder().Select("*").From(CategoryTableName).
+		Where(squirrel.Eq{"id": id}).
+		Where(notDeleted(ctx)).
+		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	row := r.db.DB.QueryRowxContext(ctx, query, args...)
 	var category model.Category
-	err = row.Scan(&category.ID, &category.Name, &category.ParentID, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+	err = r.reader(ctx).QueryRowxContext(ctx, query, args...).StructScan(&category)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrCategoryNotFound
@@ -62,31 +78,146 @@ func (r *NewRepository) GetCategoryByID(ctx context.Context, id int) (*model.Cat
 	return &category, nil
 }
 
-// UpdateCategory updates an existing category in the database.
-// It returns an error if the update fails.
+// UpdateCategory updates an existing category in the database, moving it to
+// its next version. When category.Version is set, the category is only
+// updated at that version. It returns an error if the update fails.
 func (r *NewRepository) UpdateCategory(ctx context.Context, id int, category *model.Category) error {
-	query, args, err := squirrel.Update(CategoryTableName).
+	where := byIDAt(id, category.Version)
+	where["deleted_at"] = nil
+	query, args, err := r.builder().Update(CategoryTableName).
 		Set("name", category.Name).
 		Set("parent_id", category.ParentID).
 		Set("description", category.Description).
-		Where(squirrel.Eq{"id": id}).
+		Set("version", nextVersion).
+		Where(where).
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.DB.ExecContext(ctx, query, args...)
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if isForeignKeyViolation(err) {
+		return ErrInvalidCategoryReference
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	return r.checkWritten(ctx, CategoryTableName, id, category.Version, affected, ErrCategoryNotFound)
+}
+
+// DeleteCategory soft deletes a category by its ID, at the given version
+// unless it is 0, leaving its products and subcategories as they are. It
+// returns an error if the deletion fails.
+func (r *NewRepository) DeleteCategory(ctx context.Context, id, version int) error {
+	deleted, err := r.softDelete(ctx, CategoryTableName, byIDAt(id, version))
+	if err != nil {
+		return err
+	}
+	return r.checkWritten(ctx, CategoryTableName, id, version, deleted, ErrCategoryNotFound)
+}
+
+// GetCategoryChildren retrieves the direct children of a category, ordered by name.
+// It returns an empty slice if the category has no children.
+func (r *NewRepository) GetCategoryChildren(ctx context.Context, parentID int) ([]model.Category, error) {
+	query, args, err := r.builder().Select("*").
+		From(CategoryTableName).
+		Where(squirrel.Eq{"parent_id": parentID}).
+		Where(notDeleted(ctx)).
+		OrderBy("name", "id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []model.Category{}
+	if err := r.reader(ctx).SelectContext(ctx, &categories, query, args...); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// ListCategories retrieves every category, ordered by name.
+// It is used to assemble the category tree in a single query.
+func (r *NewRepository) ListCategories(ctx context.Context) ([]model.Category, error) {
+	query, args, err := r.builder().Select("*").From(CategoryTableName).Where(notDeleted(ctx)).OrderBy("name", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []model.Category{}
+	if err := r.reader(ctx).SelectContext(ctx, &categories, query, args...); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// CountCategoryProducts returns how many products belong directly to a category
+func (r *NewRepository) CountCategoryProducts(ctx context.Context, categoryID int) (int64, error) {
+	query, args, err := r.builder().Select("COUNT(*)").
+		From(ProductTableName).
+		Where(squirrel.Eq{"category_id": categoryID}).
+		Where(notDeleted(ctx)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.reader(ctx).GetContext(ctx, &count, query, args...); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MoveCategoryProducts moves every live product of category fromID to category toID
+func (r *NewRepository) MoveCategoryProducts(ctx context.Context, fromID, toID int) error {
+	query, args, err := r.builder().Update(ProductTableName).
+		Set("category_id", toID).
+		Set("version", nextVersion).
+		Where(squirrel.Eq{"category_id": fromID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	if isForeignKeyViolation(err) {
+		return ErrInvalidCategoryReference
+	}
 	return err
 }
 
-// DeleteCategory removes a category from the database by its ID.
-// It returns an error if the deletion fails.
-func (r *NewRepository) DeleteCategory(ctx context.Context, id int) error {
-	query, args, err := squirrel.Delete(CategoryTableName).Where(squirrel.Eq{"id": id}).ToSql()
+// MoveCategoryChildren gives the live children of category fromID the parent
+// toID, making them top-level categories when toID is nil
+func (r *NewRepository) MoveCategoryChildren(ctx context.Context, fromID int, toID *int) error {
+	query, args, err := r.builder().Update(CategoryTableName).
+		Set("parent_id", toID).
+		Set("version", nextVersion).
+		Where(squirrel.Eq{"parent_id": fromID, "deleted_at": nil}).
+		ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.DB.ExecContext(ctx, query, args...)
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	if isForeignKeyViolation(err) {
+		return ErrInvalidCategoryReference
+	}
+	return err
+}
+
+// DeleteCategoryProducts soft deletes every product belonging to one of the categories
+func (r *NewRepository) DeleteCategoryProducts(ctx context.Context, categoryIDs []int) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	_, err := r.softDelete(ctx, ProductTableName, squirrel.Eq{"category_id": categoryIDs})
 	return err
 }
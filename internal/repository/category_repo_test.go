@@ -236,9 +236,12 @@ func TestRepository_UpdateCategory(t *testing.T) {
 		mock.ExpectExec("UPDATE categories SET").
 			WithArgs(category.Name, category.ParentID, category.Description, 999).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM categories WHERE deleted_at IS NULL AND id = \\?").
+			WithArgs(999).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
 		err := repo.UpdateCategory(ctx, 999, &category)
-		assert.NoError(t, err)
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
 	})
 
 	t.Run("Database Error", func(t *testing.T) {
@@ -282,49 +285,130 @@ func TestRepository_DeleteCategory(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success Delete Category", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM categories").
-			WithArgs(1).
+		mock.ExpectExec("UPDATE categories SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := repo.DeleteCategory(ctx, 1)
+		err := repo.DeleteCategory(ctx, 1, 0)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Delete Non-Existent Category", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM categories").
-			WithArgs(999).
+		mock.ExpectExec("UPDATE categories SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 999).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM categories WHERE deleted_at IS NULL AND id = \\?").
+			WithArgs(999).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		err := repo.DeleteCategory(ctx, 999)
-		assert.NoError(t, err)
+		err := repo.DeleteCategory(ctx, 999, 0)
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
 	})
 
 	t.Run("Delete With Referenced Foreign Key", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM categories").
-			WithArgs(1).
+		mock.ExpectExec("UPDATE categories SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnError(errors.New("foreign key constraint fails"))
 
-		err := repo.DeleteCategory(ctx, 1)
+		err := repo.DeleteCategory(ctx, 1, 0)
 		assert.Error(t, err)
 	})
 
 	t.Run("SQL Query Building Error", func(t *testing.T) {
 		// Simulate a case where query building might fail
 		// This is an edge case where the squirrel library might fail
-		mock.ExpectExec("DELETE FROM categories").
-			WithArgs(-1).
+		mock.ExpectExec("UPDATE categories SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), -1).
 			WillReturnError(errors.New("invalid query"))
 
-		err := repo.DeleteCategory(ctx, -1)
+		err := repo.DeleteCategory(ctx, -1, 0)
 		assert.Error(t, err)
 	})
 
 	t.Run("Database Connection Error", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM categories").
-			WithArgs(1).
+		mock.ExpectExec("UPDATE categories SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnError(errors.New("connection refused"))
 
-		err := repo.DeleteCategory(ctx, 1)
+		err := repo.DeleteCategory(ctx, 1, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestRepository_GetCategoryChildren(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	db := &database.Database{DB: mockDB}
+	repo := &NewRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "description", "created_at", "updated_at"}).
+			AddRow(2, "Laptops", 1, "Portable computers", time.Now(), time.Now()).
+			AddRow(3, "Phones", 1, "Mobile phones", time.Now(), time.Now())
+
+		mock.ExpectQuery("SELECT \\* FROM categories WHERE parent_id = \\? AND deleted_at IS NULL ORDER BY name, id").
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		children, err := repo.GetCategoryChildren(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, children, 2)
+		assert.Equal(t, "Laptops", children[0].Name)
+		assert.Equal(t, 1, *children[0].ParentID)
+	})
+
+	t.Run("No Children", func(t *testing.T) {
+		mock.ExpectQuery("SELECT \\* FROM categories WHERE parent_id = \\?").
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id", "description", "created_at", "updated_at"}))
+
+		children, err := repo.GetCategoryChildren(ctx, 5)
+		assert.NoError(t, err)
+		assert.NotNil(t, children)
+		assert.Empty(t, children)
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT \\* FROM categories WHERE parent_id = \\?").
+			WithArgs(1).
+			WillReturnError(errors.New("database error"))
+
+		children, err := repo.GetCategoryChildren(ctx, 1)
+		assert.Error(t, err)
+		assert.Nil(t, children)
+	})
+}
+
+func TestRepository_ListCategories(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	db := &database.Database{DB: mockDB}
+	repo := &NewRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "description", "created_at", "updated_at"}).
+			AddRow(1, "Electronics", nil, "All electronics", time.Now(), time.Now()).
+			AddRow(2, "Laptops", 1, "Portable computers", time.Now(), time.Now())
+
+		mock.ExpectQuery("SELECT \\* FROM categories WHERE deleted_at IS NULL ORDER BY name, id").WillReturnRows(rows)
+
+		categories, err := repo.ListCategories(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, categories, 2)
+		assert.Nil(t, categories[0].ParentID)
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT \\* FROM categories").WillReturnError(errors.New("database error"))
+
+		categories, err := repo.ListCategories(ctx)
 		assert.Error(t, err)
+		assert.Nil(t, categories)
 	})
 }
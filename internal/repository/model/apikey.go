@@ -0,0 +1,38 @@
+// Package model provides data structures for database entities.
+// It includes models for categories, products, and other database objects.
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey is a stored API key. Only the salted hash of the secret is kept;
+// Prefix is the visible part of the key. Owner is the username of UserID.
+type APIKey struct {
+	ID         int        `db:"id"`
+	Prefix     string     `db:"prefix"`
+	Salt       string     `db:"salt"`
+	KeyHash    string     `db:"key_hash"`
+	Name       string     `db:"name"`
+	UserID     int        `db:"user_id"`
+	Owner      string     `db:"owner"`
+	Scopes     string     `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// ScopeList returns the key's scopes, which are stored comma separated
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -11,4 +11,8 @@ type Category struct {
 	Description string    `db:"description"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
+	// DeletedAt is set when the category is soft deleted
+	DeletedAt *time.Time `db:"deleted_at"`
+	// Version starts at 1 and goes up on every change to the category
+	Version int `db:"version"`
 }
@@ -14,4 +14,14 @@ type Product struct {
 	CategoryID  int       `db:"category_id"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
+	// DeletedAt is set when the product is soft deleted
+	DeletedAt *time.Time `db:"deleted_at"`
+	// Version starts at 1 and goes up on every change to the product
+	Version int `db:"version"`
+}
+
+// ProductSearchResult is a product matched by a keyword search together with its relevance score
+type ProductSearchResult struct {
+	Product
+	Relevance float64 `db:"relevance"`
 }
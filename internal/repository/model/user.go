@@ -0,0 +1,29 @@
+// Package model provides data structures for database entities.
+// It includes models for categories, products, and other database objects.
+package model
+
+import "time"
+
+type User struct {
+	ID             int        `db:"id"`
+	Username       string     `db:"username"`
+	Email          string     `db:"email"`
+	PasswordHash   string     `db:"password_hash"`
+	FailedAttempts int        `db:"failed_attempts"`
+	LockedUntil    *time.Time `db:"locked_until"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+// IsLocked reports whether the account is refusing logins at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+type PasswordResetToken struct {
+	TokenHash string     `db:"token_hash"`
+	UserID    int        `db:"user_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
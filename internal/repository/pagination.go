@@ -0,0 +1,59 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+)
+
+const (
+	// DefaultPageLimit is the page size used when the caller does not set one
+	DefaultPageLimit = 20
+	// MaxPageLimit is the largest page size a listing query will return
+	MaxPageLimit = 100
+)
+
+var ErrInvalidCursor = apperror.New(apperror.Validation, "invalid pagination cursor")
+
+// Cursor identifies the last row of a page for keyset pagination.
+// Value holds the sort column of that row and ID breaks ties between equal values.
+type Cursor struct {
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor previously produced by Cursor.Encode.
+// It returns ErrInvalidCursor if the value is malformed.
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// normalizeLimit clamps a requested page size to the allowed range.
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		c := Cursor{Value: "Widget", ID: 42}
+
+		decoded, err := DecodeCursor(c.Encode())
+		assert.NoError(t, err)
+		assert.Equal(t, &c, decoded)
+	})
+
+	t.Run("Malformed Cursor", func(t *testing.T) {
+		for _, raw := range []string{"%%%", "bm90LWpzb24", "eyJ2IjoiYSJ9"} {
+			decoded, err := DecodeCursor(raw)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			assert.Nil(t, decoded)
+		}
+	})
+}
+
+func TestProductCursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	product := &model.Product{ID: 9, Name: "Lamp", Price: 19.5, CreatedAt: createdAt}
+
+	assert.Equal(t, Cursor{Value: "Lamp", ID: 9}, ProductCursor(product, "name"))
+	assert.Equal(t, Cursor{Value: "19.5", ID: 9}, ProductCursor(product, "price"))
+	assert.Equal(t, Cursor{Value: "2024-05-01T10:30:00Z", ID: 9}, ProductCursor(product, "created_at"))
+	assert.Equal(t, Cursor{Value: "9", ID: 9}, ProductCursor(product, ""))
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	assert.Equal(t, DefaultPageLimit, normalizeLimit(0))
+	assert.Equal(t, 15, normalizeLimit(15))
+	assert.Equal(t, MaxPageLimit, normalizeLimit(500))
+}
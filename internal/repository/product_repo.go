@@ -5,37 +5,86 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
 	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
 )
 
-var ErrProductNotFound = errors.New("product not found")
+var ErrProductNotFound = apperror.New(apperror.NotFound, "product not found")
 
 const ProductTableName = "products"
 
 // ProductRepository defines the methods for interacting with the product repository.
 // The methods allow for retrieving product details, creating new products, updating existing products,
-// and deleting products.
+// and deleting products. Updates and deletes return ErrProductNotFound when
+// there is no live product with the ID. Made at a version other than 0, they
+// only apply to a product still at that version, and return
+// ErrVersionMismatch otherwise. Deleted products are only marked as deleted and can
+// be restored; reads leave them out unless the context asks for them with
+// database.WithDeleted.
 type ProductRepository interface {
 	GetProductDetail(ctx context.Context, id int) (product *model.Product, err error)
 	CreateProduct(ctx context.Context, product *model.Product) (err error)
 	UpdateProduct(ctx context.Context, pid int, product *model.Product) (err error)
-	DeleteProduct(ctx context.Context, id int) (err error)
+	DeleteProduct(ctx context.Context, id, version int) (err error)
+	RestoreProduct(ctx context.Context, id int) (err error)
+	ListProducts(ctx context.Context, filter ProductListFilter) (products []model.Product, total int64, err error)
+	SearchProducts(ctx context.Context, filter ProductSearchFilter) (results []model.ProductSearchResult, total int64, err error)
+}
+
+// productSortColumns maps the public sort keys to their column names.
+var productSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"price":      "price",
+	"created_at": "created_at",
+}
+
+// ProductListFilter holds the filtering, sorting and pagination options for ListProducts.
+// Nil filter fields are not applied. When After is set the listing continues
+// after that cursor and Offset is ignored.
+type ProductListFilter struct {
+	CategoryID *int
+	MinPrice   *float64
+	MaxPrice   *float64
+	InStock    *bool
+	SortBy     string
+	SortDesc   bool
+	Limit      int
+	Offset     int
+	After      *Cursor
+}
+
+// ProductCursor builds the cursor pointing at the given product for the given sort key.
+func ProductCursor(product *model.Product, sortBy string) Cursor {
+	var value string
+	switch sortBy {
+	case "name":
+		value = product.Name
+	case "price":
+		value = strconv.FormatFloat(product.Price, 'f', -1, 64)
+	case "created_at":
+		value = product.CreatedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		value = strconv.Itoa(product.ID)
+	}
+	return Cursor{Value: value, ID: product.ID}
 }
 
 func (r *NewRepository) GetProductDetail(ctx context.Context, id int) (product *model.Product, err error) {
 	// Implement the GetProductDetail method
-	builder := squirrel.Select("*").From(ProductTableName).Where("id = ?", id)
+	builder := r.builder().Select("*").From(ProductTableName).Where("id = ?", id).Where(notDeleted(ctx))
 	query, args, err := builder.Limit(1).ToSql()
 	if err != nil {
 		return nil, err
 	}
 
 	var products model.Product
-	err = r.db.DB.QueryRowxContext(ctx, query, args...).StructScan(&products)
+	err = r.reader(ctx).QueryRowxContext(ctx, query, args...).StructScan(&products)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrProductNotFound
@@ -45,27 +94,28 @@ func (r *NewRepository) GetProductDetail(ctx context.Context, id int) (product *
 	return &products, nil
 }
 
-// CreateProduct creates a new product in the database using the provided product data.
-// It returns an error if the creation fails.
+// CreateProduct creates a new product in the database using the provided product data
+// and sets its ID. It returns an error if the creation fails.
 func (r *NewRepository) CreateProduct(ctx context.Context, product *model.Product) (err error) {
-	builder := squirrel.Insert(ProductTableName).
+	id, err := r.insertReturningID(ctx, r.builder().Insert(ProductTableName).
 		Columns("name", "description", "price", "stock", "category_id").
-		Values(product.Name, product.Description, product.Price, product.Stock, product.CategoryID)
-
-	query, args, err := builder.ToSql()
+		Values(product.Name, product.Description, product.Price, product.Stock, product.CategoryID))
+	if isForeignKeyViolation(err) {
+		return ErrInvalidCategoryReference
+	}
 	if err != nil {
-		return fmt.Errorf("failed to build sql query: %s", err.Error())
+		return err
 	}
 
-	_, err = r.db.DB.ExecContext(ctx, query, args...)
-
-	return err
+	product.ID = int(id)
+	return nil
 }
 
-// UpdateProduct updates an existing product in the database with the provided product data.
-// It returns an error if the update fails.
+// UpdateProduct updates an existing product in the database with the provided product data,
+// moving it to its next version. When product.Version is set, the product is
+// only updated at that version. It returns an error if the update fails.
 func (r *NewRepository) UpdateProduct(ctx context.Context, pid int, product *model.Product) (err error) {
-	builder := squirrel.Update(ProductTableName)
+	builder := r.builder().Update(ProductTableName)
 
 	if product.Name != "" {
 		builder = builder.Set("name", product.Name)
@@ -76,26 +126,152 @@ func (r *NewRepository) UpdateProduct(ctx context.Context, pid int, product *mod
 	if product.Price > 0 {
 		builder = builder.Set("price", product.Price)
 	}
-	builder = builder.Where("id = ?", pid)
+	if product.CategoryID > 0 {
+		builder = builder.Set("category_id", product.CategoryID)
+	}
+	builder = builder.Set("version", nextVersion).
+		Where(byIDAt(pid, product.Version)).
+		Where(squirrel.Eq{"deleted_at": nil})
 
 	query, args, err := builder.ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build sql query: %s", err.Error())
 	}
 
-	_, err = r.db.DB.ExecContext(ctx, query, args...)
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if isForeignKeyViolation(err) {
+		return ErrInvalidCategoryReference
+	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	return r.checkWritten(ctx, ProductTableName, pid, product.Version, affected, ErrProductNotFound)
 }
 
-// DeleteProduct deletes a product from the database by the given ID.
-// It returns an error if the deletion fails.
-func (r *NewRepository) DeleteProduct(ctx context.Context, id int) (err error) {
-	builder := squirrel.Delete(ProductTableName).Where("id = ?", id)
+// DeleteProduct soft deletes a product by the given ID, at the given version
+// unless it is 0. It returns an error if the deletion fails.
+func (r *NewRepository) DeleteProduct(ctx context.Context, id, version int) (err error) {
+	deleted, err := r.softDelete(ctx, ProductTableName, byIDAt(id, version))
+	if err != nil {
+		return err
+	}
+	return r.checkWritten(ctx, ProductTableName, id, version, deleted, ErrProductNotFound)
+}
+
+// ListProducts returns one page of products matching the filter along with
+// the total number of matching products across all pages.
+func (r *NewRepository) ListProducts(ctx context.Context, filter ProductListFilter) (products []model.Product, total int64, err error) {
+	sortBy := filter.SortBy
+	column, ok := productSortColumns[sortBy]
+	if !ok {
+		sortBy, column = "id", "id"
+	}
+
+	conds := squirrel.And{}
+	if filter.CategoryID != nil {
+		conds = append(conds, squirrel.Eq{"category_id": *filter.CategoryID})
+	}
+	if filter.MinPrice != nil {
+		conds = append(conds, squirrel.GtOrEq{"price": *filter.MinPrice})
+	}
+	if filter.MaxPrice != nil {
+		conds = append(conds, squirrel.LtOrEq{"price": *filter.MaxPrice})
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			conds = append(conds, squirrel.Gt{"stock": 0})
+		} else {
+			conds = append(conds, squirrel.LtOrEq{"stock": 0})
+		}
+	}
+
+	countQuery, countArgs, err := r.builder().Select("COUNT(*)").From(ProductTableName).Where(conds).Where(notDeleted(ctx)).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build sql query: %s", err.Error())
+	}
+
+	if err = r.reader(ctx).GetContext(ctx, &total, countQuery, countArgs...); err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	builder := r.builder().Select("*").From(ProductTableName).Where(conds).Where(notDeleted(ctx))
+
+	if filter.After != nil {
+		value, err := productCursorValue(sortBy, filter.After.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+		switch {
+		case column == "id" && filter.SortDesc:
+			builder = builder.Where(squirrel.Lt{"id": filter.After.ID})
+		case column == "id":
+			builder = builder.Where(squirrel.Gt{"id": filter.After.ID})
+		case filter.SortDesc:
+			builder = builder.Where(squirrel.Or{
+				squirrel.Lt{column: value},
+				squirrel.And{squirrel.Eq{column: value}, squirrel.Lt{"id": filter.After.ID}},
+			})
+		default:
+			builder = builder.Where(squirrel.Or{
+				squirrel.Gt{column: value},
+				squirrel.And{squirrel.Eq{column: value}, squirrel.Gt{"id": filter.After.ID}},
+			})
+		}
+	} else if filter.Offset > 0 {
+		builder = builder.Offset(uint64(filter.Offset))
+	}
+
+	if column != "id" {
+		builder = builder.OrderBy(column + " " + direction)
+	}
+	builder = builder.OrderBy("id " + direction).Limit(uint64(normalizeLimit(filter.Limit)))
+
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build sql query: %s", err.Error())
+		return nil, 0, fmt.Errorf("failed to build sql query: %s", err.Error())
+	}
+
+	products = []model.Product{}
+	if err = r.reader(ctx).SelectContext(ctx, &products, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+// productCursorValue converts the string value stored in a cursor back to
+// the type of the column it was taken from.
+func productCursorValue(sortBy, raw string) (any, error) {
+	switch sortBy {
+	case "name":
+		return raw, nil
+	case "price":
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return v, nil
+	case "created_at":
+		v, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return v, nil
+	default:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return v, nil
 	}
-	_, err = r.db.DB.ExecContext(ctx, query, args...)
-	return err
 }
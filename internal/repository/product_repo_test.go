@@ -88,6 +88,7 @@ func TestRepository_CreateProduct(t *testing.T) {
 
 		err := repo.CreateProduct(ctx, product)
 		assert.NoError(t, err)
+		assert.Equal(t, 1, product.ID)
 	})
 
 	t.Run("Database Insert Error", func(t *testing.T) {
@@ -168,9 +169,12 @@ func TestRepository_UpdateProduct(t *testing.T) {
 		mock.ExpectExec("UPDATE products").
 			WithArgs(product.Name, 999).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE deleted_at IS NULL AND id = \\?").
+			WithArgs(999).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
 		err := repo.UpdateProduct(ctx, 999, product)
-		assert.NoError(t, err)
+		assert.ErrorIs(t, err, ErrProductNotFound)
 	})
 
 	t.Run("Database Error", func(t *testing.T) {
@@ -197,50 +201,136 @@ func TestRepository_DeleteProduct(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success Delete Existing Product", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM products WHERE").
-			WithArgs(1).
+		mock.ExpectExec("UPDATE products SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := repo.DeleteProduct(ctx, 1)
+		err := repo.DeleteProduct(ctx, 1, 0)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Delete Non-Existent Product", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM products WHERE").
-			WithArgs(999).
+		mock.ExpectExec("UPDATE products SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 999).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE deleted_at IS NULL AND id = \\?").
+			WithArgs(999).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		err := repo.DeleteProduct(ctx, 999)
-		assert.NoError(t, err)
+		err := repo.DeleteProduct(ctx, 999, 0)
+		assert.ErrorIs(t, err, ErrProductNotFound)
 	})
 
 	t.Run("Database Connection Error", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM products WHERE").
-			WithArgs(1).
+		mock.ExpectExec("UPDATE products SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 1).
 			WillReturnError(sql.ErrConnDone)
 
-		err := repo.DeleteProduct(ctx, 1)
+		err := repo.DeleteProduct(ctx, 1, 0)
 		assert.Error(t, err)
 		assert.Equal(t, sql.ErrConnDone, err)
 	})
 
 	t.Run("Invalid ID Format", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM products WHERE").
-			WithArgs(-1).
+		mock.ExpectExec("UPDATE products SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), -1).
 			WillReturnError(errors.New("invalid id format"))
 
-		err := repo.DeleteProduct(ctx, -1)
+		err := repo.DeleteProduct(ctx, -1, 0)
 		assert.Error(t, err)
 	})
 
 	t.Run("Database Constraint Violation", func(t *testing.T) {
 		constraintErr := errors.New("foreign key constraint violation")
-		mock.ExpectExec("DELETE FROM products WHERE").
-			WithArgs(2).
+		mock.ExpectExec("UPDATE products SET deleted_at = \\?, version = version \\+ 1 WHERE id = \\? AND deleted_at IS NULL").
+			WithArgs(sqlmock.AnyArg(), 2).
 			WillReturnError(constraintErr)
 
-		err := repo.DeleteProduct(ctx, 2)
+		err := repo.DeleteProduct(ctx, 2, 0)
 		assert.Error(t, err)
 		assert.Equal(t, constraintErr, err)
 	})
 }
+
+func TestRepository_ListProducts(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	db := &database.Database{DB: mockDB}
+	repo := &NewRepository{db: db}
+	ctx := context.Background()
+
+	t.Run("Offset Pagination With Filters", func(t *testing.T) {
+		categoryID := 3
+		minPrice := 10.0
+		inStock := true
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE \\(category_id = \\? AND price >= \\? AND stock > \\?\\)").
+			WithArgs(categoryID, minPrice, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+
+		rows := sqlmock.NewRows([]string{"id", "name", "price", "stock", "category_id"}).
+			AddRow(11, "Product 11", 15.5, 4, categoryID).
+			AddRow(12, "Product 12", 12.0, 9, categoryID)
+
+		mock.ExpectQuery("SELECT \\* FROM products WHERE .+ ORDER BY price DESC, id DESC LIMIT 10 OFFSET 10").
+			WithArgs(categoryID, minPrice, 0).
+			WillReturnRows(rows)
+
+		products, total, err := repo.ListProducts(ctx, ProductListFilter{
+			CategoryID: &categoryID,
+			MinPrice:   &minPrice,
+			InStock:    &inStock,
+			SortBy:     "price",
+			SortDesc:   true,
+			Limit:      10,
+			Offset:     10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(25), total)
+		assert.Len(t, products, 2)
+		assert.Equal(t, 11, products[0].ID)
+	})
+
+	t.Run("Cursor Pagination", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		mock.ExpectQuery("SELECT \\* FROM products WHERE .+\\(name > \\? OR \\(name = \\? AND id > \\?\\)\\) ORDER BY name ASC, id ASC LIMIT 20").
+			WithArgs("Banana", "Banana", 7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Cherry"))
+
+		products, total, err := repo.ListProducts(ctx, ProductListFilter{
+			SortBy: "name",
+			After:  &Cursor{Value: "Banana", ID: 7},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, products, 1)
+	})
+
+	t.Run("Invalid Cursor Value", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		products, _, err := repo.ListProducts(ctx, ProductListFilter{
+			SortBy: "price",
+			After:  &Cursor{Value: "not-a-number", ID: 7},
+		})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Nil(t, products)
+	})
+
+	t.Run("Count Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+			WillReturnError(errors.New("database error"))
+
+		products, total, err := repo.ListProducts(ctx, ProductListFilter{})
+		assert.Error(t, err)
+		assert.Nil(t, products)
+		assert.Equal(t, int64(0), total)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+)
+
+// ProductSearchFilter holds the search terms and pagination options for SearchProducts.
+type ProductSearchFilter struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// postgresSearchDocument is the text indexed by the GIN index on products.
+// It must match the index expression for the index to be used.
+const postgresSearchDocument = "to_tsvector('english', name || ' ' || COALESCE(description, ''))"
+
+// SearchProducts returns products whose name or description match the query,
+// ordered by relevance. On MySQL it uses the FULLTEXT index on (name, description)
+// and on PostgreSQL the full-text GIN index; SQLite falls back to LIKE matching
+// with a name-weighted score.
+func (r *NewRepository) SearchProducts(ctx context.Context, filter ProductSearchFilter) (results []model.ProductSearchResult, total int64, err error) {
+	var match, relevance squirrel.Sqlizer
+	switch r.db.Driver() {
+	case database.DriverMySQL:
+		match = squirrel.Expr("MATCH(name, description) AGAINST (? IN NATURAL LANGUAGE MODE)", filter.Query)
+		relevance = match
+	case database.DriverPostgres:
+		match = squirrel.Expr(postgresSearchDocument+" @@ plainto_tsquery('english', ?)", filter.Query)
+		relevance = squirrel.Expr("ts_rank("+postgresSearchDocument+", plainto_tsquery('english', ?))", filter.Query)
+	default:
+		pattern := "%" + escapeLike(filter.Query) + "%"
+		match = squirrel.Or{
+			squirrel.Expr("name LIKE ? ESCAPE '\\'", pattern),
+			squirrel.Expr("description LIKE ? ESCAPE '\\'", pattern),
+		}
+		relevance = squirrel.Expr(
+			"(CASE WHEN name LIKE ? ESCAPE '\\' THEN 2 ELSE 0 END + CASE WHEN description LIKE ? ESCAPE '\\' THEN 1 ELSE 0 END)",
+			pattern, pattern,
+		)
+	}
+
+	countQuery, countArgs, err := r.builder().Select("COUNT(*)").From(ProductTableName).Where(match).Where(notDeleted(ctx)).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build sql query: %s", err.Error())
+	}
+
+	if err = r.reader(ctx).GetContext(ctx, &total, countQuery, countArgs...); err != nil {
+		return nil, 0, err
+	}
+
+	builder := r.builder().Select("*").
+		Column(squirrel.Alias(relevance, "relevance")).
+		From(ProductTableName).
+		Where(match).
+		Where(notDeleted(ctx)).
+		OrderBy("relevance DESC", "id ASC").
+		Limit(uint64(normalizeLimit(filter.Limit)))
+	if filter.Offset > 0 {
+		builder = builder.Offset(uint64(filter.Offset))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build sql query: %s", err.Error())
+	}
+
+	results = []model.ProductSearchResult{}
+	if err = r.reader(ctx).SelectContext(ctx, &results, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_SearchProducts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("MySQL Full-Text Search", func(t *testing.T) {
+		mockDB, mock, err := mocks.NewMockDBWithRegEx()
+		assert.NoError(t, err)
+		defer mockDB.Close()
+		repo := &NewRepository{db: &database.Database{DB: mockDB}}
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE MATCH\\(name, description\\) AGAINST").
+			WithArgs("wireless mouse").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		rows := sqlmock.NewRows([]string{"id", "name", "description", "relevance"}).
+			AddRow(1, "Wireless Mouse", "Ergonomic wireless mouse", 3.2).
+			AddRow(2, "Mouse Pad", "Works with any wireless mouse", 1.1)
+		mock.ExpectQuery("SELECT \\*, \\(MATCH\\(name, description\\) AGAINST .+\\) AS relevance FROM products WHERE .+ ORDER BY relevance DESC, id ASC LIMIT 10 OFFSET 10").
+			WithArgs("wireless mouse", "wireless mouse").
+			WillReturnRows(rows)
+
+		results, total, err := repo.SearchProducts(ctx, ProductSearchFilter{Query: "wireless mouse", Limit: 10, Offset: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "Wireless Mouse", results[0].Name)
+		assert.InDelta(t, 3.2, results[0].Relevance, 0.001)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("LIKE Fallback On Other Drivers", func(t *testing.T) {
+		mockDB, mock, err := mocks.NewMockDBWithRegEx()
+		assert.NoError(t, err)
+		defer mockDB.Close()
+		repo := &NewRepository{db: &database.Database{DB: sqlx.NewDb(mockDB.DB, "sqlite3")}}
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE \\(name LIKE .+ OR description LIKE .+\\)").
+			WithArgs("%50\\%%", "%50\\%%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		mock.ExpectQuery("SELECT \\*, \\(\\(CASE WHEN name LIKE .+\\)\\) AS relevance FROM products").
+			WithArgs("%50\\%%", "%50\\%%", "%50\\%%", "%50\\%%").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "relevance"}).AddRow(4, "50% off", 2))
+
+		results, total, err := repo.SearchProducts(ctx, ProductSearchFilter{Query: "50%"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, results, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mockDB, mock, err := mocks.NewMockDBWithRegEx()
+		assert.NoError(t, err)
+		defer mockDB.Close()
+		repo := &NewRepository{db: &database.Database{DB: mockDB}}
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+			WillReturnError(errors.New("database error"))
+
+		results, _, err := repo.SearchProducts(ctx, ProductSearchFilter{Query: "mouse"})
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+}
@@ -2,17 +2,46 @@
 // It includes database operations for categories, products, and other entities.
 package repository
 
-import "github.com/MitulShah1/golang-rest-api-template/package/database"
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	mysqlRowIsReferencedErr = 1451
+	mysqlNoReferencedRowErr = 1452
+	postgresFKViolation     = "23503"
+)
 
 type DBRepository interface {
 	// Product Repository
 	ProductRepository
 	// Category Repository
 	CategoryRepository
+	// User Repository
+	UserRepository
+	// Role Repository
+	RoleRepository
+	// API Key Repository
+	APIKeyRepository
+	// Soft-deleted rows
+	TrashRepository
+	// Transactions
+	Transactor
 }
 
 type NewRepository struct {
 	db *database.Database
+	// tx is set on repositories given to WithTx callbacks
+	tx *txState
 }
 
 // NewDBRepository creates a new instance of the DBRepository interface using the provided database.
@@ -22,3 +51,54 @@ func NewDBRepository(db *database.Database) DBRepository {
 		db: db,
 	}
 }
+
+// builder returns a statement builder using the placeholder format of the database driver
+func (r *NewRepository) builder() squirrel.StatementBuilderType {
+	return r.db.Builder()
+}
+
+// insertReturningID runs an insert and returns the ID of the new row.
+// PostgreSQL does not support LastInsertId, so the ID is read back with RETURNING.
+func (r *NewRepository) insertReturningID(ctx context.Context, insert squirrel.InsertBuilder) (int64, error) {
+	if r.db.Driver() == database.DriverPostgres {
+		query, args, err := insert.Suffix("RETURNING id").ToSql()
+		if err != nil {
+			return 0, err
+		}
+		var id int64
+		err = r.writer(ctx).QueryRowxContext(ctx, query, args...).Scan(&id)
+		return id, err
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// isForeignKeyViolation reports whether err is a foreign key violation on any
+// supported driver, either from writing a reference to a missing row or from
+// deleting a row that is still referenced
+func isForeignKeyViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlRowIsReferencedErr || mysqlErr.Number == mysqlNoReferencedRowErr
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresFKViolation
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		// A row deleted while still referenced is reported as a trigger
+		// constraint, with the same message
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY ||
+			strings.Contains(sqliteErr.Error(), "FOREIGN KEY constraint failed")
+	}
+	return false
+}
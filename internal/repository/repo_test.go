@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPostgresMockRepository returns a repository whose database reports the postgres driver
+func newPostgresMockRepository(t *testing.T) (*NewRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := mocks.NewMockDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	db := &database.Database{DB: sqlx.NewDb(mockDB.DB, database.DriverPostgres)}
+	return &NewRepository{db: db}, mock
+}
+
+func TestRepository_Postgres_InsertReturningID(t *testing.T) {
+	repo, mock := newPostgresMockRepository(t)
+
+	mock.ExpectQuery("INSERT INTO categories (name,parent_id,description) VALUES ($1,$2,$3) RETURNING id").
+		WithArgs("Books", nil, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	id, err := repo.CreateCategory(context.Background(), &model.Category{Name: "Books"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Postgres_DuplicateUser(t *testing.T) {
+	repo, mock := newPostgresMockRepository(t)
+
+	mock.ExpectQuery("INSERT INTO users (username,email,password_hash) VALUES ($1,$2,$3) RETURNING id").
+		WithArgs("alice", "alice@example.com", "hash").
+		WillReturnError(&pq.Error{Code: postgresUniqueViolation})
+
+	_, err := repo.CreateUser(context.Background(), &model.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"})
+	assert.ErrorIs(t, err, ErrUserExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Postgres_AddUserRole(t *testing.T) {
+	repo, mock := newPostgresMockRepository(t)
+
+	mock.ExpectExec("INSERT INTO user_roles (user_id,role) VALUES ($1,$2) ON CONFLICT DO NOTHING").
+		WithArgs(1, "admin").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.AddUserRole(context.Background(), 1, "admin"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Postgres_SearchProducts(t *testing.T) {
+	repo, mock := newPostgresMockRepository(t)
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM products WHERE " + postgresSearchDocument + " @@ plainto_tsquery('english', $1) AND deleted_at IS NULL").
+		WithArgs("shirt").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT *, (ts_rank("+postgresSearchDocument+", plainto_tsquery('english', $1))) AS relevance FROM products WHERE "+
+		postgresSearchDocument+" @@ plainto_tsquery('english', $2) AND deleted_at IS NULL ORDER BY relevance DESC, id ASC LIMIT 20").
+		WithArgs("shirt", "shirt").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "relevance"}))
+
+	results, total, err := repo.SearchProducts(context.Background(), ProductSearchFilter{Query: "shirt"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Zero(t, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
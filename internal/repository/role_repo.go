@@ -0,0 +1,87 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+)
+
+const UserRoleTableName = "user_roles"
+
+type RoleRepository interface {
+	GetUserRoles(ctx context.Context, userID int) ([]string, error)
+	SetUserRoles(ctx context.Context, userID int, roles []string) error
+	AddUserRole(ctx context.Context, userID int, role string) error
+}
+
+// GetUserRoles retrieves the roles assigned to a user, ordered by name
+func (r *NewRepository) GetUserRoles(ctx context.Context, userID int) ([]string, error) {
+	query, args, err := r.builder().Select("role").
+		From(UserRoleTableName).
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("role").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{}
+	if err := r.q().SelectContext(ctx, &roles, query, args...); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles assigned to a user in a single transaction
+func (r *NewRepository) SetUserRoles(ctx context.Context, userID int, roles []string) error {
+	return r.WithTx(ctx, func(repo DBRepository) error {
+		tx := repo.(*NewRepository).q()
+
+		query, args, err := r.builder().Delete(UserRoleTableName).Where(squirrel.Eq{"user_id": userID}).ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if len(roles) > 0 {
+			insert := r.builder().Insert(UserRoleTableName).Columns("user_id", "role")
+			for _, role := range roles {
+				insert = insert.Values(userID, role)
+			}
+			query, args, err = insert.ToSql()
+			if err != nil {
+				return err
+			}
+			if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddUserRole assigns a role to a user, doing nothing if it is already assigned
+func (r *NewRepository) AddUserRole(ctx context.Context, userID int, role string) error {
+	insert := r.builder().Insert(UserRoleTableName).
+		Columns("user_id", "role").
+		Values(userID, role)
+	if r.db.Driver() == database.DriverMySQL {
+		insert = insert.Options("IGNORE")
+	} else {
+		insert = insert.Suffix("ON CONFLICT DO NOTHING")
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
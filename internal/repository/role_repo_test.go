@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetUserRoles(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+
+	rows := sqlmock.NewRows([]string{"role"}).AddRow("admin").AddRow("editor")
+	mock.ExpectQuery("SELECT role FROM user_roles WHERE user_id = \\? ORDER BY role").
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	roles, err := repo.GetUserRoles(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin", "editor"}, roles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SetUserRoles(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM user_roles WHERE user_id = ?").
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles \\(user_id,role\\) VALUES \\(\\?,\\?\\),\\(\\?,\\?\\)").
+			WithArgs(7, "admin", 7, "editor").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.SetUserRoles(ctx, 7, []string{"admin", "editor"}))
+	})
+
+	t.Run("Clear Roles", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM user_roles WHERE user_id = ?").
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.SetUserRoles(ctx, 7, nil))
+	})
+
+	t.Run("Rollback On Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM user_roles").
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles").
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, repo.SetUserRoles(ctx, 7, []string{"admin"}), "database error")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_AddUserRole(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+
+	mock.ExpectExec("INSERT IGNORE INTO user_roles").
+		WithArgs(7, "admin").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.AddUserRole(context.Background(), 7, "admin"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+)
+
+// TrashRepository permanently removes soft-deleted products and categories
+type TrashRepository interface {
+	// PurgeDeleted hard deletes the products and categories soft deleted
+	// before the given time and returns how many of each were removed. A
+	// category still referenced by a product or category that is kept is
+	// left for a later purge.
+	PurgeDeleted(ctx context.Context, before time.Time) (products, categories int64, err error)
+}
+
+// notDeleted is the condition leaving out soft-deleted rows, or no condition
+// when ctx asks for them with database.WithDeleted
+func notDeleted(ctx context.Context) squirrel.Sqlizer {
+	if database.IncludeDeleted(ctx) {
+		return squirrel.And{}
+	}
+	return squirrel.Eq{"deleted_at": nil}
+}
+
+// softDelete marks the live rows of table matching where as deleted now and
+// returns how many were marked
+func (r *NewRepository) softDelete(ctx context.Context, table string, where squirrel.Sqlizer) (int64, error) {
+	query, args, err := r.builder().Update(table).
+		Set("deleted_at", time.Now().UTC()).
+		Set("version", nextVersion).
+		Where(where).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// restore clears the deletion mark of a soft-deleted row and reports whether
+// there was one
+func (r *NewRepository) restore(ctx context.Context, table string, id int) (bool, error) {
+	query, args, err := r.builder().Update(table).
+		Set("deleted_at", nil).
+		Set("version", nextVersion).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.NotEq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// RestoreProduct brings back a soft-deleted product. It returns
+// ErrProductNotFound when no deleted product has the ID.
+func (r *NewRepository) RestoreProduct(ctx context.Context, id int) error {
+	restored, err := r.restore(ctx, ProductTableName, id)
+	if err == nil && !restored {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// RestoreCategory brings back a soft-deleted category, without its products
+// or subcategories. It returns ErrCategoryNotFound when no deleted category
+// has the ID.
+func (r *NewRepository) RestoreCategory(ctx context.Context, id int) error {
+	restored, err := r.restore(ctx, CategoryTableName, id)
+	if err == nil && !restored {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// PurgeDeleted implements TrashRepository
+func (r *NewRepository) PurgeDeleted(ctx context.Context, before time.Time) (products, categories int64, err error) {
+	expired := squirrel.And{squirrel.NotEq{"deleted_at": nil}, squirrel.Lt{"deleted_at": before.UTC()}}
+
+	query, args, err := r.builder().Delete(ProductTableName).Where(expired).ToSql()
+	if err != nil {
+		return 0, 0, err
+	}
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	if products, err = result.RowsAffected(); err != nil {
+		return 0, 0, err
+	}
+
+	query, args, err = r.builder().Select("id").From(CategoryTableName).Where(expired).OrderBy("id").ToSql()
+	if err != nil {
+		return products, 0, err
+	}
+	var ids []int
+	if err := r.q().SelectContext(ctx, &ids, query, args...); err != nil {
+		return products, 0, err
+	}
+
+	// A parent can only go once its children are gone, so keep deleting until
+	// a pass makes no progress; what is left is still referenced
+	for len(ids) > 0 {
+		var kept []int
+		for _, id := range ids {
+			query, args, err := r.builder().Delete(CategoryTableName).Where(squirrel.Eq{"id": id}).ToSql()
+			if err != nil {
+				return products, categories, err
+			}
+			if _, err := r.writer(ctx).ExecContext(ctx, query, args...); err != nil {
+				if isForeignKeyViolation(err) {
+					kept = append(kept, id)
+					continue
+				}
+				return products, categories, err
+			}
+			categories++
+		}
+		if len(kept) == len(ids) {
+			break
+		}
+		ids = kept
+	}
+
+	return products, categories, nil
+}
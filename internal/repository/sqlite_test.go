@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"context"
+	"io/fs"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteRepository returns a repository backed by a fresh in-memory SQLite
+// database with every SQLite migration applied
+func newSQLiteRepository(t *testing.T) *NewRepository {
+	t.Helper()
+
+	db, err := database.NewDatabase(&database.DBConfig{Driver: database.DriverSQLite, DBName: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	files, err := fs.Glob(migrations.FS, "sqlite/*.up.sql")
+	require.NoError(t, err)
+	sort.Strings(files)
+	for _, file := range files {
+		script, err := fs.ReadFile(migrations.FS, file)
+		require.NoError(t, err)
+		_, err = db.DB.Exec(string(script))
+		require.NoError(t, err, file)
+	}
+
+	return &NewRepository{db: db}
+}
+
+func TestSQLite_Products(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"Clothing", "Accessories"} {
+		_, err := repo.CreateCategory(ctx, &model.Category{Name: name})
+		require.NoError(t, err)
+	}
+	for _, p := range []model.Product{
+		{Name: "Red Shirt", Description: "Cotton shirt", Price: 20, Stock: 5, CategoryID: 1},
+		{Name: "Blue Jeans", Description: "Denim, fits like a shirt", Price: 50, Stock: 0, CategoryID: 1},
+		{Name: "Green Hat", Description: "Wool hat", Price: 15, Stock: 3, CategoryID: 2},
+	} {
+		require.NoError(t, repo.CreateProduct(ctx, &p))
+	}
+
+	product, err := repo.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Red Shirt", product.Name)
+	assert.Equal(t, 20.0, product.Price)
+	assert.False(t, product.CreatedAt.IsZero())
+
+	require.NoError(t, repo.UpdateProduct(ctx, 1, &model.Product{Price: 25}))
+	product, err = repo.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, product.Price)
+
+	inStock := true
+	products, total, err := repo.ListProducts(ctx, ProductListFilter{InStock: &inStock, SortBy: "price", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, products, 1)
+	assert.Equal(t, "Green Hat", products[0].Name)
+
+	cursor := ProductCursor(&products[0], "price")
+	products, _, err = repo.ListProducts(ctx, ProductListFilter{InStock: &inStock, SortBy: "price", Limit: 1, After: &cursor})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "Red Shirt", products[0].Name)
+
+	results, total, err := repo.SearchProducts(ctx, ProductSearchFilter{Query: "shirt"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, results, 2)
+	// a match in the name ranks above a match in the description only
+	assert.Equal(t, "Red Shirt", results[0].Name)
+	assert.Greater(t, results[0].Relevance, results[1].Relevance)
+
+	require.NoError(t, repo.DeleteProduct(ctx, 1, 0))
+	_, err = repo.GetProductDetail(ctx, 1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestSQLite_Categories(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	rootID, err := repo.CreateCategory(ctx, &model.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rootID)
+
+	parent := int(rootID)
+	_, err = repo.CreateCategory(ctx, &model.Category{Name: "Shirts", ParentID: &parent})
+	require.NoError(t, err)
+	hatsID, err := repo.CreateCategory(ctx, &model.Category{Name: "Hats", ParentID: &parent})
+	require.NoError(t, err)
+
+	children, err := repo.GetCategoryChildren(ctx, parent)
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+	assert.Equal(t, "Hats", children[0].Name)
+	assert.Equal(t, parent, *children[0].ParentID)
+
+	require.NoError(t, repo.UpdateCategory(ctx, int(hatsID), &model.Category{Name: "Caps", ParentID: &parent}))
+	category, err := repo.GetCategoryByID(ctx, int(hatsID))
+	require.NoError(t, err)
+	assert.Equal(t, "Caps", category.Name)
+
+	all, err := repo.ListCategories(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	require.NoError(t, repo.DeleteCategory(ctx, int(hatsID), 0))
+	_, err = repo.GetCategoryByID(ctx, int(hatsID))
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func TestSQLite_CategoryReferences(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	rootID, err := repo.CreateCategory(ctx, &model.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	root := int(rootID)
+	shirtsID, err := repo.CreateCategory(ctx, &model.Category{Name: "Shirts", ParentID: &root})
+	require.NoError(t, err)
+	shirts := int(shirtsID)
+
+	missing := 99
+	_, err = repo.CreateCategory(ctx, &model.Category{Name: "Orphan", ParentID: &missing})
+	assert.ErrorIs(t, err, ErrInvalidCategoryReference)
+	err = repo.CreateProduct(ctx, &model.Product{Name: "Orphan", CategoryID: missing})
+	assert.ErrorIs(t, err, ErrInvalidCategoryReference)
+
+	require.NoError(t, repo.CreateProduct(ctx, &model.Product{Name: "Red Shirt", CategoryID: shirts}))
+	err = repo.UpdateProduct(ctx, 1, &model.Product{CategoryID: missing})
+	assert.ErrorIs(t, err, ErrInvalidCategoryReference)
+
+	require.NoError(t, repo.MoveCategoryProducts(ctx, shirts, root))
+	count, err := repo.CountCategoryProducts(ctx, root)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, repo.MoveCategoryChildren(ctx, root, nil))
+	shirt, err := repo.GetCategoryByID(ctx, shirts)
+	require.NoError(t, err)
+	assert.Nil(t, shirt.ParentID)
+
+	require.NoError(t, repo.DeleteCategoryProducts(ctx, []int{root, shirts}))
+	require.NoError(t, repo.DeleteCategory(ctx, root, 0))
+	count, err = repo.CountCategoryProducts(ctx, root)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestSQLite_SoftDelete(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	rootID, err := repo.CreateCategory(ctx, &model.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	root := int(rootID)
+	shirtsID, err := repo.CreateCategory(ctx, &model.Category{Name: "Shirts", ParentID: &root})
+	require.NoError(t, err)
+	shirts := int(shirtsID)
+	require.NoError(t, repo.CreateProduct(ctx, &model.Product{Name: "Red Shirt", Price: 20, CategoryID: shirts}))
+
+	require.NoError(t, repo.DeleteProduct(ctx, 1, 0))
+	_, err = repo.GetProductDetail(ctx, 1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	_, total, err := repo.ListProducts(ctx, ProductListFilter{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, total, err = repo.SearchProducts(ctx, ProductSearchFilter{Query: "shirt"})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// Deleted rows are still there for callers that ask for them
+	product, err := repo.GetProductDetail(database.WithDeleted(ctx), 1)
+	require.NoError(t, err)
+	assert.NotNil(t, product.DeletedAt)
+	_, total, err = repo.ListProducts(database.WithDeleted(ctx), ProductListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	require.NoError(t, repo.RestoreProduct(ctx, 1))
+	product, err = repo.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, product.DeletedAt)
+	assert.ErrorIs(t, repo.RestoreProduct(ctx, 1), ErrProductNotFound)
+
+	require.NoError(t, repo.DeleteCategory(ctx, shirts, 0))
+	children, err := repo.GetCategoryChildren(ctx, root)
+	require.NoError(t, err)
+	assert.Empty(t, children)
+	require.NoError(t, repo.RestoreCategory(ctx, shirts))
+	children, err = repo.GetCategoryChildren(ctx, root)
+	require.NoError(t, err)
+	assert.Len(t, children, 1)
+	assert.ErrorIs(t, repo.RestoreCategory(ctx, 99), ErrCategoryNotFound)
+}
+
+func TestSQLite_PurgeDeleted(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	rootID, err := repo.CreateCategory(ctx, &model.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	root := int(rootID)
+	shirtsID, err := repo.CreateCategory(ctx, &model.Category{Name: "Shirts", ParentID: &root})
+	require.NoError(t, err)
+	shirts := int(shirtsID)
+	hatsID, err := repo.CreateCategory(ctx, &model.Category{Name: "Hats"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateProduct(ctx, &model.Product{Name: "Red Shirt", Price: 20, CategoryID: shirts}))
+	require.NoError(t, repo.CreateProduct(ctx, &model.Product{Name: "Green Hat", Price: 15, CategoryID: int(hatsID)}))
+
+	// The parent is listed before its child, so the purge needs two passes
+	require.NoError(t, repo.DeleteCategoryProducts(ctx, []int{root, shirts}))
+	require.NoError(t, repo.DeleteCategory(ctx, root, 0))
+	require.NoError(t, repo.DeleteCategory(ctx, shirts, 0))
+	// A deleted category still holding a live product is kept
+	require.NoError(t, repo.DeleteCategory(ctx, int(hatsID), 0))
+
+	products, categories, err := repo.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, products)
+	assert.Zero(t, categories)
+
+	products, categories, err = repo.PurgeDeleted(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), products)
+	assert.Equal(t, int64(2), categories)
+
+	_, total, err := repo.ListProducts(database.WithDeleted(ctx), ProductListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	_, err = repo.GetCategoryByID(database.WithDeleted(ctx), int(hatsID))
+	assert.NoError(t, err)
+}
+
+func TestSQLite_Users(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	id, err := repo.CreateUser(ctx, &model.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	_, err = repo.CreateUser(ctx, &model.User{Username: "alice", Email: "other@example.com", PasswordHash: "hash"})
+	assert.ErrorIs(t, err, ErrUserExists)
+
+	lockUntil := time.Now().Add(time.Hour).UTC()
+	require.NoError(t, repo.RecordFailedLogin(ctx, int(id), 2, lockUntil))
+	user, err := repo.GetUserByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.FailedAttempts)
+	assert.Nil(t, user.LockedUntil)
+
+	require.NoError(t, repo.RecordFailedLogin(ctx, int(id), 2, lockUntil))
+	user, err = repo.GetUserByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, user.FailedAttempts)
+	require.NotNil(t, user.LockedUntil)
+	assert.True(t, user.IsLocked(time.Now()))
+
+	require.NoError(t, repo.UpdateUserPassword(ctx, int(id), "new-hash"))
+	user, err = repo.GetUserByID(ctx, int(id))
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", user.PasswordHash)
+	assert.Nil(t, user.LockedUntil)
+
+	now := time.Now().UTC()
+	require.NoError(t, repo.CreatePasswordResetToken(ctx, &model.PasswordResetToken{
+		TokenHash: "token", UserID: int(id), ExpiresAt: now.Add(time.Hour),
+	}))
+	token, err := repo.ConsumePasswordResetToken(ctx, "token", now)
+	require.NoError(t, err)
+	assert.Equal(t, int(id), token.UserID)
+	assert.NotNil(t, token.UsedAt)
+
+	_, err = repo.ConsumePasswordResetToken(ctx, "token", now)
+	assert.ErrorIs(t, err, ErrResetTokenNotFound)
+}
+
+func TestSQLite_Roles(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.SetUserRoles(ctx, 1, []string{"editor", "admin"}))
+	require.NoError(t, repo.AddUserRole(ctx, 1, "admin"))
+	require.NoError(t, repo.AddUserRole(ctx, 1, "viewer"))
+
+	roles, err := repo.GetUserRoles(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "editor", "viewer"}, roles)
+
+	require.NoError(t, repo.SetUserRoles(ctx, 1, nil))
+	roles, err = repo.GetUserRoles(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+}
+
+func TestSQLite_APIKeys(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	userID, err := repo.CreateUser(ctx, &model.User{Username: "svc", Email: "svc@example.com", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	keyID, err := repo.CreateAPIKey(ctx, &model.APIKey{
+		Prefix: "ak_0123456789ab", Salt: "salt", KeyHash: "hash", Name: "sync", UserID: int(userID), Scopes: "product:write",
+	})
+	require.NoError(t, err)
+
+	key, err := repo.GetAPIKeyByPrefix(ctx, "ak_0123456789ab")
+	require.NoError(t, err)
+	assert.Equal(t, int(keyID), key.ID)
+	assert.Equal(t, "svc", key.Owner)
+	assert.Equal(t, []string{"product:write"}, key.ScopeList())
+
+	usedAt := time.Now().UTC()
+	require.NoError(t, repo.TouchAPIKey(ctx, int(keyID), usedAt))
+	require.NoError(t, repo.RevokeAPIKey(ctx, int(keyID), usedAt))
+
+	keys, err := repo.ListAPIKeys(ctx, int(userID))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].LastUsedAt)
+	assert.False(t, keys[0].IsActive(time.Now()))
+
+	_, err = repo.GetAPIKeyByID(ctx, 99)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
+
+func TestSQLite_Versions(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	_, err := repo.CreateCategory(ctx, &model.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateProduct(ctx, &model.Product{Name: "Red Shirt", Price: 20, CategoryID: 1}))
+
+	product, err := repo.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, product.Version)
+
+	require.NoError(t, repo.UpdateProduct(ctx, 1, &model.Product{Name: "Blue Shirt", Version: 1}))
+	product, err = repo.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Blue Shirt", product.Name)
+	assert.Equal(t, 2, product.Version)
+
+	// A write at the version read before the update is stale
+	assert.ErrorIs(t, repo.UpdateProduct(ctx, 1, &model.Product{Name: "Green Shirt", Version: 1}), ErrVersionMismatch)
+	assert.ErrorIs(t, repo.DeleteProduct(ctx, 1, 1), ErrVersionMismatch)
+	assert.ErrorIs(t, repo.DeleteProduct(ctx, 99, 1), ErrProductNotFound)
+	// Version 0 writes whatever the version
+	require.NoError(t, repo.UpdateProduct(ctx, 1, &model.Product{Name: "Green Shirt"}))
+	require.NoError(t, repo.DeleteProduct(ctx, 1, 3))
+	assert.ErrorIs(t, repo.DeleteProduct(ctx, 1, 4), ErrProductNotFound)
+	assert.ErrorIs(t, repo.DeleteProduct(ctx, 1, 0), ErrProductNotFound)
+	assert.ErrorIs(t, repo.UpdateProduct(ctx, 1, &model.Product{Name: "Gone Shirt"}), ErrProductNotFound)
+
+	category, err := repo.GetCategoryByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, category.Version)
+	category.Name = "Apparel"
+	require.NoError(t, repo.UpdateCategory(ctx, 1, category))
+	assert.ErrorIs(t, repo.UpdateCategory(ctx, 1, category), ErrVersionMismatch)
+	assert.ErrorIs(t, repo.DeleteCategory(ctx, 1, 1), ErrVersionMismatch)
+	require.NoError(t, repo.DeleteCategory(ctx, 1, 2))
+	require.NoError(t, repo.RestoreCategory(ctx, 1))
+	category, err = repo.GetCategoryByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, category.Version)
+}
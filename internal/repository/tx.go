@@ -0,0 +1,217 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// DefaultTxRetries is how many times a transaction is retried after a
+	// deadlock or serialization failure
+	DefaultTxRetries = 3
+
+	// txRetryBackoff is the wait before the first retry, doubled for each one after
+	txRetryBackoff = 10 * time.Millisecond
+
+	mysqlDeadlockErr        = 1213
+	mysqlLockWaitTimeoutErr = 1205
+	postgresSerialization   = "40001"
+	postgresDeadlock        = "40P01"
+)
+
+// Transactor runs repository calls atomically
+type Transactor interface {
+	// WithTx calls fn with a repository whose methods all run in one
+	// transaction. The transaction commits when fn returns nil and rolls back
+	// when it returns an error or panics. Calling WithTx on the repository
+	// given to fn nests a savepoint: an error rolls back to the savepoint only,
+	// and is returned to the outer fn to handle.
+	//
+	// The outermost call retries fn after a deadlock or serialization failure,
+	// so fn must not have effects outside the database.
+	WithTx(ctx context.Context, fn func(repo DBRepository) error, opts ...TxOption) error
+}
+
+// TxOption configures a transaction started by WithTx. Options are ignored
+// on nested calls, which share the outer transaction.
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	sql.TxOptions
+	retries int
+}
+
+// WithIsolation sets the isolation level of the transaction. SQLite always
+// runs transactions serializably and ignores it.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(c *txConfig) {
+		c.Isolation = level
+	}
+}
+
+// WithReadOnly starts a read-only transaction
+func WithReadOnly() TxOption {
+	return func(c *txConfig) {
+		c.ReadOnly = true
+	}
+}
+
+// WithRetries sets how many times the transaction is retried after a deadlock
+// or serialization failure; 0 disables retries
+func WithRetries(n int) TxOption {
+	return func(c *txConfig) {
+		c.retries = n
+	}
+}
+
+// queryer is what the repository runs statements on: the database, or the
+// transaction of a repository given to a WithTx callback
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// txState is the transaction shared by a repository and its nested calls
+type txState struct {
+	tx *sqlx.Tx
+	// savepoints counts the savepoints created, to give each a unique name
+	savepoints int
+}
+
+// q returns the transaction the repository runs in, or the primary
+func (r *NewRepository) q() queryer {
+	if r.tx != nil {
+		return r.tx.tx
+	}
+	return r.db.DB
+}
+
+// reader returns where a catalog read runs: the transaction, or a read
+// replica unless the request has already written. Users, roles and API keys
+// are always read from the primary, so that a new password or a revoked key
+// takes effect at once.
+func (r *NewRepository) reader(ctx context.Context) queryer {
+	if r.tx != nil {
+		return r.tx.tx
+	}
+	return r.db.Reader(ctx)
+}
+
+// writer returns where a write runs and sends the later reads of the
+// request to the primary, so they see the write
+func (r *NewRepository) writer(ctx context.Context) queryer {
+	database.MarkWritten(ctx)
+	return r.q()
+}
+
+// WithTx implements Transactor
+func (r *NewRepository) WithTx(ctx context.Context, fn func(repo DBRepository) error, opts ...TxOption) error {
+	if r.tx != nil {
+		return r.withSavepoint(ctx, fn)
+	}
+
+	cfg := txConfig{retries: DefaultTxRetries}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	backoff := txRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := r.runTx(ctx, &cfg.TxOptions, fn)
+		if err == nil || attempt >= cfg.retries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// runTx runs fn in a new transaction
+func (r *NewRepository) runTx(ctx context.Context, opts *sql.TxOptions, fn func(repo DBRepository) error) (err error) {
+	tx, err := r.db.DB.BeginTxx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	database.MarkWritten(ctx)
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(&NewRepository{db: r.db, tx: &txState{tx: tx}}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// withSavepoint runs fn inside a savepoint of the current transaction
+func (r *NewRepository) withSavepoint(ctx context.Context, fn func(repo DBRepository) error) (err error) {
+	r.tx.savepoints++
+	name := fmt.Sprintf("sp_%d", r.tx.savepoints)
+	if _, err := r.tx.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = r.tx.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+		if err != nil {
+			if _, rbErr := r.tx.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+				err = fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(r); err != nil {
+		return err
+	}
+	_, err = r.tx.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// isRetryable reports whether err is a deadlock or serialization failure,
+// after which the whole transaction can be run again
+func isRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlockErr || mysqlErr.Number == mysqlLockWaitTimeoutErr
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresSerialization || pqErr.Code == postgresDeadlock
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+	return false
+}
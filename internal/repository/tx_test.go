@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_WithTx(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDB()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	ctx := context.Background()
+
+	t.Run("Commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE categories SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.WithTx(ctx, func(tx DBRepository) error {
+			if err := tx.DeleteProduct(ctx, 1, 0); err != nil {
+				return err
+			}
+			return tx.DeleteCategory(ctx, 2, 0)
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rollback On Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 1).WillReturnError(errors.New("boom"))
+		mock.ExpectRollback()
+
+		err := repo.WithTx(ctx, func(tx DBRepository) error {
+			return tx.DeleteProduct(ctx, 1, 0)
+		})
+		assert.EqualError(t, err, "boom")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rollback On Panic", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "bad", func() {
+			_ = repo.WithTx(ctx, func(tx DBRepository) error {
+				panic("bad")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Nested Savepoint", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 1).WillReturnError(errors.New("boom"))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.WithTx(ctx, func(tx DBRepository) error {
+			// The failed inner call is undone and the outer transaction carries on
+			inner := tx.WithTx(ctx, func(tx DBRepository) error {
+				return tx.DeleteProduct(ctx, 1, 0)
+			})
+			assert.EqualError(t, inner, "boom")
+
+			return tx.WithTx(ctx, func(tx DBRepository) error {
+				return tx.DeleteProduct(ctx, 2, 0)
+			})
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Retry On Deadlock", func(t *testing.T) {
+		deadlock := &mysql.MySQLError{Number: mysqlDeadlockErr, Message: "Deadlock found"}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 1).WillReturnError(deadlock)
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		calls := 0
+		err := repo.WithTx(ctx, func(tx DBRepository) error {
+			calls++
+			return tx.DeleteProduct(ctx, 1, 0)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No Retry When Disabled", func(t *testing.T) {
+		deadlock := &mysql.MySQLError{Number: mysqlDeadlockErr, Message: "Deadlock found"}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), 1).WillReturnError(deadlock)
+		mock.ExpectRollback()
+
+		err := repo.WithTx(ctx, func(tx DBRepository) error {
+			return tx.DeleteProduct(ctx, 1, 0)
+		}, WithRetries(0), WithIsolation(sql.LevelSerializable))
+		assert.ErrorIs(t, err, deadlock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLite_WithTx(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	err := repo.WithTx(ctx, func(tx DBRepository) error {
+		if _, err := tx.CreateCategory(ctx, &model.Category{Name: "Kept"}); err != nil {
+			return err
+		}
+		// Only the savepoint is rolled back
+		_ = tx.WithTx(ctx, func(tx DBRepository) error {
+			if _, err := tx.CreateCategory(ctx, &model.Category{Name: "Dropped"}); err != nil {
+				return err
+			}
+			return errors.New("undo")
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = repo.WithTx(ctx, func(tx DBRepository) error {
+		if _, err := tx.CreateCategory(ctx, &model.Category{Name: "Rolled back"}); err != nil {
+			return err
+		}
+		return errors.New("undo")
+	})
+	assert.Error(t, err)
+
+	categories, err := repo.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "Kept", categories[0].Name)
+}
@@ -0,0 +1,212 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var (
+	ErrUserNotFound       = apperror.New(apperror.NotFound, "user not found")
+	ErrUserExists         = apperror.New(apperror.Conflict, "username or email already in use")
+	ErrResetTokenNotFound = apperror.New(apperror.NotFound, "password reset token not found")
+)
+
+const (
+	UserTableName           = "users"
+	ResetTokenTableName     = "password_reset_tokens"
+	mysqlDuplicateEntryErr  = 1062
+	postgresUniqueViolation = "23505"
+)
+
+var userColumns = []string{"id", "username", "email", "password_hash", "failed_attempts", "locked_until", "created_at", "updated_at"}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user *model.User) (int64, error)
+	GetUserByID(ctx context.Context, id int) (*model.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	UpdateUserPassword(ctx context.Context, id int, passwordHash string) error
+	RecordFailedLogin(ctx context.Context, id, maxAttempts int, lockUntil time.Time) error
+	ResetFailedLogins(ctx context.Context, id int) error
+	CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordResetToken, error)
+}
+
+// CreateUser inserts a new user and returns its ID.
+// It returns ErrUserExists when the username or email is already taken.
+func (r *NewRepository) CreateUser(ctx context.Context, user *model.User) (int64, error) {
+	id, err := r.insertReturningID(ctx, r.builder().Insert(UserTableName).
+		Columns("username", "email", "password_hash").
+		Values(user.Username, user.Email, user.PasswordHash))
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return 0, ErrUserExists
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetUserByID retrieves a user by its ID
+func (r *NewRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	return r.getUser(ctx, squirrel.Eq{"id": id})
+}
+
+// GetUserByUsername retrieves a user by username
+func (r *NewRepository) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.getUser(ctx, squirrel.Eq{"username": username})
+}
+
+// GetUserByEmail retrieves a user by email address
+func (r *NewRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.getUser(ctx, squirrel.Eq{"email": email})
+}
+
+func (r *NewRepository) getUser(ctx context.Context, where squirrel.Eq) (*model.User, error) {
+	query, args, err := r.builder().Select(userColumns...).From(UserTableName).Where(where).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	if err := r.q().GetContext(ctx, &user, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UpdateUserPassword replaces a user's password hash and clears any lockout
+func (r *NewRepository) UpdateUserPassword(ctx context.Context, id int, passwordHash string) error {
+	query, args, err := r.builder().Update(UserTableName).
+		Set("password_hash", passwordHash).
+		Set("failed_attempts", 0).
+		Set("locked_until", nil).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+// RecordFailedLogin counts a failed login in a single statement so concurrent
+// attempts cannot undercount. Reaching maxAttempts locks the account until
+// lockUntil and restarts the count.
+func (r *NewRepository) RecordFailedLogin(ctx context.Context, id, maxAttempts int, lockUntil time.Time) error {
+	query, args, err := r.builder().Update(UserTableName).
+		Set("locked_until", squirrel.Expr("CASE WHEN failed_attempts + 1 >= ? THEN ? ELSE locked_until END", maxAttempts, lockUntil)).
+		Set("failed_attempts", squirrel.Expr("CASE WHEN failed_attempts + 1 >= ? THEN 0 ELSE failed_attempts + 1 END", maxAttempts)).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+// ResetFailedLogins clears the failed login count and any lockout after a successful login
+func (r *NewRepository) ResetFailedLogins(ctx context.Context, id int) error {
+	query, args, err := r.builder().Update(UserTableName).
+		Set("failed_attempts", 0).
+		Set("locked_until", nil).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+// CreatePasswordResetToken stores the hash of a password reset token
+func (r *NewRepository) CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error {
+	query, args, err := r.builder().Insert(ResetTokenTableName).
+		Columns("token_hash", "user_id", "expires_at").
+		Values(token.TokenHash, token.UserID, token.ExpiresAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.writer(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+// ConsumePasswordResetToken marks an unused, unexpired reset token as used and returns it.
+// The conditional update makes each token single use even under concurrent requests.
+func (r *NewRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordResetToken, error) {
+	query, args, err := r.builder().Update(ResetTokenTableName).
+		Set("used_at", now).
+		Where(squirrel.Eq{"token_hash": tokenHash, "used_at": nil}).
+		Where(squirrel.Gt{"expires_at": now}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.writer(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrResetTokenNotFound
+	}
+
+	query, args, err = r.builder().Select("token_hash", "user_id", "expires_at", "used_at", "created_at").
+		From(ResetTokenTableName).
+		Where(squirrel.Eq{"token_hash": tokenHash}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var token model.PasswordResetToken
+	if err := r.q().GetContext(ctx, &token, query, args...); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// isDuplicateEntry reports whether err is a unique constraint violation on any supported driver
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntryErr
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresUniqueViolation
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	return false
+}
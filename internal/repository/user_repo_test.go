@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_CreateUser(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	ctx := context.Background()
+	user := model.User{Username: "alice", Email: "alice@example.com", PasswordHash: "$argon2id$hash"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO users").
+			WithArgs(user.Username, user.Email, user.PasswordHash).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		id, err := repo.CreateUser(ctx, &user)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO users").
+			WithArgs(user.Username, user.Email, user.PasswordHash).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+		_, err := repo.CreateUser(ctx, &user)
+		assert.ErrorIs(t, err, ErrUserExists)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetUserByUsername(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		rows := sqlmock.NewRows(userColumns).
+			AddRow(1, "alice", "alice@example.com", "hash", 2, nil, now, now)
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE username = ?").
+			WithArgs("alice").
+			WillReturnRows(rows)
+
+		user, err := repo.GetUserByUsername(ctx, "alice")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+		assert.Equal(t, 2, user.FailedAttempts)
+		assert.Nil(t, user.LockedUntil)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE username = ?").
+			WithArgs("nobody").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetUserByUsername(ctx, "nobody")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE email = ?").
+			WithArgs("alice@example.com").
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.GetUserByEmail(ctx, "alice@example.com")
+		assert.EqualError(t, err, "database error")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_RecordFailedLogin(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	lockUntil := time.Now().Add(time.Minute)
+
+	mock.ExpectExec("UPDATE users SET locked_until = CASE WHEN failed_attempts \\+ 1 >= \\? THEN \\? ELSE locked_until END, failed_attempts = CASE (.+) WHERE id = ?").
+		WithArgs(5, lockUntil, 5, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RecordFailedLogin(context.Background(), 7, 5, lockUntil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateUserPassword(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+
+	mock.ExpectExec("UPDATE users SET password_hash = \\?, failed_attempts = \\?, locked_until = \\? WHERE id = \\?").
+		WithArgs("new-hash", 0, nil, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.UpdateUserPassword(context.Background(), 7, "new-hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ConsumePasswordResetToken(t *testing.T) {
+	mockDB, mock, err := mocks.NewMockDBWithRegEx()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	repo := &NewRepository{db: &database.Database{DB: mockDB}}
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE password_reset_tokens SET used_at = \\? WHERE token_hash = \\? AND used_at IS NULL AND expires_at > \\?").
+			WithArgs(now, "hash", now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		rows := sqlmock.NewRows([]string{"token_hash", "user_id", "expires_at", "used_at", "created_at"}).
+			AddRow("hash", 7, now.Add(time.Hour), now, now)
+		mock.ExpectQuery("SELECT (.+) FROM password_reset_tokens WHERE token_hash = ?").
+			WithArgs("hash").
+			WillReturnRows(rows)
+
+		token, err := repo.ConsumePasswordResetToken(ctx, "hash", now)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, token.UserID)
+	})
+
+	t.Run("Used Or Expired", func(t *testing.T) {
+		mock.ExpectExec("UPDATE password_reset_tokens").
+			WithArgs(now, "hash", now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := repo.ConsumePasswordResetToken(ctx, "hash", now)
+		assert.ErrorIs(t, err, ErrResetTokenNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,58 @@
+// Package repository provides data access layer for the application.
+// It includes database operations for categories, products, and other entities.
+package repository
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+)
+
+// ErrVersionMismatch is returned when a product or category is written at a
+// version other than its current one, because someone else changed it since
+// the caller read it
+var ErrVersionMismatch = apperror.New(apperror.PreconditionFailed, "row was changed since it was read")
+
+// nextVersion is the assignment moving a row to its next version, made by
+// every write to a product or category
+var nextVersion = squirrel.Expr("version + 1")
+
+// byIDAt is the condition matching the row with the ID at the given version,
+// or at any version when version is 0
+func byIDAt(id, version int) squirrel.Eq {
+	where := squirrel.Eq{"id": id}
+	if version != 0 {
+		where["version"] = version
+	}
+	return where
+}
+
+// checkWritten explains why a write at the given version to the live row of
+// table with the ID affected no row: notFound when the row is not there, and
+// ErrVersionMismatch when it is there at another version. A write that
+// affected a row is not checked.
+func (r *NewRepository) checkWritten(ctx context.Context, table string, id, version int, affected int64, notFound error) error {
+	if affected > 0 {
+		return nil
+	}
+
+	query, args, err := r.builder().Select("COUNT(*)").From(table).
+		Where(squirrel.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err := r.q().GetContext(ctx, &count, query, args...); err != nil {
+		return err
+	}
+	switch {
+	case count == 0:
+		return notFound
+	case version != 0:
+		return ErrVersionMismatch
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package response provides HTTP response utilities for the application.
+// It includes standardized response formatting and error handling.
+package response
+
+import (
+	"errors"
+	"net/http"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+)
+
+// InternalErrorMessage is what clients are told about an internal error
+const InternalErrorMessage = "Internal server error"
+
+// kindStatus is the HTTP status of each error kind
+var kindStatus = map[apperror.Kind]int{
+	apperror.NotFound:             http.StatusNotFound,
+	apperror.Conflict:             http.StatusConflict,
+	apperror.Validation:           http.StatusBadRequest,
+	apperror.Forbidden:            http.StatusForbidden,
+	apperror.PreconditionFailed:   http.StatusPreconditionFailed,
+	apperror.PreconditionRequired: http.StatusPreconditionRequired,
+	apperror.Unauthorized:         http.StatusUnauthorized,
+}
+
+// authErrors gives a kind to the sentinel errors of package/auth, which does
+// not depend on apperror. Their messages are safe to show to clients.
+var authErrors = map[error]apperror.Kind{
+	auth.ErrInvalidCredentials:  apperror.Unauthorized,
+	auth.ErrAccountLocked:       apperror.Unauthorized,
+	auth.ErrInvalidRefreshToken: apperror.Unauthorized,
+	auth.ErrRefreshTokenReused:  apperror.Unauthorized,
+}
+
+// ErrorStatus returns the HTTP status of the kind of err and the message to
+// show the client. Errors that are neither an *apperror.Error nor one of the
+// authErrors are internal: they map to 500 with a generic message, so that
+// their details do not leak.
+func ErrorStatus(err error) (status int, message string) {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		appErr, ok = asAuthError(err)
+	}
+	if !ok {
+		return http.StatusInternalServerError, InternalErrorMessage
+	}
+	status, ok = kindStatus[appErr.Kind]
+	if !ok {
+		return http.StatusInternalServerError, InternalErrorMessage
+	}
+	return status, capitalize(appErr.Message)
+}
+
+// asAuthError returns the authErrors sentinel in the chain of err as an
+// *apperror.Error of its kind
+func asAuthError(err error) (*apperror.Error, bool) {
+	for sentinel, kind := range authErrors {
+		if errors.Is(err, sentinel) {
+			return apperror.New(kind, sentinel.Error()), true
+		}
+	}
+	return nil, false
+}
+
+// capitalize upper-cases the first letter of an error message, which Go
+// writes in lower case, to make a sentence of it
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	r, size := utf8.DecodeRuneInString(message)
+	return string(unicode.ToUpper(r)) + message[size:]
+}
@@ -0,0 +1,96 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "Not Found",
+			err:         apperror.New(apperror.NotFound, "product not found"),
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Product not found",
+		},
+		{
+			name:        "Wrapped Conflict",
+			err:         fmt.Errorf("delete category: %w", apperror.New(apperror.Conflict, "category still has products")),
+			wantStatus:  http.StatusConflict,
+			wantMessage: "Category still has products",
+		},
+		{
+			name:        "Validation",
+			err:         apperror.New(apperror.Validation, "invalid pagination cursor"),
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid pagination cursor",
+		},
+		{
+			name:        "Forbidden",
+			err:         apperror.New(apperror.Forbidden, "scope not granted"),
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "Scope not granted",
+		},
+		{
+			name:        "Precondition Failed",
+			err:         ErrPreconditionFailed,
+			wantStatus:  http.StatusPreconditionFailed,
+			wantMessage: "Entity tag does not match the current version",
+		},
+		{
+			name:        "Precondition Required",
+			err:         ErrPreconditionRequired,
+			wantStatus:  http.StatusPreconditionRequired,
+			wantMessage: "Missing If-Match header",
+		},
+		{
+			name:        "Unauthorized",
+			err:         apperror.New(apperror.Unauthorized, "account is temporarily locked"),
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "Account is temporarily locked",
+		},
+		{
+			name:        "Auth Sentinel",
+			err:         fmt.Errorf("rotate: %w", auth.ErrRefreshTokenReused),
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "Refresh token reuse detected",
+		},
+		{
+			name:        "Detailed",
+			err:         fmt.Errorf("set roles: %w", apperror.Detailf(apperror.New(apperror.Validation, "unknown role"), "%q", "auditor")),
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `Unknown role: "auditor"`,
+		},
+		{
+			name:        "Internal Kind",
+			err:         apperror.New(apperror.Internal, "connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: InternalErrorMessage,
+		},
+		{
+			name:        "Plain Error",
+			err:         errors.New("dial tcp: connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: InternalErrorMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, message := ErrorStatus(tt.err)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
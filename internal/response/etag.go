@@ -0,0 +1,81 @@
+// Package response provides HTTP response utilities for the application.
+// It includes standardized response formatting and error handling.
+package response
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+)
+
+var (
+	// ErrPreconditionRequired is returned by IfMatch for a request without an
+	// If-Match header when one is required
+	ErrPreconditionRequired = apperror.New(apperror.PreconditionRequired, "missing If-Match header")
+	// ErrPreconditionFailed is returned by IfMatch for an If-Match header that
+	// cannot match any version, such as a weak entity tag
+	ErrPreconditionFailed = apperror.New(apperror.PreconditionFailed, "entity tag does not match the current version")
+	// ErrInvalidIfMatch is returned by IfMatch for an If-Match header that is
+	// neither * nor a single entity tag
+	ErrInvalidIfMatch = apperror.New(apperror.Validation, "invalid If-Match header, expected * or a single entity tag")
+)
+
+// ETag returns the strong entity tag of a resource at the given version
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatch returns the version the If-Match header of r asks a write to be
+// made at, or 0 when any version will do: for * and, unless required, for a
+// request without the header.
+func IfMatch(r *http.Request, required bool) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch {
+	case header == "" && required:
+		return 0, ErrPreconditionRequired
+	case header == "" || header == "*":
+		return 0, nil
+	case strings.Contains(header, ","):
+		return 0, ErrInvalidIfMatch
+	case strings.HasPrefix(header, "W/"):
+		// Weak tags never match under the strong comparison If-Match uses
+		return 0, ErrPreconditionFailed
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return 0, ErrInvalidIfMatch
+	}
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return 0, ErrInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		// A tag this server did not issue
+		return 0, ErrPreconditionFailed
+	}
+	return version, nil
+}
+
+// NotModified reports whether the If-None-Match header of r lists etag, or is
+// *, meaning the client already has the current representation. Tags are
+// compared weakly, as GET requests do.
+func NotModified(r *http.Request, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// SendNotModified answers 304 Not Modified with the entity tag and no body
+func SendNotModified(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusNotModified)
+}
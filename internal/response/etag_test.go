@@ -0,0 +1,73 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		required    bool
+		wantVersion int
+		wantErr     error
+	}{
+		{name: "Missing", header: ""},
+		{name: "Missing But Required", header: "", required: true, wantErr: ErrPreconditionRequired},
+		{name: "Any Version", header: "*", required: true},
+		{name: "Strong Tag", header: `"7"`, wantVersion: 7},
+		{name: "Weak Tag", header: `W/"7"`, wantErr: ErrPreconditionFailed},
+		{name: "Foreign Tag", header: `"abc"`, wantErr: ErrPreconditionFailed},
+		{name: "Unquoted Tag", header: `7`, wantErr: ErrInvalidIfMatch},
+		{name: "Tag List", header: `"7", "8"`, wantErr: ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", http.NoBody)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := IfMatch(req, tt.required)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "Missing", header: "", want: false},
+		{name: "Same Tag", header: `"3"`, want: true},
+		{name: "Weak Tag", header: `W/"3"`, want: true},
+		{name: "Other Tag", header: `"2"`, want: false},
+		{name: "Tag List", header: `"1", "3"`, want: true},
+		{name: "Any", header: "*", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.Header.Set("If-None-Match", tt.header)
+			assert.Equal(t, tt.want, NotModified(req, ETag(3)))
+		})
+	}
+}
+
+func TestSendNotModified(t *testing.T) {
+	w := httptest.NewRecorder()
+	SendNotModified(w, ETag(3))
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.String())
+}
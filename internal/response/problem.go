@@ -0,0 +1,146 @@
+// Package response provides HTTP response utilities for the application.
+// It includes standardized response formatting and error handling.
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the type URI of every problem this API returns.
+// The URIs identify the kind of problem; they are not meant to be fetched.
+const ProblemTypeBase = "/problems/"
+
+// ValidationProblemType is the type of problems listing invalid request fields
+const ValidationProblemType = ProblemTypeBase + "validation-error"
+
+// problemTypes is the type URI of the problems of each status. Other statuses
+// use about:blank, whose title is the status text.
+var problemTypes = map[int]string{
+	http.StatusBadRequest:           ProblemTypeBase + "bad-request",
+	http.StatusUnauthorized:         ProblemTypeBase + "unauthorized",
+	http.StatusForbidden:            ProblemTypeBase + "forbidden",
+	http.StatusNotFound:             ProblemTypeBase + "not-found",
+	http.StatusMethodNotAllowed:     ProblemTypeBase + "method-not-allowed",
+	http.StatusConflict:             ProblemTypeBase + "conflict",
+	http.StatusPreconditionFailed:   ProblemTypeBase + "precondition-failed",
+	http.StatusPreconditionRequired: ProblemTypeBase + "precondition-required",
+	http.StatusInternalServerError:  ProblemTypeBase + "internal-error",
+	http.StatusServiceUnavailable:   ProblemTypeBase + "service-unavailable",
+}
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string                       `json:"type"`
+	Title    string                       `json:"title"`
+	Status   int                          `json:"status"`
+	Detail   string                       `json:"detail,omitempty"`
+	Instance string                       `json:"instance,omitempty"`
+	TraceID  string                       `json:"trace_id,omitempty"`
+	Errors   []validation.ValidationError `json:"errors,omitempty"`
+}
+
+// legacyEnvelope is the {success,message,data} body errors had before problem
+// details, kept for clients that still parse it
+type legacyEnvelope struct {
+	IsSuccess bool   `json:"success"`
+	Message   string `json:"message"`
+	Data      any    `json:"data"`
+}
+
+var legacyErrors atomic.Bool
+
+// UseLegacyErrors switches error responses between problem details and the
+// legacy envelope. It is set once on startup from the server configuration.
+func UseLegacyErrors(legacy bool) {
+	legacyErrors.Store(legacy)
+}
+
+// NewProblem returns the problem of a failed request with the given status.
+// r may be nil when the request is not at hand; the problem then has no
+// instance or trace ID.
+func NewProblem(r *http.Request, status int, detail string) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+	if typ, ok := problemTypes[status]; ok {
+		p.Type = typ
+	}
+	if r != nil {
+		p.Instance = r.URL.Path
+		if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+			p.TraceID = sc.TraceID().String()
+		}
+	}
+	return p
+}
+
+// SendProblem answers the request with a problem of the given status
+func SendProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	WriteProblem(w, NewProblem(r, status, detail))
+}
+
+// SendValidationProblem answers 400 Bad Request listing the invalid fields
+func SendValidationProblem(w http.ResponseWriter, r *http.Request, errs []validation.ValidationError) {
+	p := NewProblem(r, http.StatusBadRequest, "Validation error")
+	p.Type = ValidationProblemType
+	p.Title = "Your request parameters did not validate"
+	p.Errors = errs
+	WriteProblem(w, p)
+}
+
+// SendError answers the request with the status and message ErrorStatus maps
+// err to. Internal errors are logged to log, since their details are not
+// shown to the client.
+func SendError(w http.ResponseWriter, r *http.Request, log *logger.Logger, err error) {
+	status, message := ErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Error("request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+	SendProblem(w, r, status, message)
+}
+
+// WriteProblem writes p as problem details, or in the legacy envelope when
+// UseLegacyErrors is on
+func WriteProblem(w http.ResponseWriter, p *Problem) {
+	if legacyErrors.Load() {
+		res := legacyEnvelope{Message: p.Detail}
+		if p.Errors != nil {
+			res.Data = p.Errors
+		}
+		body, err := json.Marshal(res)
+		if err != nil {
+			writeMarshalFailure(w, err)
+			return
+		}
+		SendResponseRaw(w, p.Status, body)
+		return
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		writeMarshalFailure(w, err)
+		return
+	}
+	sendResponse(w, p.Status, body, ProblemContentType)
+}
+
+// writeMarshalFailure answers 500 with a fixed body for a problem that could
+// not be encoded
+func writeMarshalFailure(w http.ResponseWriter, err error) {
+	logger.NewLogger(logger.DefaultOptions()).Error(`failed to marshal problem`, `error`, err.Error())
+	sendResponse(w, http.StatusInternalServerError,
+		[]byte(`{"type":"`+problemTypes[http.StatusInternalServerError]+`","title":"Internal Server Error","status":500}`),
+		ProblemContentType)
+}
@@ -0,0 +1,132 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/MitulShah1/golang-rest-api-template/package/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSendProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/product/7?fields=name", http.NoBody)
+	w := httptest.NewRecorder()
+
+	SendProblem(w, req, http.StatusNotFound, "Product not found")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "/problems/not-found",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "Product not found",
+		"instance": "/api/v1/product/7"
+	}`, w.Body.String())
+}
+
+func TestSendProblem_TraceID(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/product/7", http.NoBody).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	SendProblem(w, req, http.StatusInternalServerError, InternalErrorMessage)
+
+	var problem Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", problem.TraceID)
+	assert.Equal(t, "/problems/internal-error", problem.Type)
+}
+
+func TestSendProblem_UnknownStatus(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products", http.NoBody)
+	w := httptest.NewRecorder()
+
+	SendProblem(w, req, http.StatusTooManyRequests, "Slow down")
+
+	var problem Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Too Many Requests", problem.Title)
+}
+
+func TestSendValidationProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/create-product", http.NoBody)
+	w := httptest.NewRecorder()
+
+	SendValidationProblem(w, req, []validation.ValidationError{
+		{Field: "Name", Message: "The field Name is required"},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var problem Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, ValidationProblemType, problem.Type)
+	assert.Equal(t, []validation.ValidationError{{Field: "Name", Message: "The field Name is required"}}, problem.Errors)
+}
+
+func TestSendError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/category/3", http.NoBody)
+	w := httptest.NewRecorder()
+
+	log := logger.NewLogger(logger.DefaultOptions())
+	SendError(w, req, log, apperror.New(apperror.Conflict, "category still has products or subcategories"))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var problem Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, "Category still has products or subcategories", problem.Detail)
+
+	w = httptest.NewRecorder()
+	SendError(w, req, log, errors.New("connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, InternalErrorMessage, problem.Detail)
+}
+
+func TestLegacyErrors(t *testing.T) {
+	UseLegacyErrors(true)
+	t.Cleanup(func() { UseLegacyErrors(false) })
+
+	t.Run("Problem", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/product/7", http.NoBody)
+		w := httptest.NewRecorder()
+
+		SendProblem(w, req, http.StatusNotFound, "Product not found")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"success":false,"message":"Product not found","data":null}`, w.Body.String())
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/create-product", http.NoBody)
+		w := httptest.NewRecorder()
+
+		SendValidationProblem(w, req, []validation.ValidationError{
+			{Field: "Name", Message: "The field Name is required"},
+		})
+
+		assert.JSONEq(t, `{
+			"success": false,
+			"message": "Validation error",
+			"data": [{"field": "Name", "message": "The field Name is required"}]
+		}`, w.Body.String())
+	})
+}
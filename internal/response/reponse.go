@@ -48,24 +48,8 @@ func Success(w http.ResponseWriter, status int, message string, data any) {
 	SendResponseRaw(w, status, jsonData)
 }
 
-// Error sends an error JSON response
+// Error sends an error response for a request that is not at hand, so the
+// problem has no instance or trace ID. Handlers use SendProblem instead.
 func Error(w http.ResponseWriter, status int, message string) {
-	response := map[string]any{
-		"success": false,
-		"message": message,
-		"error":   true,
-	}
-
-	jsonData, err := json.Marshal(response)
-	if err != nil {
-		// Fallback to plain text if JSON marshaling fails
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(status)
-		if _, err := w.Write([]byte(message)); err != nil {
-			logger.NewLogger(logger.DefaultOptions()).Error(`failed to write fallback response`, `error`, err.Error())
-		}
-		return
-	}
-
-	SendResponseRaw(w, status, jsonData)
+	WriteProblem(w, NewProblem(nil, status, message))
 }
@@ -0,0 +1,217 @@
+// Package apikey provides business logic for API keys.
+// It includes issuing, listing and revoking keys and verifying presented keys.
+package apikey
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+)
+
+// lastUsedInterval limits how often a key's last-used time is written
+const lastUsedInterval = time.Minute
+
+var (
+	ErrUnknownScope    = apperror.New(apperror.Validation, "unknown scope")
+	ErrScopeNotGranted = apperror.New(apperror.Forbidden, "scope not granted to the key owner")
+	ErrInvalidExpiry   = apperror.New(apperror.Validation, "expiry must be in the future")
+)
+
+type APIKeyServiceInterface interface {
+	CreateAPIKey(ctx context.Context, owner *auth.Principal, req model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, username string) ([]model.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, caller *auth.Principal, id int) error
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+type APIKeyService struct {
+	repo   repository.DBRepository
+	logger *logger.Logger
+	policy *auth.Policy
+	now    func() time.Time
+}
+
+func NewAPIKeyService(repo repository.DBRepository, logger *logger.Logger, policy *auth.Policy) *APIKeyService {
+	if policy == nil {
+		policy = auth.DefaultPolicy()
+	}
+	return &APIKeyService{
+		repo:   repo,
+		logger: logger,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// CreateAPIKey issues a key for the owner. Every scope must be a known
+// permission that the owner holds, or "*" for all of the owner's permissions,
+// so a key never exceeds its owner.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, owner *auth.Principal, req model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		perm := auth.Permission(scope)
+		if !auth.IsKnownPermission(perm) {
+			return nil, apperror.Detailf(ErrUnknownScope, "%q", scope)
+		}
+		if perm != auth.PermAll && !owner.HasPermission(perm) {
+			return nil, apperror.Detailf(ErrScopeNotGranted, "%q", scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, owner.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	key, prefix, secret, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	salt, err := auth.NewAPIKeySalt()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &sqlModel.APIKey{
+		Prefix:    prefix,
+		Salt:      salt,
+		KeyHash:   auth.HashAPIKey(salt, secret),
+		Name:      req.Name,
+		UserID:    user.ID,
+		Owner:     user.Username,
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: s.now(),
+	}
+	id, err := s.repo.CreateAPIKey(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	record.ID = int(id)
+
+	s.logger.Info("API key created", "id", id, "prefix", prefix, "owner", user.Username, "scopes", scopes)
+	return &model.CreateAPIKeyResponse{APIKeyResponse: toResponse(record), Key: key}, nil
+}
+
+// ListAPIKeys returns the keys owned by a user, including revoked and expired ones
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, username string) ([]model.APIKeyResponse, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.ListAPIKeys(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		res = append(res, toResponse(&keys[i]))
+	}
+	return res, nil
+}
+
+// RevokeAPIKey revokes a key owned by the caller. Callers allowed to manage users
+// may revoke any key; for everyone else other users' keys are not found.
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, caller *auth.Principal, id int) error {
+	key, err := s.repo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.Owner != caller.Subject && !caller.HasPermission(auth.PermUserManage) {
+		return repository.ErrAPIKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.repo.RevokeAPIKey(ctx, id, s.now()); err != nil {
+		return err
+	}
+
+	s.logger.Info("API key revoked", "id", id, "prefix", key.Prefix, "by", caller.Subject)
+	return nil
+}
+
+// VerifyAPIKey checks a presented key and returns a principal for its owner.
+// The principal's permissions are the key's scopes limited to what the owner's
+// roles currently grant, so demoting the owner also narrows their keys.
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	prefix, secret, err := auth.ParseAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if !auth.VerifyAPIKey(record.Salt, secret, record.KeyHash) || !record.IsActive(now) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	roles, err := s.repo.GetUserRoles(ctx, record.UserID)
+	if err != nil {
+		return nil, err
+	}
+	owner := &auth.Principal{Permissions: s.policy.Permissions(roles)}
+
+	var perms []auth.Permission
+	for _, scope := range record.ScopeList() {
+		perm := auth.Permission(scope)
+		switch {
+		case perm == auth.PermAll:
+			perms = append(perms, owner.Permissions...)
+		case owner.HasPermission(perm):
+			perms = append(perms, perm)
+		}
+	}
+
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= lastUsedInterval {
+		if err := s.repo.TouchAPIKey(ctx, record.ID, now); err != nil {
+			s.logger.Error("error while recording API key use", err)
+		}
+	}
+
+	return &auth.Principal{
+		Subject:     record.Owner,
+		Permissions: perms,
+		Method:      auth.MethodAPIKey,
+	}, nil
+}
+
+func toResponse(key *sqlModel.APIKey) model.APIKeyResponse {
+	return model.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Owner:      key.Owner,
+		Scopes:     key.ScopeList(),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
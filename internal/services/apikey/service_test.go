@@ -0,0 +1,261 @@
+package apikey
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/apikey/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPIKeyRepo keeps users and keys in memory; unimplemented methods panic via the nil embedded interface.
+type fakeAPIKeyRepo struct {
+	repository.DBRepository
+	mu      sync.Mutex
+	users   map[string]int
+	roles   map[int][]string
+	keys    map[int]*sqlModel.APIKey
+	touches int
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{
+		users: map[string]int{"alice": 1, "bob": 2},
+		roles: map[int][]string{1: {auth.RoleEditor}, 2: {auth.RoleEditor}},
+		keys:  map[int]*sqlModel.APIKey{},
+	}
+}
+
+func (f *fakeAPIKeyRepo) GetUserByUsername(_ context.Context, username string) (*sqlModel.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id, ok := f.users[username]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return &sqlModel.User{ID: id, Username: username}, nil
+}
+
+func (f *fakeAPIKeyRepo) GetUserRoles(_ context.Context, userID int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.roles[userID], nil
+}
+
+func (f *fakeAPIKeyRepo) CreateAPIKey(_ context.Context, key *sqlModel.APIKey) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k := *key
+	k.ID = len(f.keys) + 1
+	f.keys[k.ID] = &k
+	return int64(k.ID), nil
+}
+
+func (f *fakeAPIKeyRepo) GetAPIKeyByID(_ context.Context, id int) (*sqlModel.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k, ok := f.keys[id]
+	if !ok {
+		return nil, repository.ErrAPIKeyNotFound
+	}
+	c := *k
+	return &c, nil
+}
+
+func (f *fakeAPIKeyRepo) GetAPIKeyByPrefix(_ context.Context, prefix string) (*sqlModel.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, k := range f.keys {
+		if k.Prefix == prefix {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (f *fakeAPIKeyRepo) ListAPIKeys(_ context.Context, userID int) ([]sqlModel.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := []sqlModel.APIKey{}
+	for id := len(f.keys); id > 0; id-- {
+		if k := f.keys[id]; k.UserID == userID {
+			keys = append(keys, *k)
+		}
+	}
+	return keys, nil
+}
+
+func (f *fakeAPIKeyRepo) RevokeAPIKey(_ context.Context, id int, revokedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[id].RevokedAt = &revokedAt
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) TouchAPIKey(_ context.Context, id int, usedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[id].LastUsedAt = &usedAt
+	f.touches++
+	return nil
+}
+
+func newTestAPIKeyService() (*APIKeyService, *fakeAPIKeyRepo) {
+	repo := newFakeAPIKeyRepo()
+	return NewAPIKeyService(repo, logger.NewLogger(logger.DefaultOptions()), auth.DefaultPolicy()), repo
+}
+
+func editor(username string) *auth.Principal {
+	return &auth.Principal{
+		Subject:     username,
+		Roles:       []string{auth.RoleEditor},
+		Permissions: auth.DefaultPolicy().Permissions([]string{auth.RoleEditor}),
+		Method:      auth.MethodJWT,
+	}
+}
+
+func TestAPIKeyService_CreateAndVerify(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	ctx := context.Background()
+
+	created, err := svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{
+		Name:   "ci",
+		Scopes: []string{string(auth.PermProductWrite), string(auth.PermProductWrite)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", created.Owner)
+	assert.Equal(t, []string{string(auth.PermProductWrite)}, created.Scopes)
+
+	stored := repo.keys[created.ID]
+	assert.Equal(t, created.Prefix, stored.Prefix)
+	assert.NotContains(t, stored.KeyHash, created.Key)
+	assert.NotContains(t, created.Key, stored.KeyHash)
+
+	principal, err := svc.VerifyAPIKey(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", principal.Subject)
+	assert.Equal(t, auth.MethodAPIKey, principal.Method)
+	assert.True(t, principal.HasPermission(auth.PermProductWrite))
+	assert.False(t, principal.HasPermission(auth.PermProductDelete))
+	assert.Empty(t, principal.Roles)
+
+	_, err = svc.VerifyAPIKey(ctx, created.Prefix+".wrong-secret")
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+
+	_, err = svc.VerifyAPIKey(ctx, "ak_000000000000.secret")
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+
+	_, err = svc.VerifyAPIKey(ctx, "not-a-key")
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_CreateValidatesScopesAndExpiry(t *testing.T) {
+	svc, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	_, err := svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"product:read"}})
+	assert.ErrorIs(t, err, ErrUnknownScope)
+
+	_, err = svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{string(auth.PermCacheFlush)}})
+	assert.ErrorIs(t, err, ErrScopeNotGranted)
+
+	past := time.Now().Add(-time.Minute)
+	_, err = svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"*"}, ExpiresAt: &past})
+	assert.ErrorIs(t, err, ErrInvalidExpiry)
+}
+
+func TestAPIKeyService_VerifyRejectsExpiredAndRevokedKeys(t *testing.T) {
+	svc, _ := newTestAPIKeyService()
+	ctx := context.Background()
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	expiresAt := now.Add(time.Hour)
+	created, err := svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"*"}, ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+
+	_, err = svc.VerifyAPIKey(ctx, created.Key)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = svc.VerifyAPIKey(ctx, created.Key)
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+
+	svc.now = func() time.Time { return now }
+	require.NoError(t, svc.RevokeAPIKey(ctx, editor("alice"), created.ID))
+	_, err = svc.VerifyAPIKey(ctx, created.Key)
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_PermissionsFollowOwnerRoles(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	ctx := context.Background()
+
+	created, err := svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"*"}})
+	require.NoError(t, err)
+
+	principal, err := svc.VerifyAPIKey(ctx, created.Key)
+	require.NoError(t, err)
+	assert.True(t, principal.HasPermission(auth.PermCategoryDelete))
+	assert.False(t, principal.HasPermission(auth.PermUserManage))
+
+	repo.roles[1] = nil
+	principal, err = svc.VerifyAPIKey(ctx, created.Key)
+	require.NoError(t, err)
+	assert.False(t, principal.HasPermission(auth.PermProductWrite))
+}
+
+func TestAPIKeyService_LastUsedIsThrottled(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	ctx := context.Background()
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	created, err := svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"*"}})
+	require.NoError(t, err)
+
+	for range 3 {
+		_, err = svc.VerifyAPIKey(ctx, created.Key)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.touches)
+
+	svc.now = func() time.Time { return now.Add(lastUsedInterval) }
+	_, err = svc.VerifyAPIKey(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.touches)
+}
+
+func TestAPIKeyService_ListAndRevoke(t *testing.T) {
+	svc, _ := newTestAPIKeyService()
+	ctx := context.Background()
+
+	created, err := svc.CreateAPIKey(ctx, editor("alice"), model.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"*"}})
+	require.NoError(t, err)
+
+	keys, err := svc.ListAPIKeys(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, created.Prefix, keys[0].Prefix)
+
+	keys, err = svc.ListAPIKeys(ctx, "bob")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// other users' keys are hidden unless the caller manages users
+	assert.ErrorIs(t, svc.RevokeAPIKey(ctx, editor("bob"), created.ID), repository.ErrAPIKeyNotFound)
+	admin := &auth.Principal{Subject: "root", Permissions: []auth.Permission{auth.PermAll}}
+	require.NoError(t, svc.RevokeAPIKey(ctx, admin, created.ID))
+
+	// revoking again is a no-op
+	require.NoError(t, svc.RevokeAPIKey(ctx, editor("alice"), created.ID))
+	assert.ErrorIs(t, svc.RevokeAPIKey(ctx, editor("alice"), 99), repository.ErrAPIKeyNotFound)
+}
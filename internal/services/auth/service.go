@@ -0,0 +1,120 @@
+// Package auth provides business logic for authentication.
+// It includes login, refresh token rotation and logout on top of the JWT token manager.
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+)
+
+type AuthServiceInterface interface {
+	Login(ctx context.Context, req model.LoginRequest) (*model.TokenResponse, error)
+	Refresh(ctx context.Context, req model.RefreshRequest) (*model.TokenResponse, error)
+	Logout(ctx context.Context, req model.RefreshRequest) error
+}
+
+// RefreshStore persists refresh tokens and rotates them on use
+type RefreshStore interface {
+	Issue(ctx context.Context, p *auth.Principal) (string, error)
+	Rotate(ctx context.Context, token string) (*auth.RefreshSession, string, error)
+	Revoke(ctx context.Context, token string) error
+}
+
+// AccessTokenIssuer signs access tokens for authenticated principals
+type AccessTokenIssuer interface {
+	IssueAccessToken(p *auth.Principal) (string, time.Time, error)
+}
+
+// UserDirectory checks passwords at login and reloads the caller's roles when
+// tokens are refreshed, so role changes apply without logging in again
+type UserDirectory interface {
+	auth.CredentialVerifier
+	auth.PrincipalResolver
+}
+
+type AuthService struct {
+	tokens  AccessTokenIssuer
+	refresh RefreshStore
+	users   UserDirectory
+	logger  *logger.Logger
+}
+
+func NewAuthService(tokens AccessTokenIssuer, refresh RefreshStore, users UserDirectory, logger *logger.Logger) AuthServiceInterface {
+	return &AuthService{
+		tokens:  tokens,
+		refresh: refresh,
+		users:   users,
+		logger:  logger,
+	}
+}
+
+func (s *AuthService) Login(ctx context.Context, req model.LoginRequest) (*model.TokenResponse, error) {
+	principal, err := s.users.VerifyCredentials(ctx, req.Username, req.Password)
+	if err != nil {
+		s.logger.Warn("login failed", "username", req.Username, "error", err)
+		return nil, err
+	}
+	principal.Method = auth.MethodJWT
+
+	refreshToken, err := s.refresh.Issue(ctx, principal)
+	if err != nil {
+		s.logger.Error("error while issuing refresh token", err)
+		return nil, err
+	}
+
+	s.logger.Info("user logged in", "subject", principal.Subject)
+	return s.tokenResponse(principal, refreshToken)
+}
+
+func (s *AuthService) Refresh(ctx context.Context, req model.RefreshRequest) (*model.TokenResponse, error) {
+	session, refreshToken, err := s.refresh.Rotate(ctx, req.RefreshToken)
+	if err != nil {
+		s.logger.Warn("refresh token rejected", "error", err)
+		return nil, err
+	}
+
+	principal, err := s.users.ResolvePrincipal(ctx, session.Subject)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		// the user no longer exists, so end the session rather than hand out the rotated token
+		s.logger.Warn("refresh for unknown user", "subject", session.Subject)
+		if revokeErr := s.refresh.Revoke(ctx, refreshToken); revokeErr != nil {
+			s.logger.Error("error while revoking refresh token", revokeErr)
+		}
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		s.logger.Error("error while loading user for refresh", err)
+		return nil, err
+	}
+	principal.Method = auth.MethodJWT
+
+	return s.tokenResponse(principal, refreshToken)
+}
+
+func (s *AuthService) Logout(ctx context.Context, req model.RefreshRequest) error {
+	if err := s.refresh.Revoke(ctx, req.RefreshToken); err != nil {
+		s.logger.Warn("logout with unknown refresh token", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *AuthService) tokenResponse(principal *auth.Principal, refreshToken string) (*model.TokenResponse, error) {
+	accessToken, expiresAt, err := s.tokens.IssueAccessToken(principal)
+	if err != nil {
+		s.logger.Error("error while issuing access token", err)
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(expiresAt).Round(time.Second).Seconds()),
+	}, nil
+}
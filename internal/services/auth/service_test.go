@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/auth/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUsers knows a single user whose roles can be changed between calls
+type testUsers struct {
+	roles   []string
+	deleted bool
+}
+
+func (u *testUsers) VerifyCredentials(ctx context.Context, username, password string) (*auth.Principal, error) {
+	if password != "password" {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return u.ResolvePrincipal(ctx, username)
+}
+
+func (u *testUsers) ResolvePrincipal(_ context.Context, username string) (*auth.Principal, error) {
+	if username != "admin" || u.deleted {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Principal{Subject: "admin", Roles: u.roles}, nil
+}
+
+func newTestAuthService(t *testing.T) (AuthServiceInterface, *auth.TokenManager, *testUsers) {
+	t.Helper()
+	tokens, err := auth.NewTokenManager(auth.JWTConfig{Secret: "secret", Issuer: "test"})
+	require.NoError(t, err)
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	users := &testUsers{roles: []string{"admin"}}
+	svc := NewAuthService(tokens, auth.NewRefreshTokenStore(client, time.Hour), users, logger.NewLogger(logger.DefaultOptions()))
+	return svc, tokens, users
+}
+
+func TestAuthService_Login(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens, _ := newTestAuthService(t)
+
+	t.Run("valid credentials", func(t *testing.T) {
+		res, err := svc.Login(ctx, model.LoginRequest{Username: "admin", Password: "password"})
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer", res.TokenType)
+		assert.Equal(t, int64(auth.DefaultAccessTokenTTL.Seconds()), res.ExpiresIn)
+		assert.NotEmpty(t, res.RefreshToken)
+
+		p, err := tokens.VerifyAccessToken(res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "admin", p.Subject)
+		assert.True(t, p.HasRole("admin"))
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		_, err := svc.Login(ctx, model.LoginRequest{Username: "admin", Password: "nope"})
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+}
+
+func TestAuthService_RefreshAndLogout(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens, users := newTestAuthService(t)
+
+	login, err := svc.Login(ctx, model.LoginRequest{Username: "admin", Password: "password"})
+	require.NoError(t, err)
+
+	// role changes are picked up on refresh
+	users.roles = []string{"editor"}
+	refreshed, err := svc.Refresh(ctx, model.RefreshRequest{RefreshToken: login.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	p, err := tokens.VerifyAccessToken(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"editor"}, p.Roles)
+
+	_, err = svc.Refresh(ctx, model.RefreshRequest{RefreshToken: login.RefreshToken})
+	assert.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	// the reuse revoked the rotated token as well
+	err = svc.Logout(ctx, model.RefreshRequest{RefreshToken: refreshed.RefreshToken})
+	assert.True(t, errors.Is(err, auth.ErrInvalidRefreshToken))
+}
+
+func TestAuthService_RefreshDeletedUser(t *testing.T) {
+	ctx := context.Background()
+	svc, _, users := newTestAuthService(t)
+
+	login, err := svc.Login(ctx, model.LoginRequest{Username: "admin", Password: "password"})
+	require.NoError(t, err)
+
+	users.deleted = true
+	_, err = svc.Refresh(ctx, model.RefreshRequest{RefreshToken: login.RefreshToken})
+	assert.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+
+	users.deleted = false
+	_, err = svc.Refresh(ctx, model.RefreshRequest{RefreshToken: login.RefreshToken})
+	assert.Error(t, err, "the session must stay revoked")
+}
@@ -0,0 +1,173 @@
+// Package category provides business logic for category operations.
+// It includes service layer functionality for category management with Redis caching.
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/cachetag"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+)
+
+// DeleteCategory deletes a category, handling its products and subcategories
+// as the policy says. Everything happens in one transaction, which only goes
+// ahead while the category is at the given version unless it is 0.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int, policy model.DeletePolicy, version int) error {
+	s.logger.Info("Deleting category", "id", id, "policy", policy)
+
+	if policy == "" {
+		policy = model.DefaultDeletePolicy
+	}
+
+	// affected lists the categories whose cached entries the deletion changes.
+	// Those of the children moved by DeleteReassign are tagged with it too.
+	affected := []int{id}
+	var newParentID *int
+	err := s.repo.WithTx(ctx, func(tx repository.DBRepository) error {
+		category, err := tx.GetCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Checked up front, as moving the children away would not touch the category
+		if version != 0 && category.Version != version {
+			return repository.ErrVersionMismatch
+		}
+		switch policy {
+		case model.DeleteRestrict:
+			return deleteRestrict(ctx, tx, id, version)
+		case model.DeleteCascade:
+			ids, err := deleteCascade(ctx, tx, id, version)
+			affected = append(affected, ids...)
+			return err
+		case model.DeleteReassign:
+			if category.ParentID == nil {
+				products, err := tx.CountCategoryProducts(ctx, id)
+				if err != nil {
+					return err
+				}
+				if products > 0 {
+					return fmt.Errorf("%w: a top-level category has no parent to take its products", repository.ErrCategoryInUse)
+				}
+			} else if err := tx.MoveCategoryProducts(ctx, id, *category.ParentID); err != nil {
+				return err
+			}
+			if err := tx.MoveCategoryChildren(ctx, id, category.ParentID); err != nil {
+				return err
+			}
+			newParentID = category.ParentID
+			return tx.DeleteCategory(ctx, id, version)
+		default:
+			return model.ErrInvalidDeletePolicy
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// The products and the list pages filtered by the affected categories are
+	// tagged with them. When products moved or went, the unfiltered lists and
+	// the lists of the parent taking them have to go too.
+	s.invalidateCategoryCache(ctx, newParentID, affected...)
+	if policy != model.DeleteRestrict {
+		tags := []string{cachetag.ProductList}
+		if newParentID != nil {
+			tags = append(tags, cachetag.CategoryProducts(*newParentID))
+		}
+		if err := s.cache.InvalidateTags(ctx, tags...); err != nil {
+			s.logger.Warn("failed to invalidate product cache", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// ErrParentDeleted is returned when restoring a category whose parent is deleted
+var ErrParentDeleted = apperror.New(apperror.Conflict, "the parent category is deleted, restore it first")
+
+// RestoreCategory brings back a soft-deleted category. Its products and
+// subcategories deleted with it stay deleted and are restored one by one. It
+// returns ErrParentDeleted while the parent is deleted.
+func (s *CategoryService) RestoreCategory(ctx context.Context, id int) error {
+	s.logger.Info("Restoring category", "id", id)
+
+	var parentID *int
+	err := s.repo.WithTx(ctx, func(tx repository.DBRepository) error {
+		if err := tx.RestoreCategory(ctx, id); err != nil {
+			return err
+		}
+		category, err := tx.GetCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if category.ParentID == nil {
+			return nil
+		}
+		parentID = category.ParentID
+		if _, err := tx.GetCategoryByID(ctx, *category.ParentID); err != nil {
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return ErrParentDeleted
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The category is cached as missing since it was deleted
+	s.invalidateCategoryCache(ctx, parentID, id)
+	s.deleteMissingCache(ctx, id)
+	return nil
+}
+
+// deleteRestrict deletes a category that has no products or subcategories
+func deleteRestrict(ctx context.Context, tx repository.DBRepository, id, version int) error {
+	products, err := tx.CountCategoryProducts(ctx, id)
+	if err != nil {
+		return err
+	}
+	children, err := tx.GetCategoryChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if products > 0 || len(children) > 0 {
+		return fmt.Errorf("%w: %d products, %d subcategories", repository.ErrCategoryInUse, products, len(children))
+	}
+	return tx.DeleteCategory(ctx, id, version)
+}
+
+// deleteCascade deletes a category at the given version, all its descendants
+// and their products. It returns the IDs of the descendants.
+func deleteCascade(ctx context.Context, tx repository.DBRepository, id, version int) ([]int, error) {
+	// Collect the subtree top down, guarding against cycles in existing data
+	ids := []int{id}
+	seen := map[int]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		children, err := tx.GetCategoryChildren(ctx, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				ids = append(ids, child.ID)
+			}
+		}
+	}
+
+	if err := tx.DeleteCategoryProducts(ctx, ids); err != nil {
+		return nil, err
+	}
+	// Delete bottom up so no category is removed while a child still references it
+	for i := len(ids) - 1; i > 0; i-- {
+		if err := tx.DeleteCategory(ctx, ids[i], 0); err != nil {
+			return nil, err
+		}
+	}
+	return ids[1:], tx.DeleteCategory(ctx, id, version)
+}
@@ -0,0 +1,251 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/cachetag"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/migrations"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDeleteTestService returns a service over a migrated in-memory SQLite
+// database holding Clothing > Shirts > Polos, with a product in each
+func newDeleteTestService(t *testing.T) (*CategoryService, repository.DBRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := database.NewDatabase(&database.DBConfig{Driver: database.DriverSQLite, DBName: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	log := logger.NewLogger(logger.DefaultOptions())
+	c, err := cache.NewRedisCache(&cache.RedisConfig{Host: host, Port: port}, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	repo := repository.NewDBRepository(db)
+	var parent *int
+	for _, name := range []string{"Clothing", "Shirts", "Polos"} {
+		id, err := repo.CreateCategory(ctx, &sqlModel.Category{Name: name, ParentID: parent})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateProduct(ctx, &sqlModel.Product{Name: name + " item", CategoryID: int(id)}))
+		parent = intPtr(int(id))
+	}
+
+	return NewCategoryService(repo, log, c, Config{Load: cache.LoadOptions{NegativeTTL: time.Minute}}).(*CategoryService), repo
+}
+
+func TestCategoryService_DeleteCategoryPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Restrict", func(t *testing.T) {
+		svc, repo := newDeleteTestService(t)
+
+		err := svc.DeleteCategory(ctx, 2, model.DeleteRestrict, 0)
+		assert.ErrorIs(t, err, repository.ErrCategoryInUse)
+		_, err = repo.GetCategoryByID(ctx, 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Cascade", func(t *testing.T) {
+		svc, repo := newDeleteTestService(t)
+
+		require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteCascade, 0))
+		for _, id := range []int{2, 3} {
+			_, err := repo.GetCategoryByID(ctx, id)
+			assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+		}
+		_, total, err := repo.ListProducts(ctx, repository.ProductListFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("Reassign", func(t *testing.T) {
+		svc, repo := newDeleteTestService(t)
+
+		require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteReassign, 0))
+		polos, err := repo.GetCategoryByID(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *polos.ParentID)
+		count, err := repo.CountCategoryProducts(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("Reassign Top Level With Products", func(t *testing.T) {
+		svc, repo := newDeleteTestService(t)
+
+		err := svc.DeleteCategory(ctx, 1, model.DeleteReassign, 0)
+		assert.ErrorIs(t, err, repository.ErrCategoryInUse)
+		_, err = repo.GetCategoryByID(ctx, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		svc, _ := newDeleteTestService(t)
+
+		err := svc.DeleteCategory(ctx, 99, model.DeleteCascade, 0)
+		assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		svc, repo := newDeleteTestService(t)
+
+		err := svc.DeleteCategory(ctx, 3, model.DeleteCascade, 2)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+		// Deleting Shirts moves Polos up to Clothing, which changes its version
+		require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteReassign, 1))
+		err = svc.DeleteCategory(ctx, 3, model.DeleteCascade, 1)
+		assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+		require.NoError(t, svc.DeleteCategory(ctx, 3, model.DeleteCascade, 2))
+		_, err = repo.GetCategoryByID(ctx, 3)
+		assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	})
+}
+
+func TestCategoryService_RestoreCategory(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newDeleteTestService(t)
+
+	require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteCascade, 0))
+	_, err := svc.GetCategoryByID(ctx, 2)
+	require.ErrorIs(t, err, repository.ErrCategoryNotFound)
+
+	// Polos cannot come back while its parent Shirts is deleted
+	assert.ErrorIs(t, svc.RestoreCategory(ctx, 3), ErrParentDeleted)
+	require.NoError(t, svc.RestoreCategory(ctx, 2))
+	require.NoError(t, svc.RestoreCategory(ctx, 3))
+	assert.ErrorIs(t, svc.RestoreCategory(ctx, 3), repository.ErrCategoryNotFound)
+
+	// The restored category is no longer cached as missing
+	category, err := svc.GetCategoryByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Shirts", category.Name)
+
+	children, err := svc.GetCategoryChildren(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	assert.Equal(t, "Shirts", children[0].Name)
+
+	// Products deleted with the category stay deleted
+	count, err := repo.CountCategoryProducts(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestCategoryService_CacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newDeleteTestService(t)
+
+	// cached reports whether key is in the cache
+	cached := func(key string) bool {
+		var entry map[string]any
+		return svc.cache.Get(ctx, key, &entry) == nil
+	}
+	load := func() {
+		for id := 1; id <= 3; id++ {
+			_, err := svc.GetCategoryByID(ctx, id)
+			require.NoError(t, err)
+			_, err = svc.GetCategoryChildren(ctx, id)
+			require.NoError(t, err)
+		}
+		_, err := svc.GetCategoryTree(ctx, nil, 0)
+		require.NoError(t, err)
+	}
+
+	// Renaming Polos changes its entry and the children list of Shirts only
+	load()
+	require.NoError(t, svc.UpdateCategory(ctx, 3, model.UpdateCategoryRequest{Name: "Polo shirts", ParentID: intPtr(2), Description: "Polos"}, 0))
+	assert.False(t, cached("category:3"))
+	assert.False(t, cached("category:children:2"))
+	assert.True(t, cached("category:1"))
+	assert.True(t, cached("category:2"))
+	assert.True(t, cached("category:children:1"))
+
+	// Deleting Shirts with its subtree changes every entry of Shirts and Polos
+	// and the children list of Clothing
+	load()
+	require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteCascade, 0))
+	for _, key := range []string{"category:2", "category:3", "category:children:1", "category:children:2", "category:children:3"} {
+		assert.False(t, cached(key), key)
+	}
+	assert.True(t, cached("category:1"))
+}
+
+func TestCategoryService_ProductListInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	// seed caches a product list page for each category and an unfiltered one,
+	// tagged as the product service tags them
+	seed := func(svc *CategoryService) {
+		for id := 1; id <= 3; id++ {
+			key := fmt.Sprintf("product:list:%d", id)
+			require.NoError(t, svc.cache.SetWithTags(ctx, key, "page", time.Minute, cachetag.Category(id), cachetag.CategoryProducts(id)))
+		}
+		require.NoError(t, svc.cache.SetWithTags(ctx, "product:list:all", "page", time.Minute, cachetag.ProductList))
+	}
+	cached := func(svc *CategoryService, key string) bool {
+		var page string
+		return svc.cache.Get(ctx, key, &page) == nil
+	}
+
+	t.Run("Cascade", func(t *testing.T) {
+		svc, _ := newDeleteTestService(t)
+		seed(svc)
+		require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteCascade, 0))
+		assert.True(t, cached(svc, "product:list:1"), "the parent keeps its products")
+		assert.False(t, cached(svc, "product:list:2"))
+		assert.False(t, cached(svc, "product:list:3"))
+		assert.False(t, cached(svc, "product:list:all"))
+	})
+
+	t.Run("Reassign", func(t *testing.T) {
+		svc, _ := newDeleteTestService(t)
+		seed(svc)
+		require.NoError(t, svc.DeleteCategory(ctx, 2, model.DeleteReassign, 0))
+		assert.False(t, cached(svc, "product:list:1"), "the parent takes the products")
+		assert.False(t, cached(svc, "product:list:2"))
+		assert.True(t, cached(svc, "product:list:3"), "the moved child keeps its products")
+		assert.False(t, cached(svc, "product:list:all"))
+	})
+}
+
+func TestCategoryService_CreateCategory_NotFoundCached(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newDeleteTestService(t)
+
+	_, err := svc.GetCategoryByID(ctx, 4)
+	require.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	_, err = svc.GetCategoryChildren(ctx, 4)
+	require.ErrorIs(t, err, repository.ErrCategoryNotFound)
+
+	id, err := svc.CreateCategory(ctx, model.CreateCategoryRequest{Name: "Hats", Description: "Hats"})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), id)
+
+	category, err := svc.GetCategoryByID(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "Hats", category.Name)
+	children, err := svc.GetCategoryChildren(ctx, 4)
+	require.NoError(t, err)
+	assert.Empty(t, children)
+}
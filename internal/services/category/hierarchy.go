@@ -0,0 +1,204 @@
+// Package category provides business logic for category operations.
+// It includes service layer functionality for category management with Redis caching.
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/cachetag"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+)
+
+// ErrCategoryCycle is returned when an update would make a category its own ancestor
+var ErrCategoryCycle = apperror.New(apperror.Validation, "category cannot be its own ancestor")
+
+// GetCategoryChildren returns the direct children of a category.
+func (s *CategoryService) GetCategoryChildren(ctx context.Context, id int) ([]sqlModel.Category, error) {
+	return cache.LoadTagged(ctx, s.loader, childrenCacheKey(id), s.cfg.CacheTTL, func(ctx context.Context) ([]sqlModel.Category, error) {
+		return s.getCategoryChildren(ctx, id)
+	}, func(children []sqlModel.Category) []string {
+		// The list shows each child, so it changes with them
+		tags := []string{cachetag.Category(id)}
+		for _, child := range children {
+			tags = append(tags, cachetag.Category(child.ID))
+		}
+		return tags
+	})
+}
+
+// childrenCacheKey is the key under which the children of a category are cached
+func childrenCacheKey(id int) string {
+	return fmt.Sprintf("category:children:%d", id)
+}
+
+// getCategoryChildren reads the children of a category from the database
+func (s *CategoryService) getCategoryChildren(ctx context.Context, id int) ([]sqlModel.Category, error) {
+	// Make sure the parent exists so callers can tell "no children" from "no category"
+	if _, err := s.repo.GetCategoryByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.GetCategoryChildren(ctx, id)
+	if err != nil {
+		s.logger.Error("error while fetch category children", err)
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// GetCategoryPath returns the breadcrumb path of a category, from the root
+// category down to and including the category itself.
+func (s *CategoryService) GetCategoryPath(ctx context.Context, id int) ([]sqlModel.Category, error) {
+	var path []sqlModel.Category
+	visited := map[int]bool{}
+
+	for cur := &id; cur != nil; {
+		if visited[*cur] {
+			s.logger.Warn("category hierarchy contains a cycle", "category_id", *cur)
+			break
+		}
+		visited[*cur] = true
+
+		category, err := s.GetCategoryByID(ctx, *cur)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, *category)
+		cur = category.ParentID
+	}
+
+	slices.Reverse(path)
+	return path, nil
+}
+
+// GetCategoryTree returns the nested category tree. When rootID is nil the
+// whole forest of top-level categories is returned. A depth of 0 returns all
+// levels, otherwise only that many levels (counting the roots) are included.
+func (s *CategoryService) GetCategoryTree(ctx context.Context, rootID *int, depth int) ([]*model.CategoryTreeNode, error) {
+	root := 0
+	if rootID != nil {
+		root = *rootID
+	}
+	return cache.Load(ctx, s.loader, fmt.Sprintf("category:tree:%d:%d", root, depth), s.cfg.CacheTTL, func(ctx context.Context) ([]*model.CategoryTreeNode, error) {
+		return s.buildTree(ctx, rootID, depth)
+	}, cachetag.CategoryTree)
+}
+
+// buildTree reads every category from the database and nests them under the
+// roots of GetCategoryTree
+func (s *CategoryService) buildTree(ctx context.Context, rootID *int, depth int) ([]*model.CategoryTreeNode, error) {
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		s.logger.Error("error while list categories", err)
+		return nil, err
+	}
+
+	byID := make(map[int]sqlModel.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	childrenOf := make(map[int][]sqlModel.Category)
+	var roots []sqlModel.Category
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		if _, ok := byID[*c.ParentID]; !ok {
+			// Orphaned categories are shown at the top level rather than hidden
+			roots = append(roots, c)
+			continue
+		}
+		childrenOf[*c.ParentID] = append(childrenOf[*c.ParentID], c)
+	}
+
+	if rootID != nil {
+		c, ok := byID[*rootID]
+		if !ok {
+			return nil, repository.ErrCategoryNotFound
+		}
+		roots = []sqlModel.Category{c}
+	}
+
+	visited := map[int]bool{}
+	tree := make([]*model.CategoryTreeNode, 0, len(roots))
+	for _, c := range roots {
+		tree = append(tree, buildCategoryTree(c, childrenOf, depth, 1, visited))
+	}
+
+	return tree, nil
+}
+
+// buildCategoryTree converts a category and its descendants into tree nodes,
+// stopping at maxDepth and skipping categories already placed in the tree.
+func buildCategoryTree(c sqlModel.Category, childrenOf map[int][]sqlModel.Category, maxDepth, level int, visited map[int]bool) *model.CategoryTreeNode {
+	visited[c.ID] = true
+	node := &model.CategoryTreeNode{
+		ID:          c.ID,
+		Name:        c.Name,
+		ParentID:    c.ParentID,
+		Description: c.Description,
+		Children:    []*model.CategoryTreeNode{},
+	}
+
+	if maxDepth > 0 && level >= maxDepth {
+		return node
+	}
+
+	for _, child := range childrenOf[c.ID] {
+		if visited[child.ID] {
+			continue
+		}
+		node.Children = append(node.Children, buildCategoryTree(child, childrenOf, maxDepth, level+1, visited))
+	}
+
+	return node
+}
+
+// checkHierarchy walks up from the proposed parent and returns ErrCategoryCycle
+// if category id is found among its ancestors (or is the parent itself).
+func (s *CategoryService) checkHierarchy(ctx context.Context, id, parentID int) error {
+	visited := map[int]bool{}
+
+	for cur := &parentID; cur != nil; {
+		if *cur == id {
+			return ErrCategoryCycle
+		}
+		if visited[*cur] {
+			// An existing cycle above us that does not involve this category
+			return nil
+		}
+		visited[*cur] = true
+
+		parent, err := s.repo.GetCategoryByID(ctx, *cur)
+		if err != nil {
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return nil
+			}
+			return err
+		}
+		cur = parent.ParentID
+	}
+
+	return nil
+}
+
+// checkParent returns repository.ErrInvalidCategoryReference when the parent
+// given for a category does not exist
+func (s *CategoryService) checkParent(ctx context.Context, parentID int) error {
+	if _, err := s.repo.GetCategoryByID(ctx, parentID); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return repository.ErrInvalidCategoryReference
+		}
+		return err
+	}
+	return nil
+}
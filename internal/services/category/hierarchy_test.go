@@ -0,0 +1,101 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCategoryRepo serves categories from memory; unimplemented methods panic via the nil embedded interface.
+type fakeCategoryRepo struct {
+	repository.DBRepository
+	categories map[int]sqlModel.Category
+	err        error
+}
+
+func (f *fakeCategoryRepo) GetCategoryByID(_ context.Context, id int) (*sqlModel.Category, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	c, ok := f.categories[id]
+	if !ok {
+		return nil, repository.ErrCategoryNotFound
+	}
+	return &c, nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestCategoryService_checkHierarchy(t *testing.T) {
+	ctx := context.Background()
+	// 1 -> 2 -> 3 (3 is the deepest), plus 4 and 5 forming an existing cycle
+	repo := &fakeCategoryRepo{categories: map[int]sqlModel.Category{
+		1: {ID: 1},
+		2: {ID: 2, ParentID: intPtr(1)},
+		3: {ID: 3, ParentID: intPtr(2)},
+		4: {ID: 4, ParentID: intPtr(5)},
+		5: {ID: 5, ParentID: intPtr(4)},
+	}}
+	svc := &CategoryService{repo: repo, logger: logger.NewLogger(logger.DefaultOptions())}
+
+	t.Run("Self Parent", func(t *testing.T) {
+		assert.ErrorIs(t, svc.checkHierarchy(ctx, 2, 2), ErrCategoryCycle)
+	})
+
+	t.Run("Descendant As Parent", func(t *testing.T) {
+		assert.ErrorIs(t, svc.checkHierarchy(ctx, 1, 3), ErrCategoryCycle)
+	})
+
+	t.Run("Valid Move", func(t *testing.T) {
+		assert.NoError(t, svc.checkHierarchy(ctx, 3, 1))
+	})
+
+	t.Run("Missing Parent", func(t *testing.T) {
+		assert.NoError(t, svc.checkHierarchy(ctx, 1, 99))
+	})
+
+	t.Run("Existing Cycle Above", func(t *testing.T) {
+		assert.NoError(t, svc.checkHierarchy(ctx, 1, 4))
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		failing := &CategoryService{repo: &fakeCategoryRepo{err: errors.New("database error")}}
+		assert.Error(t, failing.checkHierarchy(ctx, 1, 2))
+	})
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	root := sqlModel.Category{ID: 1, Name: "Root"}
+	childrenOf := map[int][]sqlModel.Category{
+		1: {{ID: 2, Name: "Child", ParentID: intPtr(1)}},
+		2: {{ID: 3, Name: "Grandchild", ParentID: intPtr(2)}},
+	}
+
+	t.Run("Full Depth", func(t *testing.T) {
+		node := buildCategoryTree(root, childrenOf, 0, 1, map[int]bool{})
+		assert.Len(t, node.Children, 1)
+		assert.Len(t, node.Children[0].Children, 1)
+		assert.Equal(t, "Grandchild", node.Children[0].Children[0].Name)
+	})
+
+	t.Run("Limited Depth", func(t *testing.T) {
+		node := buildCategoryTree(root, childrenOf, 2, 1, map[int]bool{})
+		assert.Len(t, node.Children, 1)
+		assert.Empty(t, node.Children[0].Children)
+	})
+
+	t.Run("Cycle Is Not Followed", func(t *testing.T) {
+		cyclic := map[int][]sqlModel.Category{
+			1: {{ID: 2, ParentID: intPtr(1)}},
+			2: {{ID: 1}},
+		}
+		node := buildCategoryTree(root, cyclic, 0, 1, map[int]bool{})
+		assert.Len(t, node.Children, 1)
+		assert.Empty(t, node.Children[0].Children)
+	})
+}
@@ -39,13 +39,13 @@ func (_m *CategoryServiceInterface) CreateCategory(ctx context.Context, _a1 mode
 	return r0, r1
 }
 
-// DeleteCategory provides a mock function with given fields: ctx, id
-func (_m *CategoryServiceInterface) DeleteCategory(ctx context.Context, id int) error {
-	ret := _m.Called(ctx, id)
+// DeleteCategory provides a mock function with given fields: ctx, id, policy, version
+func (_m *CategoryServiceInterface) DeleteCategory(ctx context.Context, id int, policy model.DeletePolicy, version int) error {
+	ret := _m.Called(ctx, id, policy, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.DeletePolicy, int) error); ok {
+		r0 = rf(ctx, id, policy, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -105,13 +105,105 @@ func (_m *CategoryServiceInterface) GetCategoryByID(ctx context.Context, id int)
 	return r0, r1
 }
 
-// UpdateCategory provides a mock function with given fields: ctx, id, _a2
-func (_m *CategoryServiceInterface) UpdateCategory(ctx context.Context, id int, _a2 model.UpdateCategoryRequest) error {
-	ret := _m.Called(ctx, id, _a2)
+// GetCategoryChildren provides a mock function with given fields: ctx, id
+func (_m *CategoryServiceInterface) GetCategoryChildren(ctx context.Context, id int) ([]repositorymodel.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []repositorymodel.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]repositorymodel.Category, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []repositorymodel.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repositorymodel.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategoryPath provides a mock function with given fields: ctx, id
+func (_m *CategoryServiceInterface) GetCategoryPath(ctx context.Context, id int) ([]repositorymodel.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []repositorymodel.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]repositorymodel.Category, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []repositorymodel.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repositorymodel.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategoryTree provides a mock function with given fields: ctx, rootID, depth
+func (_m *CategoryServiceInterface) GetCategoryTree(ctx context.Context, rootID *int, depth int) ([]*model.CategoryTreeNode, error) {
+	ret := _m.Called(ctx, rootID, depth)
+
+	var r0 []*model.CategoryTreeNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int, int) ([]*model.CategoryTreeNode, error)); ok {
+		return rf(ctx, rootID, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int, int) []*model.CategoryTreeNode); ok {
+		r0 = rf(ctx, rootID, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.CategoryTreeNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int, int) error); ok {
+		r1 = rf(ctx, rootID, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreCategory provides a mock function with given fields: ctx, id
+func (_m *CategoryServiceInterface) RestoreCategory(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, id, _a2, version
+func (_m *CategoryServiceInterface) UpdateCategory(ctx context.Context, id int, _a2 model.UpdateCategoryRequest, version int) error {
+	ret := _m.Called(ctx, id, _a2, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, model.UpdateCategoryRequest) error); ok {
-		r0 = rf(ctx, id, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.UpdateCategoryRequest, int) error); ok {
+		r0 = rf(ctx, id, _a2, version)
 	} else {
 		r0 = ret.Error(0)
 	}
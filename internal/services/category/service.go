@@ -4,41 +4,78 @@ package category
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/cachetag"
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/category/model"
 	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
 	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
 	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 )
 
 type CategoryServiceInterface interface {
 	CreateCategory(ctx context.Context, category model.CreateCategoryRequest) (int64, error)
 	GetCategoryByID(ctx context.Context, id int) (*sqlModel.Category, error)
-	UpdateCategory(ctx context.Context, id int, category model.UpdateCategoryRequest) error
-	DeleteCategory(ctx context.Context, id int) error
+	UpdateCategory(ctx context.Context, id int, category model.UpdateCategoryRequest, version int) error
+	DeleteCategory(ctx context.Context, id int, policy model.DeletePolicy, version int) error
+	RestoreCategory(ctx context.Context, id int) error
+	GetCategoryChildren(ctx context.Context, id int) ([]sqlModel.Category, error)
+	GetCategoryPath(ctx context.Context, id int) ([]sqlModel.Category, error)
+	GetCategoryTree(ctx context.Context, rootID *int, depth int) ([]*model.CategoryTreeNode, error)
+}
+
+// DefaultCacheTTL is how long categories, children and trees are cached
+const DefaultCacheTTL = 30 * time.Minute
+
+// Config holds how long categories are cached. A zero CacheTTL uses
+// DefaultCacheTTL. Load configures how expired entries are reloaded.
+type Config struct {
+	CacheTTL time.Duration
+	Load     cache.LoadOptions
 }
 
 type CategoryService struct {
 	repo   repository.DBRepository
 	logger *logger.Logger
-	cache  *cache.Cache
+	cache  cache.Cache
+	loader *cache.Loader
+	cfg    Config
 }
 
-func NewCategoryService(repo repository.DBRepository, logger *logger.Logger, cache *cache.Cache) CategoryServiceInterface {
+func NewCategoryService(repo repository.DBRepository, logger *logger.Logger, c cache.Cache, cfg Config) CategoryServiceInterface {
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+
+	loadOpts := cfg.Load
+	loadOpts.NotFound = []error{repository.ErrCategoryNotFound}
+	// Loaded categories stay cached after the writing request, so a lagging
+	// replica must not be the source
+	loadOpts.LoadContext = database.WithPrimary
+	loadOpts.StaleOn = apperror.IsInternal
+
 	return &CategoryService{
 		repo:   repo,
 		logger: logger,
-		cache:  cache,
+		cache:  c,
+		loader: cache.NewLoader(c, loadOpts, logger),
+		cfg:    cfg,
 	}
 }
 
 func (s *CategoryService) CreateCategory(ctx context.Context, category model.CreateCategoryRequest) (int64, error) {
 	s.logger.Info("Creating category", "category", category)
 
+	if category.ParentID != nil {
+		if err := s.checkParent(ctx, *category.ParentID); err != nil {
+			return 0, err
+		}
+	}
+
 	cat := sqlModel.Category{
 		Name:        category.Name,
 		ParentID:    category.ParentID,
@@ -50,8 +87,8 @@ func (s *CategoryService) CreateCategory(ctx context.Context, category model.Cre
 		return 0, err
 	}
 
-	// Invalidate category cache patterns
-	s.invalidateCategoryCache(ctx)
+	s.invalidateCategoryCache(ctx, category.ParentID)
+	s.deleteMissingCache(ctx, int(id))
 
 	return id, nil
 }
@@ -59,16 +96,24 @@ func (s *CategoryService) CreateCategory(ctx context.Context, category model.Cre
 func (s *CategoryService) GetCategoryByID(ctx context.Context, id int) (*sqlModel.Category, error) {
 	s.logger.Info("Getting category by ID", "id", id)
 
-	// Try to get from cache first
-	cacheKey := fmt.Sprintf("category:%d", id)
-	var cachedCategory sqlModel.Category
-
-	if err := s.cache.Get(ctx, cacheKey, &cachedCategory); err == nil {
-		s.logger.Debug("category retrieved from cache", "category_id", id)
-		return &cachedCategory, nil
+	// Reads including deleted categories are not cached
+	if database.IncludeDeleted(ctx) {
+		return s.getCategoryByID(ctx, id)
 	}
+	return cache.LoadTagged(ctx, s.loader, categoryCacheKey(id), s.cfg.CacheTTL, func(ctx context.Context) (*sqlModel.Category, error) {
+		return s.getCategoryByID(ctx, id)
+	}, func(category *sqlModel.Category) []string {
+		// Deleting the parent may move the category to another one
+		tags := []string{cachetag.Category(category.ID)}
+		if category.ParentID != nil {
+			tags = append(tags, cachetag.Category(*category.ParentID))
+		}
+		return tags
+	})
+}
 
-	// Cache miss, get from database
+// getCategoryByID reads a category from the database
+func (s *CategoryService) getCategoryByID(ctx context.Context, id int) (*sqlModel.Category, error) {
 	category, err := s.repo.GetCategoryByID(ctx, id)
 	if err != nil {
 		s.logger.Error("error while fetch category information", err)
@@ -77,24 +122,31 @@ func (s *CategoryService) GetCategoryByID(ctx context.Context, id int) (*sqlMode
 
 	if category == nil {
 		s.logger.Warn("category not found", "category id", id)
-		return nil, errors.New("category not found")
-	}
-
-	// Cache the result for future requests
-	if err := s.cache.Set(ctx, cacheKey, category, 30*time.Minute); err != nil {
-		s.logger.Warn("failed to cache category", "category_id", id, "error", err)
+		return nil, repository.ErrCategoryNotFound
 	}
 
 	return category, nil
 }
 
-func (s *CategoryService) UpdateCategory(ctx context.Context, id int, category model.UpdateCategoryRequest) error {
+// UpdateCategory updates a category, at the given version unless it is 0. It
+// returns repository.ErrVersionMismatch when the category is at another version.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id int, category model.UpdateCategoryRequest, version int) error {
 	s.logger.Info("Updating category", "category", category)
 
+	if category.ParentID != nil {
+		if err := s.checkParent(ctx, *category.ParentID); err != nil {
+			return err
+		}
+		if err := s.checkHierarchy(ctx, id, *category.ParentID); err != nil {
+			return err
+		}
+	}
+
 	updCat := sqlModel.Category{
 		Name:        category.Name,
 		ParentID:    category.ParentID,
 		Description: category.Description,
+		Version:     version,
 	}
 
 	err := s.repo.UpdateCategory(ctx, id, &updCat)
@@ -102,36 +154,43 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id int, category m
 		return err
 	}
 
-	// Invalidate category cache patterns
-	s.invalidateCategoryCache(ctx)
-	if err := s.cache.Delete(ctx, fmt.Sprintf("category:%d", id)); err != nil {
-		s.logger.Warn("failed to delete category cache", "category_id", id, "error", err)
-	}
+	// The entries tagged with the category include the children list of its
+	// old parent
+	s.invalidateCategoryCache(ctx, category.ParentID, id)
 
 	return nil
 }
 
-func (s *CategoryService) DeleteCategory(ctx context.Context, id int) error {
-	s.logger.Info("Deleting category", "id", id)
-
-	err := s.repo.DeleteCategory(ctx, id)
-	if err != nil {
-		return err
+// invalidateCategoryCache removes the cached entries of the given categories
+// and every category tree. parentID, when not nil, is a category that gained a
+// child, which only changes its children list.
+func (s *CategoryService) invalidateCategoryCache(ctx context.Context, parentID *int, ids ...int) {
+	tags := []string{cachetag.CategoryTree}
+	for _, id := range ids {
+		tags = append(tags, cachetag.Category(id))
 	}
-
-	// Invalidate category cache patterns
-	s.invalidateCategoryCache(ctx)
-	if err := s.cache.Delete(ctx, fmt.Sprintf("category:%d", id)); err != nil {
-		s.logger.Warn("failed to delete category cache", "category_id", id, "error", err)
+	if err := s.cache.InvalidateTags(ctx, tags...); err != nil {
+		s.logger.Warn("failed to invalidate category cache", "category_ids", ids, "error", err)
+	}
+	if parentID != nil {
+		if err := s.cache.Delete(ctx, childrenCacheKey(*parentID)); err != nil {
+			s.logger.Warn("failed to delete category children cache", "category_id", *parentID, "error", err)
+		}
 	}
+}
 
-	return nil
+// categoryCacheKey is the key under which a category is cached
+func categoryCacheKey(id int) string {
+	return fmt.Sprintf("category:%d", id)
 }
 
-// invalidateCategoryCache removes all category-related cache entries
-func (s *CategoryService) invalidateCategoryCache(ctx context.Context) {
-	// Delete all category cache patterns
-	if err := s.cache.DeletePattern(ctx, "category:*"); err != nil {
-		s.logger.Warn("failed to invalidate category cache", "error", err)
+// deleteMissingCache removes the entries remembering that a category and its
+// children do not exist, for a category that was created or restored. The
+// trees are tagged and removed by invalidateCategoryCache.
+func (s *CategoryService) deleteMissingCache(ctx context.Context, id int) {
+	for _, key := range []string{categoryCacheKey(id), childrenCacheKey(id)} {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			s.logger.Warn("failed to delete category cache", "key", key, "error", err)
+		}
 	}
 }
@@ -124,18 +124,18 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
-		mockRepo.On("DeleteCategory", ctx, 1).Return(nil)
+		mockRepo.On("DeleteCategory", ctx, 1, model.DeleteRestrict, 0).Return(nil)
 
-		err := mockRepo.DeleteCategory(ctx, 1)
+		err := mockRepo.DeleteCategory(ctx, 1, model.DeleteRestrict, 0)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Category In Use", func(t *testing.T) {
-		mockRepo.On("DeleteCategory", ctx, 2).Return(errors.New("category is referenced by other entities"))
+		mockRepo.On("DeleteCategory", ctx, 2, model.DeleteRestrict, 0).Return(errors.New("category is referenced by other entities"))
 
-		err := mockRepo.DeleteCategory(ctx, 2)
+		err := mockRepo.DeleteCategory(ctx, 2, model.DeleteRestrict, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "category is referenced by other entities")
@@ -143,9 +143,9 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	})
 
 	t.Run("Non-existent Category", func(t *testing.T) {
-		mockRepo.On("DeleteCategory", ctx, 999).Return(errors.New("category not found"))
+		mockRepo.On("DeleteCategory", ctx, 999, model.DeleteRestrict, 0).Return(errors.New("category not found"))
 
-		err := mockRepo.DeleteCategory(ctx, 999)
+		err := mockRepo.DeleteCategory(ctx, 999, model.DeleteRestrict, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "category not found")
@@ -153,9 +153,9 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	})
 
 	t.Run("Database Connection Error", func(t *testing.T) {
-		mockRepo.On("DeleteCategory", ctx, 3).Return(errors.New("database connection failed"))
+		mockRepo.On("DeleteCategory", ctx, 3, model.DeleteRestrict, 0).Return(errors.New("database connection failed"))
 
-		err := mockRepo.DeleteCategory(ctx, 3)
+		err := mockRepo.DeleteCategory(ctx, 3, model.DeleteRestrict, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "database connection failed")
@@ -163,9 +163,9 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	})
 
 	t.Run("Invalid Category ID", func(t *testing.T) {
-		mockRepo.On("DeleteCategory", ctx, -1).Return(errors.New("invalid category ID"))
+		mockRepo.On("DeleteCategory", ctx, -1, model.DeleteRestrict, 0).Return(errors.New("invalid category ID"))
 
-		err := mockRepo.DeleteCategory(ctx, -1)
+		err := mockRepo.DeleteCategory(ctx, -1, model.DeleteRestrict, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid category ID")
@@ -184,9 +184,9 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 			Description: "Updated Description",
 		}
 
-		mockRepo.On("UpdateCategory", ctx, 1, updateReq).Return(nil)
+		mockRepo.On("UpdateCategory", ctx, 1, updateReq, 0).Return(nil)
 
-		err := mockRepo.UpdateCategory(ctx, 1, updateReq)
+		err := mockRepo.UpdateCategory(ctx, 1, updateReq, 0)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -198,9 +198,9 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 			Description: "",
 		}
 
-		mockRepo.On("UpdateCategory", ctx, 2, updateReq).Return(nil)
+		mockRepo.On("UpdateCategory", ctx, 2, updateReq, 0).Return(nil)
 
-		err := mockRepo.UpdateCategory(ctx, 2, updateReq)
+		err := mockRepo.UpdateCategory(ctx, 2, updateReq, 0)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -209,9 +209,9 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 	t.Run("Update with Empty Fields", func(t *testing.T) {
 		updateReq := model.UpdateCategoryRequest{}
 
-		mockRepo.On("UpdateCategory", ctx, 4, updateReq).Return(errors.New("invalid update data"))
+		mockRepo.On("UpdateCategory", ctx, 4, updateReq, 0).Return(errors.New("invalid update data"))
 
-		err := mockRepo.UpdateCategory(ctx, 4, updateReq)
+		err := mockRepo.UpdateCategory(ctx, 4, updateReq, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid update data")
@@ -223,9 +223,9 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 			Name: "Non-existent Category",
 		}
 
-		mockRepo.On("UpdateCategory", ctx, 999, updateReq).Return(errors.New("category not found"))
+		mockRepo.On("UpdateCategory", ctx, 999, updateReq, 0).Return(errors.New("category not found"))
 
-		err := mockRepo.UpdateCategory(ctx, 999, updateReq)
+		err := mockRepo.UpdateCategory(ctx, 999, updateReq, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "category not found")
@@ -239,9 +239,9 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 			ParentID: &parentID,
 		}
 
-		mockRepo.On("UpdateCategory", ctx, 5, updateReq).Return(errors.New("invalid parent ID"))
+		mockRepo.On("UpdateCategory", ctx, 5, updateReq, 0).Return(errors.New("invalid parent ID"))
 
-		err := mockRepo.UpdateCategory(ctx, 5, updateReq)
+		err := mockRepo.UpdateCategory(ctx, 5, updateReq, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid parent ID")
@@ -28,13 +28,13 @@ func (_m *ProductServiceInterface) CreateProduct(ctx context.Context, _a1 model.
 	return r0
 }
 
-// DeleteProduct provides a mock function with given fields: ctx, id
-func (_m *ProductServiceInterface) DeleteProduct(ctx context.Context, id int) error {
-	ret := _m.Called(ctx, id)
+// DeleteProduct provides a mock function with given fields: ctx, id, version
+func (_m *ProductServiceInterface) DeleteProduct(ctx context.Context, id int, version int) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -68,13 +68,79 @@ func (_m *ProductServiceInterface) GetProductDetail(ctx context.Context, id int)
 	return r0, r1
 }
 
-// UpdateProduct provides a mock function with given fields: ctx, pid, _a2
-func (_m *ProductServiceInterface) UpdateProduct(ctx context.Context, pid int, _a2 model.UpdateProductRequest) error {
-	ret := _m.Called(ctx, pid, _a2)
+// ListProducts provides a mock function with given fields: ctx, req
+func (_m *ProductServiceInterface) ListProducts(ctx context.Context, req model.ListProductsRequest) (*model.ProductListResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 *model.ProductListResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListProductsRequest) (*model.ProductListResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListProductsRequest) *model.ProductListResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductListResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListProductsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreProduct provides a mock function with given fields: ctx, id
+func (_m *ProductServiceInterface) RestoreProduct(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchProducts provides a mock function with given fields: ctx, req
+func (_m *ProductServiceInterface) SearchProducts(ctx context.Context, req model.SearchProductsRequest) (*model.ProductSearchResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 *model.ProductSearchResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.SearchProductsRequest) (*model.ProductSearchResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.SearchProductsRequest) *model.ProductSearchResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductSearchResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.SearchProductsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, pid, _a2, version
+func (_m *ProductServiceInterface) UpdateProduct(ctx context.Context, pid int, _a2 model.UpdateProductRequest, version int) error {
+	ret := _m.Called(ctx, pid, _a2, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, model.UpdateProductRequest) error); ok {
-		r0 = rf(ctx, pid, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.UpdateProductRequest, int) error); ok {
+		r0 = rf(ctx, pid, _a2, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,159 @@
+// Package product provides business logic for product operations.
+// It includes service layer functionality for product management with Redis caching.
+package product
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/cachetag"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+)
+
+const (
+	// SearchCacheTTL is how long search results are cached per query
+	SearchCacheTTL = 5 * time.Minute
+
+	highlightOpen  = "<em>"
+	highlightClose = "</em>"
+	snippetRadius  = 60
+)
+
+func (s *ProductService) SearchProducts(ctx context.Context, req model.SearchProductsRequest) (result *model.ProductSearchResponse, err error) {
+	query := strings.Join(strings.Fields(req.Query), " ")
+	limit := req.Limit
+	if limit <= 0 {
+		limit = repository.DefaultPageLimit
+	}
+	page := max(req.Page, 1)
+
+	return cache.Load(ctx, s.loader, productSearchCacheKey(query, page, limit), s.cfg.SearchTTL, func(ctx context.Context) (*model.ProductSearchResponse, error) {
+		return s.searchProducts(ctx, query, page, limit)
+	}, cachetag.ProductList)
+}
+
+// searchProducts runs a search query against the database and highlights the
+// matching terms
+func (s *ProductService) searchProducts(ctx context.Context, query string, page, limit int) (*model.ProductSearchResponse, error) {
+	matches, total, err := s.repo.SearchProducts(ctx, repository.ProductSearchFilter{
+		Query:  query,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	})
+	if err != nil {
+		s.logger.Error("error while searching products", err)
+		return nil, err
+	}
+
+	terms := searchTermsPattern(query)
+	result := &model.ProductSearchResponse{
+		Query: query,
+		Items: make([]model.ProductSearchHit, 0, len(matches)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for i := range matches {
+		m := &matches[i]
+		hit := model.ProductSearchHit{
+			ProductDetailResponse: model.ProductDetailResponse{
+				ID:          m.ID,
+				Name:        m.Name,
+				Description: m.Description,
+				Price:       m.Price,
+				Stock:       m.Stock,
+				CategoryID:  m.CategoryID,
+				Version:     m.Version,
+			},
+			Score:      m.Relevance,
+			Highlights: map[string]string{},
+		}
+		if snippet, ok := highlight(m.Name, terms); ok {
+			hit.Highlights["name"] = snippet
+		}
+		if snippet, ok := highlight(m.Description, terms); ok {
+			hit.Highlights["description"] = snippet
+		}
+		result.Items = append(result.Items, hit)
+	}
+
+	return result, nil
+}
+
+// productSearchCacheKey derives the cache key for one page of a search query.
+// It lives under the product: prefix so that flushing the product namespace
+// removes it.
+func productSearchCacheKey(query string, page, limit int) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(query)))
+	return fmt.Sprintf("product:search:%s:%d:%d", hex.EncodeToString(sum[:]), page, limit)
+}
+
+// searchTermsPattern builds a case-insensitive pattern matching any word of the query.
+// It returns nil when the query has no usable terms.
+func searchTermsPattern(query string) *regexp.Regexp {
+	var terms []string
+	for _, term := range strings.Fields(query) {
+		term = strings.Trim(term, `"'+-*()~<>@`)
+		if utf8.RuneCountInString(term) < 2 {
+			continue
+		}
+		terms = append(terms, regexp.QuoteMeta(term))
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+}
+
+// highlight returns an HTML-escaped snippet of text around the first match,
+// with every match inside the snippet wrapped in <em> tags.
+// It reports false when nothing in text matches.
+func highlight(text string, terms *regexp.Regexp) (string, bool) {
+	if terms == nil {
+		return "", false
+	}
+	matches := terms.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	start := max(matches[0][0]-snippetRadius, 0)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	end := min(matches[0][1]+2*snippetRadius, len(text))
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m[1] > end {
+			break
+		}
+		b.WriteString(html.EscapeString(text[pos:m[0]]))
+		b.WriteString(highlightOpen)
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString(highlightClose)
+		pos = m[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
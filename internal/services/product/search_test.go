@@ -0,0 +1,64 @@
+package product
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	terms := searchTermsPattern(`wireless "mouse" a`)
+
+	t.Run("Short Text Is Highlighted In Full", func(t *testing.T) {
+		snippet, ok := highlight("Wireless Mouse", terms)
+		assert.True(t, ok)
+		assert.Equal(t, "<em>Wireless</em> <em>Mouse</em>", snippet)
+	})
+
+	t.Run("Long Text Is Trimmed Around First Match", func(t *testing.T) {
+		text := strings.Repeat("filler ", 20) + "a wireless mouse <b>deal</b> " + strings.Repeat("tail ", 40)
+		snippet, ok := highlight(text, terms)
+		assert.True(t, ok)
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.True(t, strings.HasSuffix(snippet, "…"))
+		assert.Contains(t, snippet, "<em>wireless</em> <em>mouse</em> &lt;b&gt;deal&lt;/b&gt;")
+	})
+
+	t.Run("No Match", func(t *testing.T) {
+		snippet, ok := highlight("Keyboard", terms)
+		assert.False(t, ok)
+		assert.Empty(t, snippet)
+	})
+
+	t.Run("Query Without Usable Terms", func(t *testing.T) {
+		assert.Nil(t, searchTermsPattern("a + -"))
+		_, ok := highlight("anything", nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestProductSearchCacheKey(t *testing.T) {
+	assert.Equal(t, productSearchCacheKey("Wireless Mouse", 1, 20), productSearchCacheKey("wireless mouse", 1, 20))
+	assert.NotEqual(t, productSearchCacheKey("mouse", 1, 20), productSearchCacheKey("mouse", 2, 20))
+	assert.True(t, strings.HasPrefix(productSearchCacheKey("mouse", 1, 20), "product:search:"))
+}
+
+func TestProductService_SearchProducts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		req := model.SearchProductsRequest{Query: "mouse"}
+		expected := &model.ProductSearchResponse{Query: "mouse", Total: 1, Page: 1, Limit: 20}
+
+		mockService.On("SearchProducts", ctx, req).Return(expected, nil)
+
+		result, err := mockService.SearchProducts(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		mockService.AssertExpectations(t)
+	})
+}
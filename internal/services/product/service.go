@@ -4,51 +4,109 @@ package product
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/cachetag"
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
 	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
 	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
 	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
 )
 
-var ErrProductNotFound = errors.New("product not found")
+const (
+	// DetailCacheTTL is how long a product detail is cached
+	DetailCacheTTL = 30 * time.Minute
+	// ListCacheTTL is how long a product list page is cached
+	ListCacheTTL = 5 * time.Minute
+)
+
+var (
+	// ErrProductNotFound is returned when there is no product with the ID
+	ErrProductNotFound = repository.ErrProductNotFound
+	// ErrCategoryDeleted is returned when restoring a product whose category
+	// is deleted
+	ErrCategoryDeleted = apperror.New(apperror.Conflict, "the category of the product is deleted, restore it first")
+)
 
 type ProductServiceInterface interface {
 	GetProductDetail(ctx context.Context, id int) (product *model.ProductDetailResponse, err error)
 	CreateProduct(ctx context.Context, product model.CreateProductRequest) (err error)
-	UpdateProduct(ctx context.Context, pid int, product model.UpdateProductRequest) (err error)
-	DeleteProduct(ctx context.Context, id int) (err error)
+	UpdateProduct(ctx context.Context, pid int, product model.UpdateProductRequest, version int) (err error)
+	DeleteProduct(ctx context.Context, id, version int) (err error)
+	RestoreProduct(ctx context.Context, id int) (err error)
+	ListProducts(ctx context.Context, req model.ListProductsRequest) (list *model.ProductListResponse, err error)
+	SearchProducts(ctx context.Context, req model.SearchProductsRequest) (result *model.ProductSearchResponse, err error)
+}
+
+// Config holds how long product details, list pages and search results are
+// cached. Zero values use DetailCacheTTL, ListCacheTTL and SearchCacheTTL.
+// Load configures how expired entries are reloaded.
+type Config struct {
+	DetailTTL time.Duration
+	ListTTL   time.Duration
+	SearchTTL time.Duration
+	Load      cache.LoadOptions
 }
 
 type ProductService struct {
 	repo   repository.DBRepository
 	logger *logger.Logger
-	cache  *cache.Cache
+	cache  cache.Cache
+	loader *cache.Loader
+	cfg    Config
 }
 
-func NewProductService(repo repository.DBRepository, logger *logger.Logger, cache *cache.Cache) ProductServiceInterface {
+func NewProductService(repo repository.DBRepository, logger *logger.Logger, c cache.Cache, cfg Config) ProductServiceInterface {
+	if cfg.DetailTTL == 0 {
+		cfg.DetailTTL = DetailCacheTTL
+	}
+	if cfg.ListTTL == 0 {
+		cfg.ListTTL = ListCacheTTL
+	}
+	if cfg.SearchTTL == 0 {
+		cfg.SearchTTL = SearchCacheTTL
+	}
+
+	loadOpts := cfg.Load
+	loadOpts.NotFound = []error{ErrProductNotFound}
+	// Cached values outlive the request that wrote them, so they are read from
+	// the primary rather than a replica that may not have the write yet
+	loadOpts.LoadContext = database.WithPrimary
+	// A stale entry may stand in for a failed load, but not for an answer such
+	// as an invalid cursor
+	loadOpts.StaleOn = apperror.IsInternal
+
 	return &ProductService{
 		repo:   repo,
 		logger: logger,
-		cache:  cache,
+		cache:  c,
+		loader: cache.NewLoader(c, loadOpts, logger),
+		cfg:    cfg,
 	}
 }
 
 func (s *ProductService) GetProductDetail(ctx context.Context, id int) (product *model.ProductDetailResponse, err error) {
-	// Try to get from cache first
-	cacheKey := fmt.Sprintf("product:%d", id)
-	var cachedProduct model.ProductDetailResponse
-
-	if err := s.cache.Get(ctx, cacheKey, &cachedProduct); err == nil {
-		s.logger.Debug("product retrieved from cache", "product_id", id)
-		return &cachedProduct, nil
+	// Reads including deleted products are not cached
+	if database.IncludeDeleted(ctx) {
+		return s.getProductDetail(ctx, id)
 	}
+	return cache.LoadTagged(ctx, s.loader, detailCacheKey(id), s.cfg.DetailTTL, func(ctx context.Context) (*model.ProductDetailResponse, error) {
+		return s.getProductDetail(ctx, id)
+	}, func(product *model.ProductDetailResponse) []string {
+		return []string{cachetag.Category(product.CategoryID)}
+	})
+}
 
-	// Cache miss, get from database
+// getProductDetail reads a product from the database
+func (s *ProductService) getProductDetail(ctx context.Context, id int) (*model.ProductDetailResponse, error) {
 	prodDetail, err := s.repo.GetProductDetail(ctx, id)
 	if err != nil {
 		s.logger.Error("error while fetch product information", err)
@@ -60,25 +118,23 @@ func (s *ProductService) GetProductDetail(ctx context.Context, id int) (product
 		return nil, ErrProductNotFound
 	}
 
-	// Send the product details as the response
-	product = &model.ProductDetailResponse{
+	return &model.ProductDetailResponse{
 		ID:          prodDetail.ID,
 		Name:        prodDetail.Name,
 		Description: prodDetail.Description,
 		Price:       prodDetail.Price,
 		Stock:       prodDetail.Stock,
 		CategoryID:  prodDetail.CategoryID,
-	}
-
-	// Cache the result for future requests
-	if err := s.cache.Set(ctx, cacheKey, product, 30*time.Minute); err != nil {
-		s.logger.Warn("failed to cache product", "product_id", id, "error", err)
-	}
-
-	return product, nil
+		Version:     prodDetail.Version,
+		DeletedAt:   prodDetail.DeletedAt,
+	}, nil
 }
 
 func (s *ProductService) CreateProduct(ctx context.Context, product model.CreateProductRequest) (err error) {
+	if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+		return err
+	}
+
 	productd := &sqlModel.Product{
 		Name:        product.Name,
 		Description: product.Description,
@@ -93,56 +149,241 @@ func (s *ProductService) CreateProduct(ctx context.Context, product model.Create
 		return err
 	}
 
-	// Invalidate product cache patterns
-	s.invalidateProductCache(ctx)
+	// Invalidate the lists showing the product; the ID may be cached as missing
+	s.invalidateProductCache(ctx, product.CategoryID)
+	s.deleteDetailCache(ctx, productd.ID)
 
 	return nil
 }
 
-func (s *ProductService) UpdateProduct(ctx context.Context, pid int, product model.UpdateProductRequest) (err error) {
+// UpdateProduct updates a product, at the given version unless it is 0. It
+// returns repository.ErrVersionMismatch when the product is at another version.
+func (s *ProductService) UpdateProduct(ctx context.Context, pid int, product model.UpdateProductRequest, version int) (err error) {
+	if product.CategoryID != 0 {
+		if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+			return err
+		}
+	}
+
 	productd := &sqlModel.Product{
 		Name:        product.Name,
 		Description: product.Description,
 		Price:       product.Price,
 		Stock:       product.Stock,
 		CategoryID:  product.CategoryID,
+		Version:     version,
 	}
 
-	err = s.repo.UpdateProduct(ctx, pid, productd)
+	// The product leaves the lists of its old category when it moves
+	var categoryIDs []int
+	err = s.repo.WithTx(ctx, func(tx repository.DBRepository) error {
+		current, err := tx.GetProductDetail(ctx, pid)
+		if err != nil {
+			return err
+		}
+		categoryIDs = append(categoryIDs, current.CategoryID)
+		if product.CategoryID != 0 && product.CategoryID != current.CategoryID {
+			categoryIDs = append(categoryIDs, product.CategoryID)
+		}
+		return tx.UpdateProduct(ctx, pid, productd)
+	})
 	if err != nil {
 		s.logger.Error("error while update product", err)
 		return err
 	}
 
-	// Invalidate specific product cache and patterns
-	s.invalidateProductCache(ctx)
-	if err := s.cache.Delete(ctx, fmt.Sprintf("product:%d", pid)); err != nil {
-		s.logger.Warn("failed to delete product cache", "product_id", pid, "error", err)
-	}
+	s.invalidateProductCache(ctx, categoryIDs...)
+	s.deleteDetailCache(ctx, pid)
 
 	return nil
 }
 
-func (s *ProductService) DeleteProduct(ctx context.Context, id int) (err error) {
-	err = s.repo.DeleteProduct(ctx, id)
+// DeleteProduct soft deletes a product, at the given version unless it is 0.
+// It returns repository.ErrVersionMismatch when the product is at another version.
+func (s *ProductService) DeleteProduct(ctx context.Context, id, version int) (err error) {
+	var categoryID int
+	err = s.repo.WithTx(ctx, func(tx repository.DBRepository) error {
+		current, err := tx.GetProductDetail(ctx, id)
+		if err != nil {
+			return err
+		}
+		categoryID = current.CategoryID
+		return tx.DeleteProduct(ctx, id, version)
+	})
 	if err != nil {
 		s.logger.Error("error while delete product", err)
 		return err
 	}
 
-	// Invalidate product cache patterns
-	s.invalidateProductCache(ctx)
-	if err := s.cache.Delete(ctx, fmt.Sprintf("product:%d", id)); err != nil {
-		s.logger.Warn("failed to delete product cache", "product_id", id, "error", err)
+	s.invalidateProductCache(ctx, categoryID)
+	s.deleteDetailCache(ctx, id)
+
+	return nil
+}
+
+// RestoreProduct brings back a soft-deleted product. It returns
+// ErrCategoryDeleted while the category of the product is deleted, and
+// ErrProductNotFound when no deleted product has the ID.
+func (s *ProductService) RestoreProduct(ctx context.Context, id int) (err error) {
+	var categoryID int
+	err = s.repo.WithTx(ctx, func(tx repository.DBRepository) error {
+		if err := tx.RestoreProduct(ctx, id); err != nil {
+			return err
+		}
+		product, err := tx.GetProductDetail(ctx, id)
+		if err != nil {
+			return err
+		}
+		categoryID = product.CategoryID
+		if err := checkCategory(ctx, tx, product.CategoryID); err != nil {
+			if errors.Is(err, repository.ErrInvalidCategoryReference) {
+				return ErrCategoryDeleted
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("error while restore product", err)
+		return err
+	}
+
+	// The product is cached as missing since it was deleted
+	s.invalidateProductCache(ctx, categoryID)
+	s.deleteDetailCache(ctx, id)
+	return nil
+}
+
+func (s *ProductService) ListProducts(ctx context.Context, req model.ListProductsRequest) (list *model.ProductListResponse, err error) {
+	// Lists including deleted products are not cached
+	if database.IncludeDeleted(ctx) {
+		return s.listProducts(ctx, req)
+	}
+	return cache.Load(ctx, s.loader, productListCacheKey(req), s.cfg.ListTTL, func(ctx context.Context) (*model.ProductListResponse, error) {
+		return s.listProducts(ctx, req)
+	}, productListCacheTags(req)...)
+}
+
+// productListCacheTags returns the tags of a cached list page. A page filtered
+// by category only changes with the products of that category.
+func productListCacheTags(req model.ListProductsRequest) []string {
+	if req.CategoryID != nil {
+		return []string{cachetag.Category(*req.CategoryID), cachetag.CategoryProducts(*req.CategoryID)}
+	}
+	return []string{cachetag.ProductList}
+}
+
+// listProducts reads a page of products from the database
+func (s *ProductService) listProducts(ctx context.Context, req model.ListProductsRequest) (list *model.ProductListResponse, err error) {
+	filter := repository.ProductListFilter{
+		CategoryID: req.CategoryID,
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		InStock:    req.InStock,
+		SortBy:     req.Sort,
+		SortDesc:   req.Order == "desc",
+		Limit:      req.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = repository.DefaultPageLimit
+	}
+
+	page := 0
+	if req.Cursor != "" {
+		if filter.After, err = repository.DecodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	} else {
+		page = max(req.Page, 1)
+		filter.Offset = (page - 1) * filter.Limit
+	}
+
+	products, total, err := s.repo.ListProducts(ctx, filter)
+	if err != nil {
+		s.logger.Error("error while listing products", err)
+		return nil, err
+	}
+
+	list = &model.ProductListResponse{
+		Items: make([]model.ProductDetailResponse, 0, len(products)),
+		Total: total,
+		Page:  page,
+		Limit: filter.Limit,
+	}
+	for _, p := range products {
+		list.Items = append(list.Items, model.ProductDetailResponse{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			Stock:       p.Stock,
+			CategoryID:  p.CategoryID,
+			Version:     p.Version,
+			DeletedAt:   p.DeletedAt,
+		})
+	}
+
+	// A full page may be followed by more rows; in offset mode the total tells us for sure
+	hasMore := len(products) == filter.Limit
+	if filter.After == nil {
+		hasMore = int64(filter.Offset+len(products)) < total
+	}
+	if hasMore && len(products) > 0 {
+		list.NextCursor = repository.ProductCursor(&products[len(products)-1], req.Sort).Encode()
 	}
 
+	return list, nil
+}
+
+// productListCacheKey derives a stable cache key from the listing parameters.
+// It lives under the product: prefix so that flushing the product namespace
+// removes it.
+func productListCacheKey(req model.ListProductsRequest) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return "product:list:" + hex.EncodeToString(sum[:])
+}
+
+// checkCategory returns repository.ErrInvalidCategoryReference when the
+// category a product is assigned to does not exist
+func (s *ProductService) checkCategory(ctx context.Context, categoryID int) error {
+	return checkCategory(ctx, s.repo, categoryID)
+}
+
+// checkCategory returns repository.ErrInvalidCategoryReference when the
+// category does not exist in repo or is deleted
+func checkCategory(ctx context.Context, repo repository.DBRepository, categoryID int) error {
+	if _, err := repo.GetCategoryByID(ctx, categoryID); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return repository.ErrInvalidCategoryReference
+		}
+		return err
+	}
 	return nil
 }
 
-// invalidateProductCache removes all product-related cache entries
-func (s *ProductService) invalidateProductCache(ctx context.Context) {
-	// Delete all product cache patterns
-	if err := s.cache.DeletePattern(ctx, "product:*"); err != nil {
-		s.logger.Warn("failed to invalidate product cache", "error", err)
+// invalidateProductCache removes the cached search results and list pages
+// that are not filtered by category or are filtered by one of categoryIDs.
+// Writes remove the details of the product they change themselves.
+func (s *ProductService) invalidateProductCache(ctx context.Context, categoryIDs ...int) {
+	tags := []string{cachetag.ProductList}
+	for _, id := range categoryIDs {
+		tags = append(tags, cachetag.CategoryProducts(id))
+	}
+	if err := s.cache.InvalidateTags(ctx, tags...); err != nil {
+		s.logger.Warn("failed to invalidate product cache", "category_ids", categoryIDs, "error", err)
+	}
+}
+
+// detailCacheKey is the key under which the detail of a product is cached
+func detailCacheKey(id int) string {
+	return fmt.Sprintf("product:%d", id)
+}
+
+// deleteDetailCache removes the cached detail of a product, or the entry
+// remembering that it does not exist
+func (s *ProductService) deleteDetailCache(ctx context.Context, id int) {
+	if err := s.cache.Delete(ctx, detailCacheKey(id)); err != nil {
+		s.logger.Warn("failed to delete product cache", "product_id", id, "error", err)
 	}
 }
@@ -3,11 +3,21 @@ package product
 import (
 	"context"
 	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/product/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
 	"github.com/MitulShah1/golang-rest-api-template/internal/services/product/mocks"
+	"github.com/MitulShah1/golang-rest-api-template/package/cache"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/MitulShah1/golang-rest-api-template/package/database/migrations"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var mockService = new(mocks.ProductServiceInterface)
@@ -88,9 +98,9 @@ func TestProductService_UpdateProduct(t *testing.T) {
 			Price:       299.99,
 		}
 
-		mockService.On("UpdateProduct", ctx, 1, updateProduct).Return(nil)
+		mockService.On("UpdateProduct", ctx, 1, updateProduct, 0).Return(nil)
 
-		err := mockService.UpdateProduct(ctx, 1, updateProduct)
+		err := mockService.UpdateProduct(ctx, 1, updateProduct, 0)
 
 		assert.NoError(t, err)
 		mockService.AssertExpectations(t)
@@ -101,9 +111,9 @@ func TestProductService_UpdateProduct(t *testing.T) {
 			Name: "Updated Product",
 		}
 
-		mockService.On("UpdateProduct", ctx, 999, updateProduct).Return(errors.New("product not found"))
+		mockService.On("UpdateProduct", ctx, 999, updateProduct, 0).Return(errors.New("product not found"))
 
-		err := mockService.UpdateProduct(ctx, 999, updateProduct)
+		err := mockService.UpdateProduct(ctx, 999, updateProduct, 0)
 
 		assert.Error(t, err)
 		mockService.AssertExpectations(t)
@@ -114,20 +124,185 @@ func TestProductService_DeleteProduct(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
-		mockService.On("DeleteProduct", ctx, 1).Return(nil)
+		mockService.On("DeleteProduct", ctx, 1, 0).Return(nil)
 
-		err := mockService.DeleteProduct(ctx, 1)
+		err := mockService.DeleteProduct(ctx, 1, 0)
 
 		assert.NoError(t, err)
 		mockService.AssertExpectations(t)
 	})
 
 	t.Run("Not Found", func(t *testing.T) {
-		mockService.On("DeleteProduct", ctx, 999).Return(errors.New("product not found"))
+		mockService.On("DeleteProduct", ctx, 999, 0).Return(errors.New("product not found"))
 
-		err := mockService.DeleteProduct(ctx, 999)
+		err := mockService.DeleteProduct(ctx, 999, 0)
 
 		assert.Error(t, err)
 		mockService.AssertExpectations(t)
 	})
 }
+
+// newSQLiteProductService returns a service configured with cfg over a
+// migrated in-memory SQLite database and a miniredis cache
+func newSQLiteProductService(t *testing.T, cfg Config) (ProductServiceInterface, repository.DBRepository, *database.Database) {
+	t.Helper()
+
+	db, err := database.NewDatabase(&database.DBConfig{Driver: database.DriverSQLite, DBName: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	log := logger.NewLogger(logger.DefaultOptions())
+	c, err := cache.NewRedisCache(&cache.RedisConfig{Host: host, Port: port}, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	repo := repository.NewDBRepository(db)
+	return NewProductService(repo, log, c, cfg), repo, db
+}
+
+func TestProductService_RestoreProduct(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newSQLiteProductService(t, Config{Load: cache.LoadOptions{NegativeTTL: time.Minute}})
+
+	categoryID, err := repo.CreateCategory(ctx, &sqlModel.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	require.NoError(t, svc.CreateProduct(ctx, model.CreateProductRequest{Name: "Red Shirt", Price: 20, CategoryID: int(categoryID)}))
+
+	// Cache the product, so that a stale entry would hide the deletion
+	_, err = svc.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteProduct(ctx, 1, 0))
+	_, err = svc.GetProductDetail(ctx, 1)
+	assert.ErrorIs(t, err, repository.ErrProductNotFound)
+
+	deleted, err := svc.GetProductDetail(database.WithDeleted(ctx), 1)
+	require.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+
+	t.Run("Category Deleted", func(t *testing.T) {
+		require.NoError(t, repo.DeleteCategory(ctx, int(categoryID), 0))
+		assert.ErrorIs(t, svc.RestoreProduct(ctx, 1), ErrCategoryDeleted)
+		require.NoError(t, repo.RestoreCategory(ctx, int(categoryID)))
+	})
+
+	t.Run("Restored", func(t *testing.T) {
+		require.NoError(t, svc.RestoreProduct(ctx, 1))
+		product, err := svc.GetProductDetail(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, product.DeletedAt)
+	})
+
+	t.Run("Not Deleted", func(t *testing.T) {
+		assert.ErrorIs(t, svc.RestoreProduct(ctx, 1), repository.ErrProductNotFound)
+	})
+}
+
+func TestProductService_GetProductDetail_StaleIfError(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, db := newSQLiteProductService(t, Config{
+		DetailTTL: time.Millisecond,
+		Load:      cache.LoadOptions{StaleIfError: true, StaleTTL: time.Minute},
+	})
+
+	categoryID, err := repo.CreateCategory(ctx, &sqlModel.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	require.NoError(t, svc.CreateProduct(ctx, model.CreateProductRequest{Name: "Red Shirt", Price: 20, CategoryID: int(categoryID)}))
+	_, err = svc.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+
+	// The entry has expired and the database is gone, so the stale entry is served
+	time.Sleep(5 * time.Millisecond)
+	db.Close()
+	product, err := svc.GetProductDetail(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Red Shirt", product.Name)
+
+	// Without a stale entry the failure reaches the caller
+	_, err = svc.GetProductDetail(ctx, 2)
+	assert.Error(t, err)
+}
+
+func TestProductService_GetProductDetail_NotFoundCached(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newSQLiteProductService(t, Config{Load: cache.LoadOptions{NegativeTTL: time.Minute}})
+
+	categoryID, err := repo.CreateCategory(ctx, &sqlModel.Category{Name: "Clothing"})
+	require.NoError(t, err)
+	_, err = svc.GetProductDetail(ctx, 1)
+	require.ErrorIs(t, err, ErrProductNotFound)
+
+	// A product written behind the service's back stays missing until the
+	// negative entry expires
+	require.NoError(t, repo.CreateProduct(ctx, &sqlModel.Product{Name: "Red Shirt", CategoryID: int(categoryID)}))
+	_, err = svc.GetProductDetail(ctx, 1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	// Creating a product through the service removes the negative entry of its ID
+	_, err = svc.GetProductDetail(ctx, 2)
+	require.ErrorIs(t, err, ErrProductNotFound)
+	require.NoError(t, svc.CreateProduct(ctx, model.CreateProductRequest{Name: "Blue Shirt", Price: 20, CategoryID: int(categoryID)}))
+	product, err := svc.GetProductDetail(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Blue Shirt", product.Name)
+}
+
+func TestProductService_ListCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newSQLiteProductService(t, Config{})
+	c := svc.(*ProductService).cache
+
+	var categories []int
+	for _, name := range []string{"Clothing", "Books"} {
+		id, err := repo.CreateCategory(ctx, &sqlModel.Category{Name: name})
+		require.NoError(t, err)
+		categories = append(categories, int(id))
+		require.NoError(t, svc.CreateProduct(ctx, model.CreateProductRequest{Name: name + " item", Price: 10, CategoryID: int(id)}))
+	}
+	clothing, books := categories[0], categories[1]
+
+	requests := map[string]model.ListProductsRequest{
+		"clothing": {CategoryID: &clothing},
+		"books":    {CategoryID: &books},
+		"all":      {},
+	}
+	cached := func(name string) bool {
+		var list map[string]any
+		return c.Get(ctx, productListCacheKey(requests[name]), &list) == nil
+	}
+	load := func() {
+		for _, req := range requests {
+			_, err := svc.ListProducts(ctx, req)
+			require.NoError(t, err)
+		}
+	}
+
+	// A write in Clothing keeps the pages of Books
+	load()
+	require.NoError(t, svc.UpdateProduct(ctx, 1, model.UpdateProductRequest{Price: 12}, 0))
+	assert.False(t, cached("clothing"))
+	assert.False(t, cached("all"))
+	assert.True(t, cached("books"))
+
+	// Moving a product changes the pages of both categories
+	load()
+	require.NoError(t, svc.UpdateProduct(ctx, 1, model.UpdateProductRequest{CategoryID: books}, 0))
+	assert.False(t, cached("clothing"))
+	assert.False(t, cached("books"))
+
+	load()
+	require.NoError(t, svc.DeleteProduct(ctx, 2, 0))
+	assert.False(t, cached("books"))
+	assert.True(t, cached("clothing"))
+
+	list, err := svc.ListProducts(ctx, requests["books"])
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, 1, list.Items[0].ID)
+}
@@ -0,0 +1,37 @@
+// Package user provides business logic for user accounts.
+// It includes account creation, credential verification with lockout and password reset.
+package user
+
+import (
+	"context"
+	"time"
+
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+)
+
+// Notifier delivers password reset tokens to their owner, for example by email
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, user *sqlModel.User, token string, expiresAt time.Time) error
+}
+
+// LogNotifier writes password reset tokens to the application log.
+// It is meant for development; anyone with access to the logs can reset passwords.
+type LogNotifier struct {
+	logger *logger.Logger
+}
+
+func NewLogNotifier(logger *logger.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// SendPasswordReset implements Notifier
+func (n *LogNotifier) SendPasswordReset(_ context.Context, user *sqlModel.User, token string, expiresAt time.Time) error {
+	n.logger.Info("password reset requested",
+		"username", user.Username,
+		"email", user.Email,
+		"token", token,
+		"expiresAt", expiresAt,
+	)
+	return nil
+}
@@ -0,0 +1,360 @@
+// Package user provides business logic for user accounts.
+// It includes account creation, credential verification with lockout and password reset.
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/apperror"
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+)
+
+const (
+	DefaultMaxFailedLogins = 5
+	DefaultLockoutDuration = 15 * time.Minute
+	DefaultResetTokenTTL   = time.Hour
+)
+
+var (
+	ErrInvalidResetToken = apperror.New(apperror.Validation, "invalid or expired password reset token")
+	ErrUnknownRole       = apperror.New(apperror.Validation, "unknown role")
+	ErrIncorrectPassword = apperror.New(apperror.Unauthorized, "current password is incorrect")
+)
+
+// SessionRevoker ends the login sessions of a user, such as
+// auth.RefreshTokenStore does for refresh tokens
+type SessionRevoker interface {
+	RevokeSubject(ctx context.Context, subject string) error
+}
+
+type UserServiceInterface interface {
+	CreateUser(ctx context.Context, req model.CreateUserRequest) (int64, error)
+	GetUserByUsername(ctx context.Context, username string) (*sqlModel.User, error)
+	ChangePassword(ctx context.Context, username string, req model.ChangePasswordRequest) error
+	RequestPasswordReset(ctx context.Context, req model.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req model.ResetPasswordRequest) error
+	VerifyCredentials(ctx context.Context, username, password string) (*auth.Principal, error)
+	ResolvePrincipal(ctx context.Context, username string) (*auth.Principal, error)
+	GetUserRoles(ctx context.Context, username string) ([]string, error)
+	SetUserRoles(ctx context.Context, username string, roles []string) error
+}
+
+// Config holds the account lockout and password reset policy and the roles
+// that may be assigned to users. Sessions, when set, ends the sessions of a
+// user whose password changes.
+type Config struct {
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	ResetTokenTTL   time.Duration
+	Policy          *auth.Policy
+	Sessions        SessionRevoker
+}
+
+type UserService struct {
+	repo      repository.DBRepository
+	logger    *logger.Logger
+	hasher    auth.PasswordHasher
+	notifier  Notifier
+	cfg       Config
+	dummyHash string
+	now       func() time.Time
+}
+
+func NewUserService(repo repository.DBRepository, logger *logger.Logger, hasher auth.PasswordHasher, notifier Notifier, cfg Config) (*UserService, error) {
+	if cfg.MaxFailedLogins <= 0 {
+		cfg.MaxFailedLogins = DefaultMaxFailedLogins
+	}
+	if cfg.LockoutDuration <= 0 {
+		cfg.LockoutDuration = DefaultLockoutDuration
+	}
+	if cfg.ResetTokenTTL <= 0 {
+		cfg.ResetTokenTTL = DefaultResetTokenTTL
+	}
+	if cfg.Policy == nil {
+		cfg.Policy = auth.DefaultPolicy()
+	}
+
+	// Unknown usernames are checked against this hash so they take as long as known ones
+	dummyHash, err := hasher.Hash("dummy-password")
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserService{
+		repo:      repo,
+		logger:    logger,
+		hasher:    hasher,
+		notifier:  notifier,
+		cfg:       cfg,
+		dummyHash: dummyHash,
+		now:       time.Now,
+	}, nil
+}
+
+func (s *UserService) CreateUser(ctx context.Context, req model.CreateUserRequest) (int64, error) {
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		s.logger.Error("error while hashing password", err)
+		return 0, err
+	}
+
+	id, err := s.repo.CreateUser(ctx, &sqlModel.User{
+		Username:     req.Username,
+		Email:        req.Email,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("user created", "id", id, "username", req.Username)
+	return id, nil
+}
+
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*sqlModel.User, error) {
+	return s.repo.GetUserByUsername(ctx, username)
+}
+
+// EnsureUser creates the account unless a user with the same username already exists,
+// then makes sure it holds the given roles. It is used to bootstrap the
+// administrator account from configuration.
+func (s *UserService) EnsureUser(ctx context.Context, req model.CreateUserRequest, roles ...string) error {
+	user, err := s.repo.GetUserByUsername(ctx, req.Username)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		if _, err = s.CreateUser(ctx, req); err != nil && !errors.Is(err, repository.ErrUserExists) {
+			return err
+		}
+		user, err = s.repo.GetUserByUsername(ctx, req.Username)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if err := s.repo.AddUserRole(ctx, user.ID, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *UserService) ChangePassword(ctx context.Context, username string, req model.ChangePasswordRequest) error {
+	user, err := s.authenticate(ctx, username, req.CurrentPassword)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return ErrIncorrectPassword
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.setPassword(ctx, user, req.NewPassword)
+}
+
+// RequestPasswordReset sends a reset token to the account with the given email.
+// It succeeds for unknown addresses too so callers cannot probe for accounts.
+func (s *UserService) RequestPasswordReset(ctx context.Context, req model.ForgotPasswordRequest) error {
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		s.logger.Info("password reset requested for unknown email")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := s.now().Add(s.cfg.ResetTokenTTL)
+	if err := s.repo.CreatePasswordResetToken(ctx, &sqlModel.PasswordResetToken{
+		TokenHash: hashResetToken(token),
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.notifier.SendPasswordReset(ctx, user, token, expiresAt); err != nil {
+		s.logger.Error("error while sending password reset", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *UserService) ResetPassword(ctx context.Context, req model.ResetPasswordRequest) error {
+	token, err := s.repo.ConsumePasswordResetToken(ctx, hashResetToken(req.Token), s.now())
+	if errors.Is(err, repository.ErrResetTokenNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, token.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.setPassword(ctx, user, req.NewPassword)
+}
+
+// VerifyCredentials implements auth.CredentialVerifier
+func (s *UserService) VerifyCredentials(ctx context.Context, username, password string) (*auth.Principal, error) {
+	user, err := s.authenticate(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.principal(ctx, user)
+}
+
+// ResolvePrincipal implements auth.PrincipalResolver. A deleted user resolves to
+// auth.ErrInvalidCredentials so that outstanding refresh tokens stop working.
+func (s *UserService) ResolvePrincipal(ctx context.Context, username string) (*auth.Principal, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.principal(ctx, user)
+}
+
+func (s *UserService) GetUserRoles(ctx context.Context, username string) ([]string, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserRoles(ctx, user.ID)
+}
+
+// SetUserRoles replaces the roles of a user. Every role must be defined by the policy.
+func (s *UserService) SetUserRoles(ctx context.Context, username string, roles []string) error {
+	unique := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !s.cfg.Policy.HasRole(role) {
+			return apperror.Detailf(ErrUnknownRole, "%q", role)
+		}
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SetUserRoles(ctx, user.ID, unique); err != nil {
+		return err
+	}
+
+	s.logger.Info("user roles changed", "username", username, "roles", unique)
+	return nil
+}
+
+func (s *UserService) principal(ctx context.Context, user *sqlModel.User) (*auth.Principal, error) {
+	roles, err := s.repo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Principal{Subject: user.Username, Roles: roles}, nil
+}
+
+// authenticate checks a password and applies the lockout policy. Locked accounts
+// are rejected without checking the password so that guessing cannot continue.
+func (s *UserService) authenticate(ctx context.Context, username, password string) (*sqlModel.User, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		_, _ = auth.VerifyPassword(s.dummyHash, password)
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if user.IsLocked(now) {
+		return nil, auth.ErrAccountLocked
+	}
+
+	ok, err := auth.VerifyPassword(user.PasswordHash, password)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.repo.RecordFailedLogin(ctx, user.ID, s.cfg.MaxFailedLogins, now.Add(s.cfg.LockoutDuration)); err != nil {
+			s.logger.Error("error while recording failed login", err)
+		}
+		if user.FailedAttempts+1 >= s.cfg.MaxFailedLogins {
+			s.logger.Warn("account locked after repeated failed logins", "username", username)
+		}
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	if user.FailedAttempts > 0 || user.LockedUntil != nil {
+		if err := s.repo.ResetFailedLogins(ctx, user.ID); err != nil {
+			s.logger.Error("error while resetting failed logins", err)
+		}
+	}
+
+	return user, nil
+}
+
+func (s *UserService) setPassword(ctx context.Context, user *sqlModel.User, password string) error {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		s.logger.Error("error while hashing password", err)
+		return err
+	}
+
+	if err := s.repo.UpdateUserPassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+	s.logger.Info("password changed", "username", user.Username)
+
+	// Sessions opened with the old password, possibly by whoever the change
+	// locks out, must not outlive it
+	if s.cfg.Sessions != nil {
+		if err := s.cfg.Sessions.RevokeSubject(ctx, user.Username); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+	return nil
+}
+
+func newResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashResetToken returns the stored form of a reset token so a database leak cannot be used to reset passwords
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
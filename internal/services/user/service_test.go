@@ -0,0 +1,324 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/handlers/user/model"
+	"github.com/MitulShah1/golang-rest-api-template/internal/repository"
+	sqlModel "github.com/MitulShah1/golang-rest-api-template/internal/repository/model"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserRepo keeps users in memory; unimplemented methods panic via the nil embedded interface.
+type fakeUserRepo struct {
+	repository.DBRepository
+	mu     sync.Mutex
+	users  map[int]*sqlModel.User
+	tokens map[string]*sqlModel.PasswordResetToken
+	roles  map[int][]string
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{
+		users:  map[int]*sqlModel.User{},
+		tokens: map[string]*sqlModel.PasswordResetToken{},
+		roles:  map[int][]string{},
+	}
+}
+
+func (f *fakeUserRepo) GetUserRoles(_ context.Context, userID int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.roles[userID]), nil
+}
+
+func (f *fakeUserRepo) SetUserRoles(_ context.Context, userID int, roles []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.roles[userID] = slices.Clone(roles)
+	return nil
+}
+
+func (f *fakeUserRepo) AddUserRole(_ context.Context, userID int, role string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !slices.Contains(f.roles[userID], role) {
+		f.roles[userID] = append(f.roles[userID], role)
+	}
+	return nil
+}
+
+func (f *fakeUserRepo) CreateUser(_ context.Context, user *sqlModel.User) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.Username == user.Username || u.Email == user.Email {
+			return 0, repository.ErrUserExists
+		}
+	}
+	u := *user
+	u.ID = len(f.users) + 1
+	f.users[u.ID] = &u
+	return int64(u.ID), nil
+}
+
+func (f *fakeUserRepo) find(match func(u *sqlModel.User) bool) (*sqlModel.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if match(u) {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (f *fakeUserRepo) GetUserByID(_ context.Context, id int) (*sqlModel.User, error) {
+	return f.find(func(u *sqlModel.User) bool { return u.ID == id })
+}
+
+func (f *fakeUserRepo) GetUserByUsername(_ context.Context, username string) (*sqlModel.User, error) {
+	return f.find(func(u *sqlModel.User) bool { return u.Username == username })
+}
+
+func (f *fakeUserRepo) GetUserByEmail(_ context.Context, email string) (*sqlModel.User, error) {
+	return f.find(func(u *sqlModel.User) bool { return u.Email == email })
+}
+
+func (f *fakeUserRepo) UpdateUserPassword(_ context.Context, id int, hash string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[id].PasswordHash = hash
+	f.users[id].FailedAttempts = 0
+	f.users[id].LockedUntil = nil
+	return nil
+}
+
+func (f *fakeUserRepo) RecordFailedLogin(_ context.Context, id, maxAttempts int, lockUntil time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u := f.users[id]
+	if u.FailedAttempts+1 >= maxAttempts {
+		u.FailedAttempts = 0
+		u.LockedUntil = &lockUntil
+		return nil
+	}
+	u.FailedAttempts++
+	return nil
+}
+
+func (f *fakeUserRepo) ResetFailedLogins(_ context.Context, id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[id].FailedAttempts = 0
+	f.users[id].LockedUntil = nil
+	return nil
+}
+
+func (f *fakeUserRepo) CreatePasswordResetToken(_ context.Context, token *sqlModel.PasswordResetToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := *token
+	f.tokens[t.TokenHash] = &t
+	return nil
+}
+
+func (f *fakeUserRepo) ConsumePasswordResetToken(_ context.Context, hash string, now time.Time) (*sqlModel.PasswordResetToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.tokens[hash]
+	if !ok || t.UsedAt != nil || !now.Before(t.ExpiresAt) {
+		return nil, repository.ErrResetTokenNotFound
+	}
+	t.UsedAt = &now
+	c := *t
+	return &c, nil
+}
+
+// captureNotifier records the last reset token it was asked to deliver
+type captureNotifier struct {
+	token string
+}
+
+func (n *captureNotifier) SendPasswordReset(_ context.Context, _ *sqlModel.User, token string, _ time.Time) error {
+	n.token = token
+	return nil
+}
+
+// recordingRevoker records the users whose sessions it was asked to revoke
+type recordingRevoker struct {
+	subjects []string
+}
+
+func (r *recordingRevoker) RevokeSubject(_ context.Context, subject string) error {
+	r.subjects = append(r.subjects, subject)
+	return nil
+}
+
+func newTestUserService(t *testing.T) (*UserService, *fakeUserRepo, *captureNotifier) {
+	t.Helper()
+	repo := newFakeUserRepo()
+	notifier := &captureNotifier{}
+	svc, err := NewUserService(repo, logger.NewLogger(logger.DefaultOptions()), auth.BcryptHasher{Cost: bcrypt.MinCost}, notifier, Config{
+		MaxFailedLogins: 3,
+		LockoutDuration: time.Minute,
+		Sessions:        &recordingRevoker{},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.CreateUser(context.Background(), model.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "password1"})
+	require.NoError(t, err)
+	return svc, repo, notifier
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestUserService(t)
+
+	u, err := repo.GetUserByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.NotEqual(t, "password1", u.PasswordHash)
+
+	_, err = svc.CreateUser(ctx, model.CreateUserRequest{Username: "alice", Email: "other@example.com", Password: "password1"})
+	assert.ErrorIs(t, err, repository.ErrUserExists)
+
+	require.NoError(t, svc.EnsureUser(ctx, model.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "ignored"}))
+	require.NoError(t, svc.EnsureUser(ctx, model.CreateUserRequest{Username: "admin", Email: "admin@example.com", Password: "password1"}, auth.RoleAdmin))
+	p, err := svc.VerifyCredentials(ctx, "admin", "password1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{auth.RoleAdmin}, p.Roles)
+
+	// an existing account keeps its password but gains missing roles
+	require.NoError(t, svc.EnsureUser(ctx, model.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "ignored"}, auth.RoleEditor))
+	p, err = svc.VerifyCredentials(ctx, "alice", "password1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{auth.RoleEditor}, p.Roles)
+}
+
+func TestUserService_Roles(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestUserService(t)
+
+	err := svc.SetUserRoles(ctx, "alice", []string{auth.RoleEditor, "superuser"})
+	assert.ErrorIs(t, err, ErrUnknownRole)
+
+	err = svc.SetUserRoles(ctx, "nobody", []string{auth.RoleEditor})
+	assert.ErrorIs(t, err, repository.ErrUserNotFound)
+
+	require.NoError(t, svc.SetUserRoles(ctx, "alice", []string{auth.RoleEditor, auth.RoleEditor}))
+	roles, err := svc.GetUserRoles(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{auth.RoleEditor}, roles)
+
+	p, err := svc.ResolvePrincipal(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{auth.RoleEditor}, p.Roles)
+
+	_, err = svc.ResolvePrincipal(ctx, "nobody")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestUserService_VerifyCredentials(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestUserService(t)
+
+	p, err := svc.VerifyCredentials(ctx, "alice", "password1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+
+	_, err = svc.VerifyCredentials(ctx, "alice", "wrong")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = svc.VerifyCredentials(ctx, "nobody", "password1")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestUserService_Lockout(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestUserService(t)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	// a successful login resets the count
+	_, _ = svc.VerifyCredentials(ctx, "alice", "wrong")
+	_, _ = svc.VerifyCredentials(ctx, "alice", "wrong")
+	_, err := svc.VerifyCredentials(ctx, "alice", "password1")
+	require.NoError(t, err)
+	u, _ := repo.GetUserByUsername(ctx, "alice")
+	assert.Equal(t, 0, u.FailedAttempts)
+
+	for range 3 {
+		_, err = svc.VerifyCredentials(ctx, "alice", "wrong")
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	}
+
+	// locked: even the correct password is refused
+	_, err = svc.VerifyCredentials(ctx, "alice", "password1")
+	assert.ErrorIs(t, err, auth.ErrAccountLocked)
+
+	now = now.Add(2 * time.Minute)
+	_, err = svc.VerifyCredentials(ctx, "alice", "password1")
+	assert.NoError(t, err)
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestUserService(t)
+
+	revoker := svc.cfg.Sessions.(*recordingRevoker)
+
+	err := svc.ChangePassword(ctx, "alice", model.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "password2"})
+	assert.ErrorIs(t, err, ErrIncorrectPassword)
+	assert.Empty(t, revoker.subjects)
+
+	require.NoError(t, svc.ChangePassword(ctx, "alice", model.ChangePasswordRequest{CurrentPassword: "password1", NewPassword: "password2"}))
+	assert.Equal(t, []string{"alice"}, revoker.subjects, "sessions opened with the old password are revoked")
+
+	_, err = svc.VerifyCredentials(ctx, "alice", "password1")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	_, err = svc.VerifyCredentials(ctx, "alice", "password2")
+	assert.NoError(t, err)
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, notifier := newTestUserService(t)
+
+	require.NoError(t, svc.RequestPasswordReset(ctx, model.ForgotPasswordRequest{Email: "unknown@example.com"}))
+	assert.Empty(t, notifier.token)
+
+	require.NoError(t, svc.RequestPasswordReset(ctx, model.ForgotPasswordRequest{Email: "alice@example.com"}))
+	require.NotEmpty(t, notifier.token)
+	assert.NotContains(t, repo.tokens, notifier.token, "reset tokens must be stored hashed")
+
+	err := svc.ResetPassword(ctx, model.ResetPasswordRequest{Token: "bogus", NewPassword: "password3"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	require.NoError(t, svc.ResetPassword(ctx, model.ResetPasswordRequest{Token: notifier.token, NewPassword: "password3"}))
+	_, err = svc.VerifyCredentials(ctx, "alice", "password3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, svc.cfg.Sessions.(*recordingRevoker).subjects, "a reset ends every session")
+
+	err = svc.ResetPassword(ctx, model.ResetPasswordRequest{Token: notifier.token, NewPassword: "password4"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken, "reset tokens are single use")
+}
+
+func TestUserService_ResetPasswordExpired(t *testing.T) {
+	ctx := context.Background()
+	svc, _, notifier := newTestUserService(t)
+
+	require.NoError(t, svc.RequestPasswordReset(ctx, model.ForgotPasswordRequest{Email: "alice@example.com"}))
+
+	svc.now = func() time.Time { return time.Now().Add(2 * DefaultResetTokenTTL) }
+	err := svc.ResetPassword(ctx, model.ResetPasswordRequest{Token: notifier.token, NewPassword: "password3"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, secret, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(prefix, APIKeyPrefix))
+	assert.Len(t, prefix, len(APIKeyPrefix)+12)
+	assert.Equal(t, prefix+"."+secret, key)
+
+	parsedPrefix, parsedSecret, err := ParseAPIKey(key)
+	require.NoError(t, err)
+	assert.Equal(t, prefix, parsedPrefix)
+	assert.Equal(t, secret, parsedSecret)
+
+	other, _, _, err := GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestParseAPIKey_Invalid(t *testing.T) {
+	for _, key := range []string{"", "ak_abc", "ak_.secret", "ak_abc.", "xx_abc.secret"} {
+		_, _, err := ParseAPIKey(key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, key)
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	salt, err := NewAPIKeySalt()
+	require.NoError(t, err)
+	otherSalt, err := NewAPIKeySalt()
+	require.NoError(t, err)
+
+	hash := HashAPIKey(salt, "secret")
+	assert.NotEqual(t, hash, HashAPIKey(otherSalt, "secret"))
+	assert.True(t, VerifyAPIKey(salt, "secret", hash))
+	assert.False(t, VerifyAPIKey(salt, "wrong", hash))
+	assert.False(t, VerifyAPIKey(otherSalt, "secret", hash))
+}
@@ -0,0 +1,247 @@
+// Package auth provides authentication primitives for the application.
+// It includes JWT issuance and verification, refresh token storage and
+// the authenticated principal carried on the request context.
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	DefaultLeeway          = 30 * time.Second
+)
+
+var (
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrSigningDisabled   = errors.New("token signing is not configured")
+	ErrUnknownAlgorithm  = errors.New("unsupported signing algorithm")
+	ErrMissingSigningKey = errors.New("missing signing key")
+)
+
+// JWTConfig holds the settings for issuing and verifying access tokens.
+// HS256 uses Secret for both. RS256 and ES256 sign with PrivateKeyFile and verify
+// with PublicKeyFile, the public half of the private key, and any keys in JWKSFile.
+// Without a private key the manager can only verify tokens.
+type JWTConfig struct {
+	Algorithm      string
+	Secret         string
+	PrivateKeyFile string
+	PublicKeyFile  string
+	JWKSFile       string
+	KeyID          string
+	Issuer         string
+	Audience       string
+	AccessTTL      time.Duration
+	RefreshTTL     time.Duration
+	Leeway         time.Duration
+}
+
+// AccessClaims are the claims carried by an access token
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// TokenManager issues and verifies JWT access tokens
+type TokenManager struct {
+	cfg        JWTConfig
+	method     jwt.SigningMethod
+	signingKey any
+	verifyKeys map[string]any
+}
+
+// NewTokenManager builds a token manager from the configuration, loading any key files it references
+func NewTokenManager(cfg JWTConfig) (*TokenManager, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = AlgHS256
+	}
+	if cfg.AccessTTL == 0 {
+		cfg.AccessTTL = DefaultAccessTokenTTL
+	}
+	if cfg.RefreshTTL == 0 {
+		cfg.RefreshTTL = DefaultRefreshTokenTTL
+	}
+	if cfg.Leeway == 0 {
+		cfg.Leeway = DefaultLeeway
+	}
+
+	tm := &TokenManager{
+		cfg:        cfg,
+		verifyKeys: map[string]any{},
+	}
+
+	switch cfg.Algorithm {
+	case AlgHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("%w: JWT secret is required for %s", ErrMissingSigningKey, cfg.Algorithm)
+		}
+		tm.method = jwt.SigningMethodHS256
+		tm.signingKey = []byte(cfg.Secret)
+		tm.verifyKeys[cfg.KeyID] = []byte(cfg.Secret)
+		return tm, nil
+	case AlgRS256:
+		tm.method = jwt.SigningMethodRS256
+	case AlgES256:
+		tm.method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, cfg.Algorithm)
+	}
+
+	if cfg.PrivateKeyFile != "" {
+		signer, err := LoadPrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if !keyMatchesAlgorithm(signer.Public(), cfg.Algorithm) {
+			return nil, fmt.Errorf("private key type does not match algorithm %s", cfg.Algorithm)
+		}
+		tm.signingKey = signer
+		tm.verifyKeys[cfg.KeyID] = signer.Public()
+	}
+
+	if cfg.PublicKeyFile != "" {
+		pub, err := LoadPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tm.verifyKeys[cfg.KeyID] = pub
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		for kid, key := range keys {
+			if keyMatchesAlgorithm(key, cfg.Algorithm) {
+				tm.verifyKeys[kid] = key
+			}
+		}
+	}
+
+	if len(tm.verifyKeys) == 0 {
+		return nil, fmt.Errorf("%w: no verification key configured for %s", ErrMissingSigningKey, cfg.Algorithm)
+	}
+
+	return tm, nil
+}
+
+// AccessTTL returns the lifetime of issued access tokens
+func (tm *TokenManager) AccessTTL() time.Duration {
+	return tm.cfg.AccessTTL
+}
+
+// RefreshTTL returns the lifetime of issued refresh tokens
+func (tm *TokenManager) RefreshTTL() time.Duration {
+	return tm.cfg.RefreshTTL
+}
+
+// IssueAccessToken signs a new access token for the principal and returns it with its expiry time
+func (tm *TokenManager) IssueAccessToken(p *Principal) (string, time.Time, error) {
+	if tm.signingKey == nil {
+		return "", time.Time{}, ErrSigningDisabled
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(tm.cfg.AccessTTL)
+	claims := AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   p.Subject,
+			Issuer:    tm.cfg.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Roles: p.Roles,
+	}
+	if tm.cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{tm.cfg.Audience}
+	}
+
+	token := jwt.NewWithClaims(tm.method, claims)
+	if tm.cfg.KeyID != "" {
+		token.Header["kid"] = tm.cfg.KeyID
+	}
+
+	signed, err := token.SignedString(tm.signingKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// VerifyAccessToken checks the signature, expiry, not-before, issuer and audience
+// of an access token and returns the principal it was issued to
+func (tm *TokenManager) VerifyAccessToken(tokenString string) (*Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{tm.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tm.cfg.Leeway),
+	}
+	if tm.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(tm.cfg.Issuer))
+	}
+	if tm.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(tm.cfg.Audience))
+	}
+
+	var claims AccessClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, tm.keyFunc, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return &Principal{
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+		Method:  MethodJWT,
+	}, nil
+}
+
+// keyFunc selects the verification key by the token's kid header, falling back
+// to the only configured key when the token carries no kid
+func (tm *TokenManager) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := tm.verifyKeys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(tm.verifyKeys) == 1 {
+		for _, key := range tm.verifyKeys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func keyMatchesAlgorithm(key crypto.PublicKey, alg string) bool {
+	switch alg {
+	case AlgRS256:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case AlgES256:
+		_, ok := key.(*ecdsa.PublicKey)
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPrincipal = &Principal{Subject: "alice", Roles: []string{"admin"}}
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func TestTokenManager_HS256(t *testing.T) {
+	tm, err := NewTokenManager(JWTConfig{Secret: "secret", Issuer: "api", Audience: "clients"})
+	require.NoError(t, err)
+
+	token, expiresAt, err := tm.IssueAccessToken(testPrincipal)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(DefaultAccessTokenTTL), expiresAt, time.Second)
+
+	p, err := tm.VerifyAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+	assert.Equal(t, []string{"admin"}, p.Roles)
+	assert.Equal(t, MethodJWT, p.Method)
+}
+
+func TestTokenManager_Validation(t *testing.T) {
+	tm, err := NewTokenManager(JWTConfig{Secret: "secret", Issuer: "api", Audience: "clients", Leeway: 5 * time.Second})
+	require.NoError(t, err)
+
+	sign := func(claims AccessClaims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		return s
+	}
+	now := time.Now()
+	valid := func() AccessClaims {
+		return AccessClaims{RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "alice",
+			Issuer:    "api",
+			Audience:  jwt.ClaimStrings{"clients"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(c *AccessClaims)
+	}{
+		{"expired", func(c *AccessClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) }},
+		{"missing expiry", func(c *AccessClaims) { c.ExpiresAt = nil }},
+		{"not yet valid", func(c *AccessClaims) { c.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) }},
+		{"wrong issuer", func(c *AccessClaims) { c.Issuer = "someone-else" }},
+		{"wrong audience", func(c *AccessClaims) { c.Audience = jwt.ClaimStrings{"other"} }},
+		{"missing subject", func(c *AccessClaims) { c.Subject = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(&claims)
+			_, err := tm.VerifyAccessToken(sign(claims))
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	t.Run("within leeway", func(t *testing.T) {
+		claims := valid()
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(-2 * time.Second))
+		_, err := tm.VerifyAccessToken(sign(claims))
+		assert.NoError(t, err)
+	})
+
+	t.Run("algorithm none rejected", func(t *testing.T) {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = tm.VerifyAccessToken(s)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestTokenManager_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	signer, err := NewTokenManager(JWTConfig{Algorithm: AlgRS256, PrivateKeyFile: writePEM(t, "PRIVATE KEY", der), KeyID: "k1"})
+	require.NoError(t, err)
+
+	token, _, err := signer.IssueAccessToken(testPrincipal)
+	require.NoError(t, err)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	verifier, err := NewTokenManager(JWTConfig{Algorithm: AlgRS256, PublicKeyFile: writePEM(t, "PUBLIC KEY", pubDER), KeyID: "k1"})
+	require.NoError(t, err)
+
+	p, err := verifier.VerifyAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+
+	_, _, err = verifier.IssueAccessToken(testPrincipal)
+	assert.ErrorIs(t, err, ErrSigningDisabled)
+}
+
+func TestTokenManager_ES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	tm, err := NewTokenManager(JWTConfig{Algorithm: AlgES256, PrivateKeyFile: writePEM(t, "EC PRIVATE KEY", der)})
+	require.NoError(t, err)
+
+	token, _, err := tm.IssueAccessToken(testPrincipal)
+	require.NoError(t, err)
+
+	p, err := tm.VerifyAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+
+	_, err = NewTokenManager(JWTConfig{Algorithm: AlgRS256, PrivateKeyFile: writePEM(t, "EC PRIVATE KEY", der)})
+	assert.Error(t, err)
+}
+
+func TestTokenManager_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X.Bytes()), "y": b64(ecKey.Y.Bytes())},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+	}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	keys, err := ParseJWKS(data)
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Contains(t, keys, "rsa-1")
+	assert.Contains(t, keys, "ec-1")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	verifier, err := NewTokenManager(JWTConfig{Algorithm: AlgRS256, JWKSFile: path})
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, AccessClaims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "alice",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}})
+	token.Header["kid"] = "rsa-1"
+	signed, err := token.SignedString(rsaKey)
+	require.NoError(t, err)
+
+	p, err := verifier.VerifyAccessToken(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+
+	token.Header["kid"] = "unknown"
+	signed, err = token.SignedString(rsaKey)
+	require.NoError(t, err)
+	_, err = verifier.VerifyAccessToken(signed)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = ParseJWKS([]byte(`{"keys":[]}`))
+	assert.Error(t, err)
+}
+
+func TestNewTokenManager_Errors(t *testing.T) {
+	_, err := NewTokenManager(JWTConfig{})
+	assert.ErrorIs(t, err, ErrMissingSigningKey)
+
+	_, err = NewTokenManager(JWTConfig{Algorithm: "PS512", Secret: "secret"})
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+
+	_, err = NewTokenManager(JWTConfig{Algorithm: AlgRS256})
+	assert.ErrorIs(t, err, ErrMissingSigningKey)
+
+	_, err = NewTokenManager(JWTConfig{Algorithm: AlgRS256, PrivateKeyFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHashers(t *testing.T) {
+	fastArgon := Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		prefix string
+	}{
+		{"argon2id", fastArgon, "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{"bcrypt", BcryptHasher{Cost: bcrypt.MinCost}, "$2a$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("correct horse")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+
+			ok, err := VerifyPassword(hash, "correct horse")
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = VerifyPassword(hash, "battery staple")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			other, err := tt.hasher.Hash("correct horse")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other, "hashes must be salted")
+		})
+	}
+}
+
+func TestVerifyPassword_InvalidHash(t *testing.T) {
+	for _, hash := range []string{"", "plaintext", "$argon2id$v=19$m=1,t=1$bad", "$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5"} {
+		_, err := VerifyPassword(hash, "password")
+		assert.ErrorIs(t, err, ErrUnknownHashFormat, hash)
+	}
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	h, err := NewPasswordHasher("")
+	require.NoError(t, err)
+	assert.IsType(t, Argon2idHasher{}, h)
+
+	h, err = NewPasswordHasher(HashBcrypt)
+	require.NoError(t, err)
+	assert.IsType(t, BcryptHasher{}, h)
+
+	_, err = NewPasswordHasher("md5")
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+}
@@ -0,0 +1,67 @@
+// Package auth provides authentication primitives for the application.
+// It includes JWT issuance and verification, refresh token storage and
+// the authenticated principal carried on the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Authentication methods recorded on a Principal
+const (
+	MethodJWT    = "jwt"
+	MethodBasic  = "basic"
+	MethodAPIKey = "apikey"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
+)
+
+// Principal is the authenticated caller of a request.
+// Permissions are derived from Roles by the authentication middleware.
+type Principal struct {
+	Subject     string       `json:"sub"`
+	Roles       []string     `json:"roles,omitempty"`
+	Permissions []Permission `json:"-"`
+	Method      string       `json:"method"`
+}
+
+// HasRole reports whether the principal has been granted the given role
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// HasPermission reports whether the principal may perform the given action
+func (p *Principal) HasPermission(perm Permission) bool {
+	return slices.Contains(p.Permissions, PermAll) || slices.Contains(p.Permissions, perm)
+}
+
+// CredentialVerifier checks a username and password and returns the matching principal.
+// Implementations return ErrInvalidCredentials when the credentials do not match
+// and ErrAccountLocked while the account is refusing logins.
+type CredentialVerifier interface {
+	VerifyCredentials(ctx context.Context, username, password string) (*Principal, error)
+}
+
+// PrincipalResolver looks up the current principal for a subject, for example
+// to pick up role changes when a refresh token is exchanged
+type PrincipalResolver interface {
+	ResolvePrincipal(ctx context.Context, subject string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal stored on ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithPrincipal(context.Background(), testPrincipal)
+	p, ok := PrincipalFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, testPrincipal, p)
+	assert.True(t, p.HasRole("admin"))
+	assert.False(t, p.HasRole("editor"))
+}
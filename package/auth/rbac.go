@@ -0,0 +1,97 @@
+// Package auth provides authentication primitives for the application.
+// It includes JWT issuance and verification, refresh token storage and
+// the authenticated principal carried on the request context.
+package auth
+
+import (
+	"slices"
+	"sort"
+)
+
+// Permission names an action on a resource, written as "resource:action"
+type Permission string
+
+// Permissions checked by the API
+const (
+	PermProductWrite   Permission = "product:write"
+	PermProductDelete  Permission = "product:delete"
+	PermCategoryWrite  Permission = "category:write"
+	PermCategoryDelete Permission = "category:delete"
+	PermCacheRead      Permission = "cache:read"
+	PermCacheFlush     Permission = "cache:flush"
+	PermUserManage     Permission = "user:manage"
+
+	// PermAll grants every permission
+	PermAll Permission = "*"
+)
+
+// knownPermissions lists every permission checked by the API
+var knownPermissions = []Permission{
+	PermProductWrite, PermProductDelete,
+	PermCategoryWrite, PermCategoryDelete,
+	PermCacheRead, PermCacheFlush,
+	PermUserManage,
+}
+
+// IsKnownPermission reports whether perm is checked by the API or is PermAll
+func IsKnownPermission(perm Permission) bool {
+	return perm == PermAll || slices.Contains(knownPermissions, perm)
+}
+
+// Built-in roles
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+)
+
+// Policy maps role names to the permissions they grant
+type Policy struct {
+	roles map[string][]Permission
+}
+
+// NewPolicy creates a policy from a role to permissions map
+func NewPolicy(roles map[string][]Permission) *Policy {
+	return &Policy{roles: roles}
+}
+
+// DefaultPolicy returns the built-in roles: admin may do anything and
+// editor may manage the catalogue
+func DefaultPolicy() *Policy {
+	return NewPolicy(map[string][]Permission{
+		RoleAdmin: {PermAll},
+		RoleEditor: {
+			PermProductWrite, PermProductDelete,
+			PermCategoryWrite, PermCategoryDelete,
+		},
+	})
+}
+
+// HasRole reports whether the policy defines the role
+func (p *Policy) HasRole(role string) bool {
+	_, ok := p.roles[role]
+	return ok
+}
+
+// Roles returns the names of all roles defined by the policy, sorted
+func (p *Policy) Roles() []string {
+	names := make([]string, 0, len(p.roles))
+	for name := range p.roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Permissions returns the union of the permissions granted by the roles.
+// Unknown roles grant nothing.
+func (p *Policy) Permissions(roles []string) []Permission {
+	var perms []Permission
+	for _, role := range roles {
+		for _, perm := range p.roles[role] {
+			if !slices.Contains(perms, perm) {
+				perms = append(perms, perm)
+			}
+		}
+	}
+	return perms
+}
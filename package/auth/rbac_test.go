@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	assert.Equal(t, []string{RoleAdmin, RoleEditor}, policy.Roles())
+	assert.True(t, policy.HasRole(RoleEditor))
+	assert.False(t, policy.HasRole("superuser"))
+
+	editor := &Principal{Permissions: policy.Permissions([]string{RoleEditor, "unknown"})}
+	assert.True(t, editor.HasPermission(PermProductWrite))
+	assert.True(t, editor.HasPermission(PermCategoryDelete))
+	assert.False(t, editor.HasPermission(PermCacheFlush))
+	assert.False(t, editor.HasPermission(PermUserManage))
+
+	admin := &Principal{Permissions: policy.Permissions([]string{RoleAdmin, RoleEditor})}
+	assert.True(t, admin.HasPermission(PermCacheFlush))
+	assert.True(t, admin.HasPermission("anything:else"))
+
+	assert.Empty(t, policy.Permissions(nil))
+	assert.False(t, (&Principal{}).HasPermission(PermProductWrite))
+}
+
+func TestPolicy_PermissionsAreDeduplicated(t *testing.T) {
+	policy := NewPolicy(map[string][]Permission{
+		"a": {PermProductWrite, PermCategoryWrite},
+		"b": {PermProductWrite},
+	})
+
+	assert.Equal(t, []Permission{PermProductWrite, PermCategoryWrite}, policy.Permissions([]string{"a", "b"}))
+}
+
+func TestIsKnownPermission(t *testing.T) {
+	assert.True(t, IsKnownPermission(PermCacheRead))
+	assert.True(t, IsKnownPermission(PermAll))
+	assert.False(t, IsKnownPermission("product:read"))
+}
@@ -0,0 +1,205 @@
+// Package auth provides authentication primitives for the application.
+// It includes JWT issuance and verification, refresh token storage and
+// the authenticated principal carried on the request context.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	refreshTokenPrefix  = "auth:refresh:"
+	refreshUsedPrefix   = "auth:refresh-used:"
+	refreshFamilyPrefix = "auth:refresh-family:"
+	refreshUserPrefix   = "auth:refresh-user:"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// RefreshSession is the server-side record behind a refresh token.
+// All tokens produced by rotating one login share the same Family.
+type RefreshSession struct {
+	Subject   string    `json:"sub"`
+	Roles     []string  `json:"roles,omitempty"`
+	Family    string    `json:"family"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// RefreshTokenStore persists refresh tokens in Redis. Tokens are single use:
+// Rotate consumes the presented token and issues its successor, and presenting
+// an already consumed token revokes the whole family.
+type RefreshTokenStore struct {
+	client redis.Cmdable
+	ttl    time.Duration
+}
+
+// NewRefreshTokenStore creates a refresh token store backed by the given Redis client
+func NewRefreshTokenStore(client redis.Cmdable, ttl time.Duration) *RefreshTokenStore {
+	if ttl == 0 {
+		ttl = DefaultRefreshTokenTTL
+	}
+	return &RefreshTokenStore{client: client, ttl: ttl}
+}
+
+// Issue creates a refresh token for a new login session
+func (s *RefreshTokenStore) Issue(ctx context.Context, p *Principal) (string, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	return s.issue(ctx, RefreshSession{Subject: p.Subject, Roles: p.Roles, Family: family})
+}
+
+// Rotate consumes a refresh token and returns its session together with a new
+// token in the same family. Reusing a consumed token revokes the family and
+// returns ErrRefreshTokenReused.
+func (s *RefreshTokenStore) Rotate(ctx context.Context, token string) (*RefreshSession, string, error) {
+	session, err := s.consume(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, err := s.issue(ctx, RefreshSession{Subject: session.Subject, Roles: session.Roles, Family: session.Family})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, next, nil
+}
+
+// Revoke invalidates a refresh token and every other token in its family
+func (s *RefreshTokenStore) Revoke(ctx context.Context, token string) error {
+	session, err := s.consume(ctx, token)
+	if err != nil {
+		return err
+	}
+	return s.RevokeFamily(ctx, session.Family)
+}
+
+// RevokeSubject invalidates every outstanding refresh token of a user, for
+// example after a password change
+func (s *RefreshTokenStore) RevokeSubject(ctx context.Context, subject string) error {
+	userKey := refreshUserPrefix + subject
+	families, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		if err := s.RevokeFamily(ctx, family); err != nil {
+			return err
+		}
+	}
+	return s.client.Del(ctx, userKey).Err()
+}
+
+// RevokeFamily invalidates every outstanding refresh token of a login session
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, family string) error {
+	familyKey := refreshFamilyPrefix + family
+	ids, err := s.client.SMembers(ctx, familyKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, refreshTokenPrefix+id)
+	}
+	keys = append(keys, familyKey)
+
+	return s.client.Del(ctx, keys...).Err()
+}
+
+func (s *RefreshTokenStore) issue(ctx context.Context, session RefreshSession) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	session.ExpiresAt = time.Now().Add(s.ttl)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	id := hashToken(token)
+	familyKey := refreshFamilyPrefix + session.Family
+	// The families of a user are recorded so that RevokeSubject can find them
+	userKey := refreshUserPrefix + session.Subject
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, refreshTokenPrefix+id, data, s.ttl)
+	pipe.SAdd(ctx, familyKey, id)
+	pipe.Expire(ctx, familyKey, s.ttl)
+	pipe.SAdd(ctx, userKey, session.Family)
+	pipe.Expire(ctx, userKey, s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// consume atomically removes a refresh token and returns its session.
+// A consumed token is remembered so that a replay can be detected.
+func (s *RefreshTokenStore) consume(ctx context.Context, token string) (*RefreshSession, error) {
+	id := hashToken(token)
+
+	data, err := s.client.GetDel(ctx, refreshTokenPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		family, err := s.client.Get(ctx, refreshUsedPrefix+id).Result()
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := s.RevokeFamily(ctx, family); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session RefreshSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode refresh session: %w", err)
+	}
+
+	if err := s.client.Set(ctx, refreshUsedPrefix+id, session.Family, s.ttl).Err(); err != nil {
+		return nil, err
+	}
+	if err := s.client.SRem(ctx, refreshFamilyPrefix+session.Family, id).Err(); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the storage key for a token so raw tokens never reach Redis
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRefreshStore(t *testing.T) (*RefreshTokenStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRefreshTokenStore(client, time.Hour), mr
+}
+
+func TestRefreshTokenStore_Rotate(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRefreshStore(t)
+
+	first, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+
+	session, second, err := store.Rotate(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", session.Subject)
+	assert.Equal(t, []string{"admin"}, session.Roles)
+	assert.NotEqual(t, first, second)
+
+	session2, third, err := store.Rotate(ctx, second)
+	require.NoError(t, err)
+	assert.Equal(t, session.Family, session2.Family)
+	assert.NotEmpty(t, third)
+}
+
+func TestRefreshTokenStore_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRefreshStore(t)
+
+	first, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+	_, second, err := store.Rotate(ctx, first)
+	require.NoError(t, err)
+
+	_, _, err = store.Rotate(ctx, first)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	_, _, err = store.Rotate(ctx, second)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestRefreshTokenStore_RevokeSubject(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRefreshStore(t)
+
+	first, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+	_, first, err = store.Rotate(ctx, first)
+	require.NoError(t, err)
+	second, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+	other, err := store.Issue(ctx, &Principal{Subject: "bob"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.RevokeSubject(ctx, "alice"))
+	for _, token := range []string{first, second} {
+		_, _, err = store.Rotate(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	}
+
+	// the sessions of other users are left alone
+	_, _, err = store.Rotate(ctx, other)
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokenStore_Revoke(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newTestRefreshStore(t)
+
+	token, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+	other, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Revoke(ctx, token))
+	_, _, err = store.Rotate(ctx, token)
+	assert.Error(t, err)
+
+	// revoking one session leaves the others usable
+	_, _, err = store.Rotate(ctx, other)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, store.Revoke(ctx, "unknown"), ErrInvalidRefreshToken)
+
+	for _, key := range mr.Keys() {
+		assert.NotContains(t, key, token, "raw tokens must not be stored")
+	}
+}
+
+func TestRefreshTokenStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newTestRefreshStore(t)
+
+	token, err := store.Issue(ctx, testPrincipal)
+	require.NoError(t, err)
+
+	mr.FastForward(2 * time.Hour)
+
+	_, _, err = store.Rotate(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
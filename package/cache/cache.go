@@ -0,0 +1,123 @@
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+)
+
+// Backend names accepted by New
+const (
+	BackendRedis   = "redis"
+	BackendMemory  = "memory"
+	BackendLayered = "layered"
+	BackendNone    = "none"
+)
+
+// ErrCacheMiss is returned by Get when the key is not cached
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache stores JSON-encoded values under string keys until their TTL runs
+// out. Keys can be registered under tags, such as "category:5", and every key
+// of a tag removed at once; this is how writes evict the entries they affect.
+// Patterns are globs in the syntax of the Redis KEYS command.
+type Cache interface {
+	// Get decodes the value stored under key into dest, or returns ErrCacheMiss
+	Get(ctx context.Context, key string, dest any) error
+	// Set stores value under key; a zero TTL means the default TTL
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	// SetWithTags stores value under key like Set and registers key under tags
+	SetWithTags(ctx context.Context, key string, value any, ttl time.Duration, tags ...string) error
+	// InvalidateTags removes the keys registered under any of tags
+	InvalidateTags(ctx context.Context, tags ...string) error
+	// Delete removes key
+	Delete(ctx context.Context, key string) error
+	// DeletePattern removes the keys matching pattern. It walks every key, so
+	// writes use tags instead.
+	DeletePattern(ctx context.Context, pattern string) error
+	// CountPattern counts the keys matching pattern
+	CountPattern(ctx context.Context, pattern string) (int64, error)
+	// FlushPattern removes the keys matching pattern and returns how many it removed
+	FlushPattern(ctx context.Context, pattern string) (int64, error)
+	// DBSize returns the number of keys in the cache
+	DBSize(ctx context.Context) (int64, error)
+	// FlushDB removes every key
+	FlushDB(ctx context.Context) error
+	// Info returns a report on the backend for operators
+	Info(ctx context.Context) (string, error)
+	// Close releases the resources of the backend
+	Close() error
+}
+
+// Config selects and configures the cache backend
+type Config struct {
+	// Backend is redis, memory, layered or none
+	Backend string
+	// Fallback is the backend used when Redis cannot be reached on startup.
+	// Empty means startup fails instead.
+	Fallback string
+	// Redis configures the redis backend and the L2 of the layered backend
+	Redis RedisConfig
+	// MaxEntries bounds the memory backend and the L1 of the layered backend
+	MaxEntries int
+	// L1TTL is how long the layered backend keeps an entry in L1
+	L1TTL time.Duration
+	// DefaultTTL is used by Set when it is called without a TTL
+	DefaultTTL time.Duration
+}
+
+// New returns the backend cfg selects. A redis or layered backend whose
+// Redis cannot be reached is replaced by the fallback backend, if there is one.
+func New(cfg *Config, logger *logger.Logger) (Cache, error) {
+	switch cfg.Backend {
+	case BackendRedis, BackendLayered, "":
+		redisCfg := cfg.Redis
+		if redisCfg.DefaultTTL == 0 {
+			redisCfg.DefaultTTL = cfg.DefaultTTL
+		}
+		c, err := NewRedisCache(&redisCfg, logger)
+		if err != nil {
+			if cfg.Fallback == "" {
+				return nil, err
+			}
+			logger.Warn("Redis is unavailable, caching degraded", "fallback", cfg.Fallback, "error", err)
+			return New(&Config{Backend: cfg.Fallback, MaxEntries: cfg.MaxEntries, DefaultTTL: cfg.DefaultTTL}, logger)
+		}
+		if cfg.Backend != BackendLayered {
+			return c, nil
+		}
+		layered, err := NewLayeredCache(NewMemoryCache(cfg.MaxEntries, cfg.L1TTL), c, logger)
+		if err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+		return layered, nil
+	case BackendMemory:
+		return NewMemoryCache(cfg.MaxEntries, cfg.DefaultTTL), nil
+	case BackendNone:
+		return NewNoopCache(), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+	}
+}
+
+// BackendOf returns the name of the backend of c, or an empty string for a
+// Cache implemented outside this package
+func BackendOf(c Cache) string {
+	switch c.(type) {
+	case *RedisCache:
+		return BackendRedis
+	case *MemoryCache:
+		return BackendMemory
+	case *LayeredCache:
+		return BackendLayered
+	case NoopCache:
+		return BackendNone
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableRedis returns the config of a Redis server that is not running
+func unreachableRedis(t *testing.T) RedisConfig {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	mr.Close()
+	return RedisConfig{Host: host, Port: port, ConnectTimeout: 100 * time.Millisecond}
+}
+
+func TestNew(t *testing.T) {
+	log := logger.NewLogger(logger.DefaultOptions())
+
+	t.Run("Redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		host, port, err := net.SplitHostPort(mr.Addr())
+		require.NoError(t, err)
+
+		c, err := New(&Config{Backend: BackendRedis, Redis: RedisConfig{Host: host, Port: port}}, log)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.Close() })
+		assert.Equal(t, BackendRedis, BackendOf(c))
+	})
+
+	t.Run("Layered", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		host, port, err := net.SplitHostPort(mr.Addr())
+		require.NoError(t, err)
+
+		c, err := New(&Config{Backend: BackendLayered, Redis: RedisConfig{Host: host, Port: port}, MaxEntries: 5, L1TTL: time.Second}, log)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.Close() })
+		assert.Equal(t, BackendLayered, BackendOf(c))
+		assert.Equal(t, 5, c.(*LayeredCache).l1.maxEntries)
+		assert.Equal(t, time.Second, c.(*LayeredCache).l1.defaultTTL)
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		c, err := New(&Config{Backend: BackendMemory, MaxEntries: 5}, log)
+		require.NoError(t, err)
+		assert.Equal(t, BackendMemory, BackendOf(c))
+		assert.Equal(t, 5, c.(*MemoryCache).maxEntries)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		c, err := New(&Config{Backend: BackendNone}, log)
+		require.NoError(t, err)
+		assert.Equal(t, BackendNone, BackendOf(c))
+	})
+
+	t.Run("Unreachable Redis", func(t *testing.T) {
+		_, err := New(&Config{Backend: BackendRedis, Redis: unreachableRedis(t)}, log)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unreachable Redis With Fallback", func(t *testing.T) {
+		c, err := New(&Config{Backend: BackendRedis, Fallback: BackendMemory, Redis: unreachableRedis(t)}, log)
+		require.NoError(t, err)
+		assert.Equal(t, BackendMemory, BackendOf(c))
+	})
+
+	t.Run("Unknown Backend", func(t *testing.T) {
+		_, err := New(&Config{Backend: "memcached"}, log)
+		assert.EqualError(t, err, `unknown cache backend "memcached"`)
+	})
+}
+
+func TestNoopCache(t *testing.T) {
+	c := NewNoopCache()
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "product:1", "Phone", 0))
+
+	var got string
+	assert.ErrorIs(t, c.Get(ctx, "product:1", &got), ErrCacheMiss)
+
+	size, err := c.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, size)
+
+	deleted, err := c.FlushPattern(ctx, "product:*")
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+// TestPatterns runs the same patterns against the Redis and memory backends,
+// which must select the same keys
+func TestPatterns(t *testing.T) {
+	ctx := context.Background()
+	keys := []string{"product:1", "product:12", "product:a/b", "products:list", "category:1", "lock:product:1", "a*b", "a?b", "[x]"}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"product:*", []string{"product:1", "product:12", "product:a/b"}},
+		{"product*", []string{"product:1", "product:12", "product:a/b", "products:list"}},
+		{"*product:*", []string{"product:1", "product:12", "product:a/b", "lock:product:1"}},
+		{"*:1", []string{"product:1", "category:1", "lock:product:1"}},
+		{"product:?", []string{"product:1"}},
+		{"product:[0-9]*", []string{"product:1", "product:12"}},
+		{"product:[^0-9]*", []string{"product:a/b"}},
+		{"product:a?b", []string{"product:a/b"}},
+		{`a\*b`, []string{"a*b"}},
+		{"a?b", []string{"a*b", "a?b"}},
+		{`\[x]`, []string{"[x]"}},
+		{"[[]x]", []string{"[x]"}},
+		{"product:[12", []string{"product:1"}},
+		{"order:*", nil},
+	}
+
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	redisCache, err := NewRedisCache(&RedisConfig{Host: host, Port: port}, logger.NewLogger(logger.DefaultOptions()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = redisCache.Close() })
+
+	backends := map[string]Cache{
+		"Redis":  redisCache,
+		"Memory": NewMemoryCache(len(keys), time.Minute),
+	}
+	for name, c := range backends {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range tests {
+				for _, key := range keys {
+					require.NoError(t, c.Set(ctx, key, key, 0))
+				}
+
+				count, err := c.CountPattern(ctx, tt.pattern)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(tt.want)), count, tt.pattern)
+
+				require.NoError(t, c.DeletePattern(ctx, tt.pattern))
+				var deleted []string
+				for _, key := range keys {
+					var value string
+					if errors.Is(c.Get(ctx, key, &value), ErrCacheMiss) {
+						deleted = append(deleted, key)
+					}
+				}
+				assert.ElementsMatch(t, tt.want, deleted, tt.pattern)
+			}
+		})
+	}
+}
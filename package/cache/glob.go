@@ -0,0 +1,94 @@
+package cache
+
+// globMatch reports whether key matches pattern, a glob in the syntax of the
+// Redis KEYS command. It follows stringmatchlen of the Redis sources, so the
+// memory backend selects the same keys as Redis:
+//
+//   - * matches any run of bytes, including none and including '/'
+//   - ? matches a single byte
+//   - [abc], [a-c] and [^abc] match a byte in or out of a set; a class left
+//     open runs to the end of the pattern
+//   - \ makes the next byte literal, inside a class too
+//
+// Like Redis, it works on bytes and never rejects a pattern.
+func globMatch(pattern, key string) bool {
+	var skipLonger bool
+	return globMatchFrom(pattern, key, &skipLonger)
+}
+
+// globMatchFrom implements globMatch. skipLonger is set once a * failed to
+// match the rest of the key from every position, so that the stars before it
+// stop trying longer runs, which cannot match either.
+func globMatchFrom(p, s string, skipLonger *bool) bool {
+	for len(p) > 0 && len(s) > 0 {
+		switch p[0] {
+		case '*':
+			for len(p) > 1 && p[1] == '*' {
+				p = p[1:]
+			}
+			if len(p) == 1 {
+				return true
+			}
+			for len(s) > 0 {
+				if globMatchFrom(p[1:], s, skipLonger) {
+					return true
+				}
+				if *skipLonger {
+					return false
+				}
+				s = s[1:]
+			}
+			*skipLonger = true
+			return false
+		case '?':
+			p, s = p[1:], s[1:]
+		case '[':
+			p = p[1:]
+			negate := len(p) > 0 && p[0] == '^'
+			if negate {
+				p = p[1:]
+			}
+			match := false
+			for len(p) > 0 && p[0] != ']' {
+				switch {
+				case p[0] == '\\' && len(p) >= 2:
+					p = p[1:]
+					match = match || p[0] == s[0]
+				case len(p) >= 3 && p[1] == '-':
+					lo, hi := p[0], p[2]
+					if lo > hi {
+						lo, hi = hi, lo
+					}
+					match = match || (s[0] >= lo && s[0] <= hi)
+					p = p[2:]
+				default:
+					match = match || p[0] == s[0]
+				}
+				p = p[1:]
+			}
+			if match == negate {
+				return false
+			}
+			if len(p) > 0 {
+				p = p[1:]
+			}
+			s = s[1:]
+		case '\\':
+			if len(p) >= 2 {
+				p = p[1:]
+			}
+			fallthrough
+		default:
+			if p[0] != s[0] {
+				return false
+			}
+			p, s = p[1:], s[1:]
+		}
+		if len(s) == 0 {
+			for len(p) > 0 && p[0] == '*' {
+				p = p[1:]
+			}
+		}
+	}
+	return len(p) == 0 && len(s) == 0
+}
@@ -0,0 +1,277 @@
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationChannel is the Redis pub/sub channel on which layered caches
+// tell each other which L1 entries to drop
+const InvalidationChannel = "cache:invalidate"
+
+// Operations of an invalidation message
+const (
+	invalidateKey     = "key"
+	invalidateTags    = "tags"
+	invalidatePattern = "pattern"
+	invalidateAll     = "all"
+)
+
+// invalidation is the message published on InvalidationChannel after a write
+type invalidation struct {
+	// Origin identifies the publishing cache, which ignores its own messages
+	Origin string   `json:"origin"`
+	Op     string   `json:"op"`
+	Target string   `json:"target,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	// Keys lists the keys a tag invalidation removed from L2. The L1 copies of
+	// entries read from L2 are not registered under their tags, so they are
+	// dropped by key.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// LayeredCache keeps the hot entries of a RedisCache (L2) in a small
+// MemoryCache (L1) in each process. Writes go to both tiers and are announced
+// on InvalidationChannel so that the other instances drop their L1 copies.
+// An instance that misses an announcement, for example while reconnecting to
+// Redis, serves its L1 copy until the L1 TTL runs out, which bounds how stale
+// a read can be.
+type LayeredCache struct {
+	l1     *MemoryCache
+	l2     *RedisCache
+	logger *logger.Logger
+	id     string
+	sub    *redis.PubSub
+	done   chan struct{}
+}
+
+// NewLayeredCache puts l1 in front of l2 and subscribes to the invalidations
+// of the other instances. L1 entries live at most for the default TTL of l1.
+func NewLayeredCache(l1 *MemoryCache, l2 *RedisCache, logger *logger.Logger) (*LayeredCache, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate cache instance ID: %w", err)
+	}
+
+	sub := l2.client.Subscribe(context.Background(), InvalidationChannel)
+	// Wait for the subscription so that no invalidation is missed once the
+	// cache is in use
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultConnectTimeout)
+	defer cancel()
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+	}
+
+	c := &LayeredCache{
+		l1:     l1,
+		l2:     l2,
+		logger: logger,
+		id:     hex.EncodeToString(id),
+		sub:    sub,
+		done:   make(chan struct{}),
+	}
+	go c.listen()
+	return c, nil
+}
+
+// Get reads key from L1, then from L2, copying an L2 hit into L1
+func (c *LayeredCache) Get(ctx context.Context, key string, dest any) error {
+	err := c.l1.Get(ctx, key, dest)
+	recordLookup(TierL1, err == nil)
+	if !errors.Is(err, ErrCacheMiss) {
+		return err
+	}
+
+	err = c.l2.Get(ctx, key, dest)
+	if errors.Is(err, ErrCacheMiss) {
+		recordLookup(TierL2, false)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	recordLookup(TierL2, true)
+
+	if err := c.l1.Set(ctx, key, dest, 0); err != nil {
+		c.logger.Warn("failed to copy cache entry to L1", "key", key, "error", err)
+	}
+	return nil
+}
+
+// Set stores value in both tiers. The L1 copy lives at most for the L1 TTL.
+func (c *LayeredCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return c.SetWithTags(ctx, key, value, ttl)
+}
+
+// SetWithTags stores value in both tiers and registers key under tags in each
+func (c *LayeredCache) SetWithTags(ctx context.Context, key string, value any, ttl time.Duration, tags ...string) error {
+	if err := c.l2.SetWithTags(ctx, key, value, ttl, tags...); err != nil {
+		return err
+	}
+	c.publish(ctx, invalidation{Op: invalidateKey, Target: key})
+
+	if ttl == 0 || ttl > c.l1.defaultTTL {
+		ttl = c.l1.defaultTTL
+	}
+	return c.l1.SetWithTags(ctx, key, value, ttl, tags...)
+}
+
+// InvalidateTags removes the keys registered under tags from both tiers and
+// from the L1 of the other instances
+func (c *LayeredCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	_ = c.l1.InvalidateTags(ctx, tags...)
+	keys, err := c.l2.invalidateTags(ctx, tags)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		_ = c.l1.Delete(ctx, key)
+	}
+	c.publish(ctx, invalidation{Op: invalidateTags, Tags: tags, Keys: keys})
+	return nil
+}
+
+// Delete removes key from both tiers and from the L1 of the other instances
+func (c *LayeredCache) Delete(ctx context.Context, key string) error {
+	_ = c.l1.Delete(ctx, key)
+	if err := c.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.publish(ctx, invalidation{Op: invalidateKey, Target: key})
+	return nil
+}
+
+// DeletePattern removes the keys matching pattern from both tiers and from
+// the L1 of the other instances
+func (c *LayeredCache) DeletePattern(ctx context.Context, pattern string) error {
+	if err := c.l1.DeletePattern(ctx, pattern); err != nil {
+		return err
+	}
+	if err := c.l2.DeletePattern(ctx, pattern); err != nil {
+		return err
+	}
+	c.publish(ctx, invalidation{Op: invalidatePattern, Target: pattern})
+	return nil
+}
+
+// CountPattern counts the keys matching pattern in L2
+func (c *LayeredCache) CountPattern(ctx context.Context, pattern string) (int64, error) {
+	return c.l2.CountPattern(ctx, pattern)
+}
+
+// FlushPattern removes the keys matching pattern from both tiers and from
+// the L1 of the other instances, returning how many it removed from L2
+func (c *LayeredCache) FlushPattern(ctx context.Context, pattern string) (int64, error) {
+	if _, err := c.l1.FlushPattern(ctx, pattern); err != nil {
+		return 0, err
+	}
+	deleted, err := c.l2.FlushPattern(ctx, pattern)
+	if err != nil {
+		return deleted, err
+	}
+	c.publish(ctx, invalidation{Op: invalidatePattern, Target: pattern})
+	return deleted, nil
+}
+
+// DBSize returns the number of keys in L2
+func (c *LayeredCache) DBSize(ctx context.Context) (int64, error) {
+	return c.l2.DBSize(ctx)
+}
+
+// FlushDB empties both tiers and the L1 of the other instances
+func (c *LayeredCache) FlushDB(ctx context.Context) error {
+	_ = c.l1.FlushDB(ctx)
+	if err := c.l2.FlushDB(ctx); err != nil {
+		return err
+	}
+	c.publish(ctx, invalidation{Op: invalidateAll})
+	return nil
+}
+
+// Info reports the size of L1 followed by the Redis INFO report
+func (c *LayeredCache) Info(ctx context.Context) (string, error) {
+	l1Size, err := c.l1.DBSize(ctx)
+	if err != nil {
+		return "", err
+	}
+	info, err := c.l2.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("backend:%s\r\nl1_keys:%d\r\nl1_max_entries:%d\r\n%s",
+		BackendLayered, l1Size, c.l1.maxEntries, info), nil
+}
+
+// Close stops listening for invalidations and closes both tiers
+func (c *LayeredCache) Close() error {
+	err := c.sub.Close()
+	<-c.done
+	_ = c.l1.Close()
+	return errors.Join(err, c.l2.Close())
+}
+
+// GetClient returns the Redis client of L2
+func (c *LayeredCache) GetClient() *redis.Client {
+	return c.l2.GetClient()
+}
+
+// publish announces a write to the other instances. A lost announcement is
+// only logged: their L1 copies expire with the L1 TTL.
+func (c *LayeredCache) publish(ctx context.Context, inv invalidation) {
+	inv.Origin = c.id
+	msg, err := json.Marshal(inv)
+	if err != nil {
+		c.logger.Error("failed to marshal cache invalidation", "error", err)
+		return
+	}
+	if err := c.l2.client.Publish(ctx, InvalidationChannel, msg).Err(); err != nil {
+		c.logger.Warn("failed to publish cache invalidation", "op", inv.Op, "target", inv.Target, "tags", inv.Tags, "error", err)
+	}
+}
+
+// listen applies the invalidations of the other instances to L1 until the
+// subscription is closed
+func (c *LayeredCache) listen() {
+	defer close(c.done)
+
+	ctx := context.Background()
+	for msg := range c.sub.Channel() {
+		var inv invalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			c.logger.Warn("ignoring malformed cache invalidation", "payload", msg.Payload, "error", err)
+			continue
+		}
+		if inv.Origin == c.id {
+			continue
+		}
+
+		switch inv.Op {
+		case invalidateKey:
+			_ = c.l1.Delete(ctx, inv.Target)
+		case invalidateTags:
+			_ = c.l1.InvalidateTags(ctx, inv.Tags...)
+			for _, key := range inv.Keys {
+				_ = c.l1.Delete(ctx, key)
+			}
+		case invalidatePattern:
+			if err := c.l1.DeletePattern(ctx, inv.Target); err != nil {
+				c.logger.Warn("failed to apply cache invalidation", "pattern", inv.Target, "error", err)
+			}
+		case invalidateAll:
+			_ = c.l1.FlushDB(ctx)
+		default:
+			c.logger.Warn("ignoring unknown cache invalidation", "op", inv.Op)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLayeredCache returns a layered cache in front of the Redis server mr,
+// standing for one instance of the service
+func newTestLayeredCache(t *testing.T, mr *miniredis.Miniredis) *LayeredCache {
+	t.Helper()
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+
+	log := logger.NewLogger(logger.DefaultOptions())
+	l2, err := NewRedisCache(&RedisConfig{Host: host, Port: port}, log)
+	require.NoError(t, err)
+	c, err := NewLayeredCache(NewMemoryCache(10, time.Minute), l2, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestLayeredCache_Get(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := newTestLayeredCache(t, mr)
+	ctx := context.Background()
+
+	l1Hits := testutil.ToFloat64(lookups.WithLabelValues(TierL1, "hit"))
+	l2Hits := testutil.ToFloat64(lookups.WithLabelValues(TierL2, "hit"))
+	l2Misses := testutil.ToFloat64(lookups.WithLabelValues(TierL2, "miss"))
+
+	// An entry written by another instance is read from L2 and copied to L1
+	require.NoError(t, mr.Set("product:1", `{"name":"Phone"}`))
+	var got map[string]any
+	require.NoError(t, c.Get(ctx, "product:1", &got))
+	assert.Equal(t, "Phone", got["name"])
+
+	// The copy answers even once Redis has lost the entry
+	mr.Del("product:1")
+	got = nil
+	require.NoError(t, c.Get(ctx, "product:1", &got))
+	assert.Equal(t, "Phone", got["name"])
+
+	assert.ErrorIs(t, c.Get(ctx, "product:2", &got), ErrCacheMiss)
+
+	assert.Equal(t, l1Hits+1, testutil.ToFloat64(lookups.WithLabelValues(TierL1, "hit")))
+	assert.Equal(t, l2Hits+1, testutil.ToFloat64(lookups.WithLabelValues(TierL2, "hit")))
+	assert.Equal(t, l2Misses+1, testutil.ToFloat64(lookups.WithLabelValues(TierL2, "miss")))
+}
+
+func TestLayeredCache_SetCapsL1TTL(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := newTestLayeredCache(t, mr)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "product:1", "Phone", time.Hour))
+	assert.Equal(t, time.Hour, mr.TTL("product:1"))
+
+	c.l1.mu.Lock()
+	expiresAt := c.l1.entries["product:1"].Value.(*memoryEntry).expiresAt
+	c.l1.mu.Unlock()
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+}
+
+func TestLayeredCache_Invalidation(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := newTestLayeredCache(t, mr)
+	b := newTestLayeredCache(t, mr)
+	ctx := context.Background()
+
+	// inL1 reports whether b holds key in its L1
+	inL1 := func(key string) bool {
+		var v string
+		return b.l1.Get(ctx, key, &v) == nil
+	}
+
+	t.Run("Set", func(t *testing.T) {
+		require.NoError(t, a.Set(ctx, "product:1", "Phone", 0))
+		var got string
+		require.NoError(t, b.Get(ctx, "product:1", &got))
+		require.True(t, inL1("product:1"))
+
+		require.NoError(t, a.Set(ctx, "product:1", "Tablet", 0))
+		assert.Eventually(t, func() bool { return !inL1("product:1") }, time.Second, 10*time.Millisecond)
+		require.NoError(t, b.Get(ctx, "product:1", &got))
+		assert.Equal(t, "Tablet", got)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, a.Set(ctx, "product:2", "Phone", 0))
+		var got string
+		require.NoError(t, b.Get(ctx, "product:2", &got))
+
+		require.NoError(t, a.Delete(ctx, "product:2"))
+		assert.Eventually(t, func() bool { return !inL1("product:2") }, time.Second, 10*time.Millisecond)
+		assert.ErrorIs(t, b.Get(ctx, "product:2", &got), ErrCacheMiss)
+	})
+
+	t.Run("InvalidateTags", func(t *testing.T) {
+		require.NoError(t, a.SetWithTags(ctx, "product:5", "Phone", 0, "category:1"))
+		var got string
+		require.NoError(t, b.Get(ctx, "product:5", &got))
+		require.NoError(t, b.SetWithTags(ctx, "product:6", "Tablet", 0, "category:1"))
+
+		require.NoError(t, a.InvalidateTags(ctx, "category:1"))
+		assert.Eventually(t, func() bool { return !inL1("product:5") && !inL1("product:6") }, time.Second, 10*time.Millisecond)
+		assert.False(t, mr.Exists("product:6"))
+	})
+
+	t.Run("DeletePattern", func(t *testing.T) {
+		require.NoError(t, a.Set(ctx, "products:page:1", "list", 0))
+		require.NoError(t, b.Set(ctx, "category:1", "Phones", 0))
+		var got string
+		require.NoError(t, b.Get(ctx, "products:page:1", &got))
+
+		require.NoError(t, a.DeletePattern(ctx, "products:*"))
+		assert.Eventually(t, func() bool { return !inL1("products:page:1") }, time.Second, 10*time.Millisecond)
+		assert.True(t, inL1("category:1"))
+	})
+
+	t.Run("FlushDB", func(t *testing.T) {
+		require.NoError(t, b.Set(ctx, "category:2", "Tablets", 0))
+
+		require.NoError(t, a.FlushDB(ctx))
+		assert.Eventually(t, func() bool { return !inL1("category:2") }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Own Writes", func(t *testing.T) {
+		// An instance keeps the L1 copy of its own write
+		require.NoError(t, a.Set(ctx, "product:3", "Phone", 0))
+		require.NoError(t, b.Set(ctx, "product:4", "Tablet", 0))
+		time.Sleep(50 * time.Millisecond)
+		assert.True(t, inL1("product:4"))
+	})
+}
+
+func TestLayeredCache_Info(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := newTestLayeredCache(t, mr)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "product:1", "Phone", 0))
+	info, err := c.Info(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, info, "backend:layered\r\nl1_keys:1\r\nl1_max_entries:10\r\n")
+
+	size, err := c.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), size)
+}
@@ -0,0 +1,392 @@
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	mathrand "math/rand/v2"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultLockTimeout bounds how long a load holds its lock and how long
+	// the other instances wait for it
+	DefaultLockTimeout = 5 * time.Second
+
+	// loadTimeout bounds a load, which outlives the request that started it
+	// when other requests wait for it or it refreshes an entry in the background
+	loadTimeout = 30 * time.Second
+	// lockPollInterval is how often an instance waiting for another one's
+	// load checks the cache
+	lockPollInterval = 50 * time.Millisecond
+	// lockPrefix prefixes the keys of the locks taken while loading a key
+	lockPrefix = "lock:"
+)
+
+// unlockScript deletes a lock only while it still holds the token of the
+// instance releasing it, so that a lock that expired and was taken by another
+// instance is left alone
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// LoadOptions configures how a Loader protects the database when entries
+// expire
+type LoadOptions struct {
+	// StaleTTL is how long an entry is kept once it goes stale, to be served
+	// by StaleWhileRevalidate and StaleIfError
+	StaleTTL time.Duration
+	// StaleWhileRevalidate serves a stale entry at once and refreshes it in
+	// the background
+	StaleWhileRevalidate bool
+	// StaleIfError serves a stale entry when loading a fresh one fails
+	StaleIfError bool
+	// StaleOn reports whether StaleIfError may replace an error with a stale
+	// entry. Errors that are answers rather than failures, such as invalid
+	// input, must be returned as they are. Nil allows every error but the
+	// NotFound ones.
+	StaleOn func(error) bool
+	// EarlyExpiry refreshes entries in the background shortly before they go
+	// stale, the more likely the closer they are, so that popular keys are
+	// reloaded before every request misses them at once
+	EarlyExpiry bool
+	// Lock makes instances sharing a Redis cache take a lock before loading
+	// a key, so that only one of them queries the database while the others
+	// wait for its result
+	Lock bool
+	// LockTimeout bounds how long a lock is held and waited for; zero means
+	// DefaultLockTimeout
+	LockTimeout time.Duration
+	// NegativeTTL is how long a load that failed with one of the NotFound
+	// errors is remembered, so that requests for missing values do not reach
+	// the database; zero turns negative caching off
+	NegativeTTL time.Duration
+	// NotFound lists the errors meaning that a value does not exist. They are
+	// cached by message, so each needs its own.
+	NotFound []error
+	// LoadContext, when set, derives the context of loads whose result is
+	// cached, such as one reading from the primary database so that a lagging
+	// replica cannot put outdated values in the cache
+	LoadContext func(context.Context) context.Context
+}
+
+// Loader reads values through a cache, loading and storing them on a miss.
+// Concurrent misses of a key in a process share a single load.
+type Loader struct {
+	cache  Cache
+	opts   LoadOptions
+	logger *logger.Logger
+	group  singleflight.Group
+	// locker takes the locks of LoadOptions.Lock; nil when locking is off or
+	// the cache is not kept in Redis
+	locker redis.Cmdable
+	now    func() time.Time
+	// random returns a number in (0, 1] for early expiry
+	random func() float64
+}
+
+// loadedEntry is what a Loader stores under a key
+type loadedEntry struct {
+	Value json.RawMessage `json:"value,omitempty"`
+	// Missing is the message of the NotFound error the load failed with, for
+	// entries remembering that the value does not exist
+	Missing string `json:"missing,omitempty"`
+	// StaleAt is when the value stops being fresh
+	StaleAt time.Time `json:"stale_at"`
+	// LoadTime is how long the value took to load. Slow values are refreshed
+	// earlier by early expiry.
+	LoadTime time.Duration `json:"load_time"`
+}
+
+// NewLoader returns a Loader reading through c. Locking needs a cache kept in
+// Redis and is off for the other backends.
+func NewLoader(c Cache, opts LoadOptions, logger *logger.Logger) *Loader {
+	if !opts.StaleWhileRevalidate && !opts.StaleIfError {
+		opts.StaleTTL = 0
+	}
+	// Nothing loaded through a NoopCache is kept, so its loads are plain reads
+	if _, ok := c.(NoopCache); ok {
+		opts.LoadContext = nil
+	}
+	if opts.LockTimeout == 0 {
+		opts.LockTimeout = DefaultLockTimeout
+	}
+
+	l := &Loader{
+		cache:  c,
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+		random: func() float64 { return 1 - mathrand.Float64() },
+	}
+	if redisCache, ok := c.(interface{ GetClient() *redis.Client }); ok && opts.Lock {
+		l.locker = redisCache.GetClient()
+	}
+	return l
+}
+
+// Load returns the value cached under key, calling load and caching its
+// result for ttl under tags when there is none; a zero ttl means DefaultTTL.
+// Failed loads are replaced by stale values only when StaleOn allows it. The
+// NotFound errors are never replaced, and are cached for NegativeTTL under
+// tags too.
+func Load[T any](ctx context.Context, l *Loader, key string, ttl time.Duration, load func(ctx context.Context) (T, error), tags ...string) (T, error) {
+	return loadTagged(ctx, l, key, ttl, load, func(T) []string { return tags }, tags)
+}
+
+// LoadTagged is Load for entries whose tags depend on the loaded value, such
+// as the category of a product. The NotFound errors are cached without tags,
+// so whoever creates the value deletes key.
+func LoadTagged[T any](ctx context.Context, l *Loader, key string, ttl time.Duration, load func(ctx context.Context) (T, error), tags func(T) []string) (T, error) {
+	return loadTagged(ctx, l, key, ttl, load, tags, nil)
+}
+
+// loadTagged implements Load and LoadTagged. missingTags are the tags of the
+// entry caching a NotFound error.
+func loadTagged[T any](ctx context.Context, l *Loader, key string, ttl time.Duration, load func(ctx context.Context) (T, error), tags func(T) []string, missingTags []string) (T, error) {
+	var value T
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	loadAny := func(ctx context.Context) (any, []string, error) {
+		v, err := load(ctx)
+		if err != nil {
+			return nil, missingTags, err
+		}
+		return v, tags(v), nil
+	}
+
+	var entry loadedEntry
+	hit := l.cache.Get(ctx, key, &entry) == nil
+	if hit && entry.Missing != "" {
+		// A missing value is never served stale, as it may have been created since
+		if err := l.missing(&entry); err != nil {
+			recordLoad(key, LoadNegativeHit)
+			return value, err
+		}
+		hit = false
+	}
+	if hit {
+		now := l.now()
+		fresh := now.Before(entry.StaleAt)
+		if (fresh && l.expiresEarly(now, &entry)) || (!fresh && l.opts.StaleWhileRevalidate) {
+			l.refresh(ctx, key, ttl, loadAny)
+		}
+		if fresh {
+			recordLoad(key, LoadHit)
+			return value, decodeLoaded(entry.Value, &value)
+		}
+		if l.opts.StaleWhileRevalidate {
+			recordLoad(key, LoadStale)
+			return value, decodeLoaded(entry.Value, &value)
+		}
+	}
+
+	recordLoad(key, LoadMiss)
+	data, err := l.fill(ctx, key, ttl, loadAny)
+	if err != nil {
+		if hit && l.opts.StaleIfError && l.staleOn(err) && ctx.Err() == nil {
+			l.logger.Warn("serving stale cache entry", "key", key, "error", err)
+			recordLoad(key, LoadStale)
+			return value, decodeLoaded(entry.Value, &value)
+		}
+		return value, err
+	}
+	return value, decodeLoaded(data, &value)
+}
+
+// decodeLoaded decodes a value stored by a Loader. Every caller decodes its
+// own copy, so that callers sharing a load do not share the value.
+func decodeLoaded(data []byte, dest any) error {
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal cached value: %w", err)
+	}
+	return nil
+}
+
+// missing returns the NotFound error cached by entry, or nil when the entry
+// is stale or its error is no longer one of the NotFound errors
+func (l *Loader) missing(entry *loadedEntry) error {
+	if !l.now().Before(entry.StaleAt) {
+		return nil
+	}
+	for _, err := range l.opts.NotFound {
+		if err.Error() == entry.Missing {
+			return err
+		}
+	}
+	return nil
+}
+
+// notFound returns the NotFound error err matches, or nil
+func (l *Loader) notFound(err error) error {
+	for _, notFound := range l.opts.NotFound {
+		if errors.Is(err, notFound) {
+			return notFound
+		}
+	}
+	return nil
+}
+
+// staleOn reports whether a failed load may be answered with a stale entry
+func (l *Loader) staleOn(err error) bool {
+	if l.notFound(err) != nil {
+		return false
+	}
+	return l.opts.StaleOn == nil || l.opts.StaleOn(err)
+}
+
+// expiresEarly decides whether a fresh entry is refreshed now, with the
+// probabilistic early expiration of Vattani et al. ("Optimal Probabilistic
+// Cache Stampede Prevention"): the chance grows as the entry nears StaleAt,
+// and sooner for values that are slow to load.
+func (l *Loader) expiresEarly(now time.Time, entry *loadedEntry) bool {
+	if !l.opts.EarlyExpiry || entry.LoadTime <= 0 {
+		return false
+	}
+	gap := time.Duration(-float64(entry.LoadTime) * math.Log(l.random()))
+	return !now.Add(gap).Before(entry.StaleAt)
+}
+
+// fill loads key and caches the result, sharing the load with the other
+// callers waiting for the same key. It returns the encoded value.
+func (l *Loader) fill(ctx context.Context, key string, ttl time.Duration, load func(context.Context) (any, []string, error)) ([]byte, error) {
+	ch := l.group.DoChan(key, func() (any, error) {
+		// The load is shared, so the request that happens to start it must not
+		// cancel it for the others
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		return l.loadLocked(loadCtx, key, ttl, load)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]byte), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh reloads key in the background, unless a load of it is running
+func (l *Loader) refresh(ctx context.Context, key string, ttl time.Duration, load func(context.Context) (any, []string, error)) {
+	l.group.DoChan(key, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		data, err := l.loadLocked(loadCtx, key, ttl, load)
+		if err != nil {
+			l.logger.Warn("failed to refresh cache entry", "key", key, "error", err)
+		}
+		return data, err
+	})
+}
+
+// loadLocked loads key under the lock of LoadOptions.Lock. When another
+// instance holds the lock, it waits for that instance to cache the value and
+// only loads it itself if that does not happen in time.
+func (l *Loader) loadLocked(ctx context.Context, key string, ttl time.Duration, load func(context.Context) (any, []string, error)) ([]byte, error) {
+	if l.locker != nil {
+		unlock, locked := l.lock(ctx, key)
+		if locked {
+			defer unlock()
+		} else if entry, ok := l.await(ctx, key); ok {
+			if entry.Missing != "" {
+				return nil, l.missing(entry)
+			}
+			return entry.Value, nil
+		}
+	}
+
+	if l.opts.LoadContext != nil {
+		ctx = l.opts.LoadContext(ctx)
+	}
+	start := l.now()
+	value, tags, err := load(ctx)
+	if err != nil {
+		if notFound := l.notFound(err); notFound != nil && l.opts.NegativeTTL > 0 {
+			entry := loadedEntry{Missing: notFound.Error(), StaleAt: l.now().Add(l.opts.NegativeTTL)}
+			if err := l.cache.SetWithTags(ctx, key, entry, l.opts.NegativeTTL, tags...); err != nil {
+				l.logger.Warn("failed to cache missing value", "key", key, "error", err)
+			}
+		}
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	now := l.now()
+	entry := loadedEntry{Value: data, StaleAt: now.Add(ttl), LoadTime: now.Sub(start)}
+	if err := l.cache.SetWithTags(ctx, key, entry, ttl+l.opts.StaleTTL, tags...); err != nil {
+		l.logger.Warn("failed to cache loaded value", "key", key, "error", err)
+	}
+	return data, nil
+}
+
+// lock takes the lock of key. It reports false when another instance holds
+// it; a lock that cannot be taken because Redis fails counts as taken, so
+// that loads go on without it.
+func (l *Loader) lock(ctx context.Context, key string) (unlock func(), locked bool) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return func() {}, true
+	}
+	value := hex.EncodeToString(token)
+
+	ok, err := l.locker.SetNX(ctx, lockPrefix+key, value, l.opts.LockTimeout).Result()
+	if err != nil {
+		l.logger.Warn("failed to take cache lock", "key", key, "error", err)
+		return func() {}, true
+	}
+	if !ok {
+		return nil, false
+	}
+	return func() {
+		if err := unlockScript.Run(context.WithoutCancel(ctx), l.locker, []string{lockPrefix + key}, value).Err(); err != nil {
+			l.logger.Warn("failed to release cache lock", "key", key, "error", err)
+		}
+	}, true
+}
+
+// await waits up to LockTimeout for another instance to cache a fresh value
+// or a missing one under key
+func (l *Loader) await(ctx context.Context, key string) (*loadedEntry, bool) {
+	timer := time.NewTimer(l.opts.LockTimeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var entry loadedEntry
+			err := l.cache.Get(ctx, key, &entry)
+			if err == nil && l.now().Before(entry.StaleAt) && (entry.Missing == "" || l.missing(&entry) != nil) {
+				return &entry, true
+			}
+			if err != nil && !errors.Is(err, ErrCacheMiss) {
+				return nil, false
+			}
+		case <-timer.C:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
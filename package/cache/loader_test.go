@@ -0,0 +1,354 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLoader returns a loader over a memory cache whose clock only moves
+// when the returned function is called
+func newTestLoader(opts LoadOptions) (*Loader, func(time.Duration)) {
+	l := NewLoader(NewMemoryCache(10, time.Hour), opts, logger.NewLogger(logger.DefaultOptions()))
+	now := time.Now()
+	var mu sync.Mutex
+	l.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	return l, func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+}
+
+// counter returns a load function answering value and the number of times
+// it was called
+func counter(value string, err error) (func(context.Context) (string, error), *atomic.Int32) {
+	calls := &atomic.Int32{}
+	return func(context.Context) (string, error) {
+		calls.Add(1)
+		return value, err
+	}, calls
+}
+
+func TestLoad_Hit(t *testing.T) {
+	l, _ := newTestLoader(LoadOptions{})
+	ctx := context.Background()
+	load, calls := counter("Phone", nil)
+
+	for range 3 {
+		got, err := Load(ctx, l, "product:1", time.Minute, load)
+		require.NoError(t, err)
+		assert.Equal(t, "Phone", got)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLoad_LoadContext(t *testing.T) {
+	type marker struct{}
+	opts := LoadOptions{LoadContext: func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, marker{}, true)
+	}}
+	marked := func(ctx context.Context) (bool, error) {
+		v, _ := ctx.Value(marker{}).(bool)
+		return v, nil
+	}
+
+	l, _ := newTestLoader(opts)
+	got, err := Load(context.Background(), l, "product:1", time.Minute, marked)
+	require.NoError(t, err)
+	assert.True(t, got, "cached loads run with the derived context")
+
+	noop := NewLoader(NewNoopCache(), opts, logger.NewLogger(logger.DefaultOptions()))
+	got, err = Load(context.Background(), noop, "product:1", time.Minute, marked)
+	require.NoError(t, err)
+	assert.False(t, got, "loads that are not cached run with the request context")
+}
+
+func TestLoad_Tags(t *testing.T) {
+	l, _ := newTestLoader(LoadOptions{})
+	ctx := context.Background()
+
+	_, err := Load(ctx, l, "product:list:a", time.Minute, func(context.Context) (string, error) { return "page", nil }, "product-list")
+	require.NoError(t, err)
+	_, err = LoadTagged(ctx, l, "product:1", time.Minute,
+		func(context.Context) (int, error) { return 5, nil },
+		func(categoryID int) []string { return []string{fmt.Sprintf("category:%d", categoryID)} })
+	require.NoError(t, err)
+
+	require.NoError(t, l.cache.InvalidateTags(ctx, "category:5"))
+	size, err := l.cache.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), size)
+
+	require.NoError(t, l.cache.InvalidateTags(ctx, "product-list"))
+	size, err = l.cache.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, size)
+}
+
+func TestLoad_Coalescing(t *testing.T) {
+	l, _ := newTestLoader(LoadOptions{})
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "Phone", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan string, 20)
+	for range 20 {
+		wg.Go(func() {
+			got, err := Load(ctx, l, "product:1", time.Minute, load)
+			assert.NoError(t, err)
+			results <- got
+		})
+	}
+	// Give every request time to join the load before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	assert.Equal(t, int32(1), calls.Load())
+	for got := range results {
+		assert.Equal(t, "Phone", got)
+	}
+}
+
+func TestLoad_Expired(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Reloads", func(t *testing.T) {
+		l, advance := newTestLoader(LoadOptions{})
+		_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "Phone", nil })
+		require.NoError(t, err)
+
+		advance(2 * time.Minute)
+		got, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "Tablet", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "Tablet", got)
+	})
+
+	t.Run("Stale If Error", func(t *testing.T) {
+		errInvalid := errors.New("invalid cursor")
+		notFound := errors.New("product not found")
+		l, advance := newTestLoader(LoadOptions{
+			StaleIfError: true,
+			StaleTTL:     time.Hour,
+			StaleOn:      func(err error) bool { return !errors.Is(err, errInvalid) },
+			NotFound:     []error{notFound},
+		})
+		_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "Phone", nil })
+		require.NoError(t, err)
+
+		advance(2 * time.Minute)
+		got, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) {
+			return "", errors.New("connection refused")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Phone", got)
+
+		// Errors the client can act on are answers, not failures
+		_, err = Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "", errInvalid })
+		assert.ErrorIs(t, err, errInvalid)
+		_, err = Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "", notFound })
+		assert.ErrorIs(t, err, notFound)
+	})
+
+	t.Run("Error Without Stale", func(t *testing.T) {
+		l, advance := newTestLoader(LoadOptions{})
+		_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "Phone", nil })
+		require.NoError(t, err)
+
+		advance(2 * time.Minute)
+		_, err = Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) {
+			return "", errors.New("connection refused")
+		})
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("Stale While Revalidate", func(t *testing.T) {
+		l, advance := newTestLoader(LoadOptions{StaleWhileRevalidate: true, StaleTTL: time.Hour})
+		_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "Phone", nil })
+		require.NoError(t, err)
+
+		advance(2 * time.Minute)
+		load, calls := counter("Tablet", nil)
+		got, err := Load(ctx, l, "product:1", time.Minute, load)
+		require.NoError(t, err)
+		assert.Equal(t, "Phone", got)
+
+		// The refresh runs in the background and replaces the stale entry
+		assert.Eventually(t, func() bool {
+			got, err := Load(ctx, l, "product:1", time.Minute, load)
+			return err == nil && got == "Tablet"
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestLoad_NotFound(t *testing.T) {
+	ctx := context.Background()
+	notFound := errors.New("product not found")
+	opts := LoadOptions{NegativeTTL: time.Second, NotFound: []error{notFound}}
+
+	t.Run("Cached", func(t *testing.T) {
+		l, advance := newTestLoader(opts)
+		negativeHits := testutil.ToFloat64(loads.WithLabelValues("product", LoadNegativeHit))
+		calls := 0
+		load := func(context.Context) (string, error) {
+			calls++
+			return "", fmt.Errorf("get product: %w", notFound)
+		}
+
+		for range 3 {
+			_, err := Load(ctx, l, "product:1", time.Minute, load)
+			assert.ErrorIs(t, err, notFound)
+		}
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, negativeHits+2, testutil.ToFloat64(loads.WithLabelValues("product", LoadNegativeHit)))
+
+		// The missing value is looked up again once NegativeTTL runs out
+		advance(2 * time.Second)
+		_, err := Load(ctx, l, "product:1", time.Minute, load)
+		assert.ErrorIs(t, err, notFound)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Created", func(t *testing.T) {
+		l, _ := newTestLoader(opts)
+		_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "", notFound }, "product-list")
+		require.ErrorIs(t, err, notFound)
+
+		// Creating the value removes the entry by key or by tag
+		require.NoError(t, l.cache.InvalidateTags(ctx, "product-list"))
+		got, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) { return "Phone", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "Phone", got)
+	})
+
+	t.Run("Other Errors", func(t *testing.T) {
+		l, _ := newTestLoader(opts)
+		load, calls := counter("", errors.New("category not found"))
+		for range 2 {
+			_, err := Load(ctx, l, "product:1", time.Minute, load)
+			assert.Error(t, err)
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		l, _ := newTestLoader(LoadOptions{NotFound: opts.NotFound})
+		load, calls := counter("", notFound)
+		for range 2 {
+			_, err := Load(ctx, l, "product:1", time.Minute, load)
+			assert.ErrorIs(t, err, notFound)
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestLoad_EarlyExpiry(t *testing.T) {
+	ctx := context.Background()
+	l, advance := newTestLoader(LoadOptions{EarlyExpiry: true})
+
+	// The value takes a second to load
+	_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) {
+		advance(time.Second)
+		return "Phone", nil
+	})
+	require.NoError(t, err)
+	load, calls := counter("Tablet", nil)
+
+	// Far from expiry the draw keeps the entry
+	l.random = func() float64 { return 0.5 }
+	got, err := Load(ctx, l, "product:1", time.Minute, load)
+	require.NoError(t, err)
+	assert.Equal(t, "Phone", got)
+	assert.Equal(t, int32(0), calls.Load())
+
+	// Near expiry the same draw refreshes it in the background
+	advance(time.Minute - 500*time.Millisecond)
+	got, err = Load(ctx, l, "product:1", time.Minute, load)
+	require.NoError(t, err)
+	assert.Equal(t, "Phone", got)
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestLoad_Lock(t *testing.T) {
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	log := logger.NewLogger(logger.DefaultOptions())
+	ctx := context.Background()
+
+	// newInstance returns the loader of one instance of the service
+	newInstance := func(opts LoadOptions) *Loader {
+		c, err := NewRedisCache(&RedisConfig{Host: host, Port: port}, log)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.Close() })
+		return NewLoader(c, opts, log)
+	}
+
+	t.Run("Released", func(t *testing.T) {
+		l := newInstance(LoadOptions{Lock: true})
+		_, err := Load(ctx, l, "product:1", time.Minute, func(context.Context) (string, error) {
+			assert.True(t, mr.Exists("lock:product:1"))
+			return "Phone", nil
+		})
+		require.NoError(t, err)
+		assert.False(t, mr.Exists("lock:product:1"))
+	})
+
+	t.Run("Waits For Holder", func(t *testing.T) {
+		l := newInstance(LoadOptions{Lock: true})
+		other := newInstance(LoadOptions{})
+		require.NoError(t, mr.Set("lock:product:2", "other"))
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = Load(ctx, other, "product:2", time.Minute, func(context.Context) (string, error) { return "Phone", nil })
+		}()
+
+		load, calls := counter("Tablet", nil)
+		got, err := Load(ctx, l, "product:2", time.Minute, load)
+		require.NoError(t, err)
+		assert.Equal(t, "Phone", got)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("Holder Times Out", func(t *testing.T) {
+		l := newInstance(LoadOptions{Lock: true, LockTimeout: 100 * time.Millisecond})
+		require.NoError(t, mr.Set("lock:product:3", "other"))
+
+		got, err := Load(ctx, l, "product:3", time.Minute, func(context.Context) (string, error) { return "Tablet", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "Tablet", got)
+		// The lock of the other instance is left alone
+		assert.True(t, mr.Exists("lock:product:3"))
+	})
+
+	t.Run("Memory Backend", func(t *testing.T) {
+		l := NewLoader(NewMemoryCache(10, time.Hour), LoadOptions{Lock: true}, log)
+		assert.Nil(t, l.locker)
+	})
+}
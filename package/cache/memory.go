@@ -0,0 +1,260 @@
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries bounds a MemoryCache created without a size
+const DefaultMaxEntries = 10000
+
+// MemoryCache is a Cache held in the memory of the process. It keeps at most
+// a fixed number of entries, evicting the least recently used one to make
+// room, and drops entries once their TTL runs out. Values are stored encoded,
+// like in Redis, so that callers never share them.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	defaultTTL time.Duration
+	// order lists the entries from most to least recently used
+	order   *list.List
+	entries map[string]*list.Element
+	// tags holds the keys registered under each tag
+	tags map[string]map[string]struct{}
+	now  func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// NewMemoryCache returns an empty MemoryCache holding at most maxEntries
+// entries. Zero values select DefaultMaxEntries and DefaultTTL.
+func NewMemoryCache(maxEntries int, defaultTTL time.Duration) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if defaultTTL == 0 {
+		defaultTTL = DefaultTTL
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		defaultTTL: defaultTTL,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		now:        time.Now,
+	}
+}
+
+// Get decodes the value stored under key into dest
+func (c *MemoryCache) Get(_ context.Context, key string, dest any) error {
+	c.mu.Lock()
+	elem, ok := c.live(key)
+	var data []byte
+	if ok {
+		c.order.MoveToFront(elem)
+		data = elem.Value.(*memoryEntry).data
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return ErrCacheMiss
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal cached value: %w", err)
+	}
+	return nil
+}
+
+// Set stores value under key, evicting the least recently used entry when
+// the cache is full
+func (c *MemoryCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return c.SetWithTags(ctx, key, value, ttl)
+}
+
+// SetWithTags stores value under key like Set and registers key under tags
+func (c *MemoryCache) SetWithTags(_ context.Context, key string, value any, ttl time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, data: data, expiresAt: c.now().Add(ttl), tags: tags})
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// InvalidateTags removes the keys registered under tags
+func (c *MemoryCache) InvalidateTags(_ context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if elem, ok := c.entries[key]; ok {
+				c.remove(elem)
+			}
+		}
+		delete(c.tags, tag)
+	}
+	return nil
+}
+
+// Delete removes key
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+// DeletePattern removes the keys matching pattern
+func (c *MemoryCache) DeletePattern(ctx context.Context, pattern string) error {
+	_, err := c.FlushPattern(ctx, pattern)
+	return err
+}
+
+// CountPattern counts the live keys matching pattern
+func (c *MemoryCache) CountPattern(_ context.Context, pattern string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count int64
+	c.match(pattern, func(*list.Element) { count++ })
+	return count, nil
+}
+
+// FlushPattern removes the keys matching pattern and returns how many live
+// keys it removed
+func (c *MemoryCache) FlushPattern(_ context.Context, pattern string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var deleted int64
+	c.match(pattern, func(elem *list.Element) {
+		c.remove(elem)
+		deleted++
+	})
+	return deleted, nil
+}
+
+// DBSize returns the number of live keys
+func (c *MemoryCache) DBSize(_ context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purgeExpired()
+	return int64(c.order.Len()), nil
+}
+
+// FlushDB removes every key
+func (c *MemoryCache) FlushDB(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+	clear(c.tags)
+	return nil
+}
+
+// Info reports the backend, its size and its bound
+func (c *MemoryCache) Info(ctx context.Context) (string, error) {
+	size, err := c.DBSize(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("backend:%s\r\nkeys:%d\r\nmax_entries:%d\r\n", BackendMemory, size, c.maxEntries), nil
+}
+
+// Close drops every entry
+func (c *MemoryCache) Close() error {
+	return c.FlushDB(context.Background())
+}
+
+// live returns the entry of key unless it is missing or expired, removing it
+// when expired. The caller holds the lock.
+func (c *MemoryCache) live(key string) (*list.Element, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.expired(elem) {
+		c.remove(elem)
+		return nil, false
+	}
+	return elem, true
+}
+
+// match calls fn with each live entry whose key matches pattern, removing
+// expired entries on the way. The caller holds the lock.
+func (c *MemoryCache) match(pattern string, fn func(*list.Element)) {
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		switch {
+		case c.expired(elem):
+			c.remove(elem)
+		case globMatch(pattern, elem.Value.(*memoryEntry).key):
+			fn(elem)
+		}
+		elem = next
+	}
+}
+
+// purgeExpired removes the expired entries. The caller holds the lock.
+func (c *MemoryCache) purgeExpired() {
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if c.expired(elem) {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *MemoryCache) expired(elem *list.Element) bool {
+	return !c.now().Before(elem.Value.(*memoryEntry).expiresAt)
+}
+
+// remove drops an entry and its tag registrations. The caller holds the lock.
+func (c *MemoryCache) remove(elem *list.Element) {
+	entry := elem.Value.(*memoryEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMemoryCache returns a memory cache whose clock only moves when the
+// returned function is called
+func newTestMemoryCache(maxEntries int) (*MemoryCache, func(time.Duration)) {
+	c := NewMemoryCache(maxEntries, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryCache_SetAndGet(t *testing.T) {
+	c, _ := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "product:1", map[string]any{"name": "Phone"}, 0))
+
+	var got map[string]any
+	require.NoError(t, c.Get(ctx, "product:1", &got))
+	assert.Equal(t, "Phone", got["name"])
+
+	// Values are copies, so changing one does not change the cache
+	got["name"] = "Tablet"
+	var again map[string]any
+	require.NoError(t, c.Get(ctx, "product:1", &again))
+	assert.Equal(t, "Phone", again["name"])
+
+	assert.ErrorIs(t, c.Get(ctx, "product:2", &got), ErrCacheMiss)
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	c, advance := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "short", "a", time.Second))
+	require.NoError(t, c.Set(ctx, "default", "b", 0))
+
+	advance(time.Second)
+	var got string
+	assert.ErrorIs(t, c.Get(ctx, "short", &got), ErrCacheMiss)
+	require.NoError(t, c.Get(ctx, "default", &got))
+
+	advance(time.Minute)
+	assert.ErrorIs(t, c.Get(ctx, "default", &got), ErrCacheMiss)
+
+	size, err := c.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, size)
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestMemoryCache(2)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", 1, 0))
+	require.NoError(t, c.Set(ctx, "b", 2, 0))
+
+	// Reading a makes b the least recently used entry
+	var got int
+	require.NoError(t, c.Get(ctx, "a", &got))
+	require.NoError(t, c.Set(ctx, "c", 3, 0))
+
+	assert.ErrorIs(t, c.Get(ctx, "b", &got), ErrCacheMiss)
+	require.NoError(t, c.Get(ctx, "a", &got))
+	require.NoError(t, c.Get(ctx, "c", &got))
+}
+
+func TestMemoryCache_Patterns(t *testing.T) {
+	c, advance := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	for _, key := range []string{"product:1", "product:2", "products:list:1", "category:1"} {
+		require.NoError(t, c.Set(ctx, key, key, 0))
+	}
+	require.NoError(t, c.Set(ctx, "product:3", "expiring", time.Second))
+	advance(time.Second)
+
+	count, err := c.CountPattern(ctx, "product:*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	deleted, err := c.FlushPattern(ctx, "product*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	require.NoError(t, c.DeletePattern(ctx, "category:*"))
+	size, err := c.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, size)
+}
+
+func TestMemoryCache_DeleteAndFlush(t *testing.T) {
+	c, _ := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", 1, 0))
+	require.NoError(t, c.Set(ctx, "b", 2, 0))
+
+	require.NoError(t, c.Delete(ctx, "a"))
+	require.NoError(t, c.Delete(ctx, "missing"))
+	size, err := c.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), size)
+
+	info, err := c.Info(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, info, "backend:memory")
+	assert.Contains(t, info, "keys:1")
+
+	require.NoError(t, c.FlushDB(ctx))
+	size, err = c.DBSize(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, size)
+}
+
+func TestMemoryCache_Tags(t *testing.T) {
+	c, _ := newTestMemoryCache(2)
+	ctx := context.Background()
+
+	require.NoError(t, c.SetWithTags(ctx, "product:1", "Phone", 0, "category:5", "product-list"))
+	require.NoError(t, c.SetWithTags(ctx, "product:2", "Tablet", 0, "category:6"))
+
+	require.NoError(t, c.InvalidateTags(ctx, "category:5"))
+	var got string
+	assert.ErrorIs(t, c.Get(ctx, "product:1", &got), ErrCacheMiss)
+	require.NoError(t, c.Get(ctx, "product:2", &got))
+
+	// Evicted and overwritten entries leave their tags
+	require.NoError(t, c.SetWithTags(ctx, "product:3", "Laptop", 0, "category:7"))
+	require.NoError(t, c.SetWithTags(ctx, "product:4", "Watch", 0, "category:7"))
+	require.NoError(t, c.Set(ctx, "product:4", "Watch", 0))
+	c.mu.Lock()
+	assert.NotContains(t, c.tags, "category:6")
+	assert.NotContains(t, c.tags, "product-list")
+	assert.Equal(t, map[string]struct{}{"product:3": {}}, c.tags["category:7"])
+	c.mu.Unlock()
+
+	require.NoError(t, c.InvalidateTags(ctx, "category:7", "missing"))
+	assert.ErrorIs(t, c.Get(ctx, "product:3", &got), ErrCacheMiss)
+	require.NoError(t, c.Get(ctx, "product:4", &got))
+}
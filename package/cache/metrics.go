@@ -0,0 +1,72 @@
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
+package cache
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Tiers of the layered cache, used as the tier label of its metrics
+const (
+	TierL1 = "l1"
+	TierL2 = "l2"
+)
+
+// Results of a read through a Loader, used as the result label of its metrics
+const (
+	// LoadHit is a read answered by a fresh value
+	LoadHit = "hit"
+	// LoadNegativeHit is a read answered by a cached not found error
+	LoadNegativeHit = "negative_hit"
+	// LoadStale is a read answered by a stale value
+	LoadStale = "stale"
+	// LoadMiss is a read that loads the value
+	LoadMiss = "miss"
+)
+
+// lookups counts the reads of the layered cache by tier and outcome. A read
+// that misses L1 is counted again against L2.
+var lookups = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: "golang_rest_api_template",
+		Name:      "cache_lookups_total",
+		Help:      "How many cache reads hit or missed, partitioned by tier of the layered cache.",
+	},
+	[]string{"tier", "result"},
+)
+
+// loads counts the reads through a Loader by namespace, the part of the key
+// before the first colon, and result. A miss that ends up served stale is
+// counted as both.
+var loads = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: "golang_rest_api_template",
+		Name:      "cache_loads_total",
+		Help:      "How many reads through the cache loader were answered by a fresh value, a cached not found, a stale value or a load, partitioned by key namespace.",
+	},
+	[]string{"namespace", "result"},
+)
+
+// RegisterMetrics registers the cache metrics on reg. It is called once on
+// startup.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	if err := reg.Register(lookups); err != nil {
+		return err
+	}
+	return reg.Register(loads)
+}
+
+func recordLookup(tier string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	lookups.WithLabelValues(tier, result).Inc()
+}
+
+func recordLoad(key, result string) {
+	namespace, _, _ := strings.Cut(key, ":")
+	loads.WithLabelValues(namespace, result).Inc()
+}
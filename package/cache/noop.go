@@ -0,0 +1,77 @@
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// NoopCache is a Cache that stores nothing, so every read goes to the
+// database. It turns caching off without changing the services.
+type NoopCache struct{}
+
+// NewNoopCache returns a cache that stores nothing
+func NewNoopCache() NoopCache {
+	return NoopCache{}
+}
+
+// Get always misses
+func (NoopCache) Get(context.Context, string, any) error {
+	return ErrCacheMiss
+}
+
+// Set discards the value
+func (NoopCache) Set(context.Context, string, any, time.Duration) error {
+	return nil
+}
+
+// SetWithTags discards the value
+func (NoopCache) SetWithTags(context.Context, string, any, time.Duration, ...string) error {
+	return nil
+}
+
+// InvalidateTags has nothing to remove
+func (NoopCache) InvalidateTags(context.Context, ...string) error {
+	return nil
+}
+
+// Delete has nothing to remove
+func (NoopCache) Delete(context.Context, string) error {
+	return nil
+}
+
+// DeletePattern has nothing to remove
+func (NoopCache) DeletePattern(context.Context, string) error {
+	return nil
+}
+
+// CountPattern finds no keys
+func (NoopCache) CountPattern(context.Context, string) (int64, error) {
+	return 0, nil
+}
+
+// FlushPattern removes no keys
+func (NoopCache) FlushPattern(context.Context, string) (int64, error) {
+	return 0, nil
+}
+
+// DBSize is always 0
+func (NoopCache) DBSize(context.Context) (int64, error) {
+	return 0, nil
+}
+
+// FlushDB has nothing to remove
+func (NoopCache) FlushDB(context.Context) error {
+	return nil
+}
+
+// Info reports that caching is off
+func (NoopCache) Info(context.Context) (string, error) {
+	return "backend:" + BackendNone + "\r\n", nil
+}
+
+// Close has nothing to release
+func (NoopCache) Close() error {
+	return nil
+}
@@ -1,10 +1,11 @@
-// Package cache provides Redis caching functionality for the application.
-// It includes connection management, basic operations, and caching utilities.
+// Package cache provides caching functionality for the application.
+// It defines the Cache interface with Redis, in-memory, layered and no-op backends.
 package cache
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,61 +14,110 @@ import (
 )
 
 const (
-	DefaultTTL = 30 * time.Minute
-	MaxRetries = 3
+	DefaultTTL            = 30 * time.Minute
+	DefaultPoolSize       = 10
+	DefaultConnectTimeout = 5 * time.Second
+	MaxRetries            = 3
+
+	// scanBatchSize is the COUNT hint for SCAN when walking keys by pattern
+	scanBatchSize = 500
+	// tagPrefix prefixes the sets holding the keys registered under each tag
+	tagPrefix = "tag:"
 )
 
+// invalidateTagsScript deletes the keys registered under the tag sets in KEYS
+// and the sets themselves, in one step so that no key is registered between
+// reading a set and deleting it. It returns the keys it deleted.
+var invalidateTagsScript = redis.NewScript(`
+local deleted = {}
+for _, tag in ipairs(KEYS) do
+	local keys = redis.call("SMEMBERS", tag)
+	for i = 1, #keys, 500 do
+		redis.call("DEL", unpack(keys, i, math.min(i + 499, #keys)))
+	end
+	for _, key in ipairs(keys) do
+		table.insert(deleted, key)
+	end
+	redis.call("DEL", tag)
+end
+return deleted
+`)
+
 // RedisConfig holds Redis connection configuration
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host           string
+	Port           string
+	Password       string
+	DB             int
+	PoolSize       int
+	ConnectTimeout time.Duration
+	// DefaultTTL is used by Set when it is called without a TTL
+	DefaultTTL time.Duration
 }
 
-// Cache wraps the Redis client and provides caching operations
-type Cache struct {
-	client *redis.Client
-	logger *logger.Logger
+// RedisCache is the Cache kept in Redis, shared by every instance of the service
+type RedisCache struct {
+	client     *redis.Client
+	logger     *logger.Logger
+	defaultTTL time.Duration
 }
 
-// NewCache initializes a new Redis cache connection
-func NewCache(cfg *RedisConfig, logger *logger.Logger) (*Cache, error) {
-	client := redis.NewClient(&redis.Options{
+// NewRedisClient returns a client for the Redis server of cfg without
+// connecting to it
+func NewRedisClient(cfg *RedisConfig) *redis.Client {
+	poolSize := cfg.PoolSize
+	if poolSize == 0 {
+		poolSize = DefaultPoolSize
+	}
+	return redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
 		Password: cfg.Password,
 		DB:       cfg.DB,
-		PoolSize: 10,
+		PoolSize: poolSize,
 	})
+}
+
+// NewRedisCache initializes a new Redis cache connection
+func NewRedisCache(cfg *RedisConfig, logger *logger.Logger) (*RedisCache, error) {
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	client := NewRedisClient(cfg)
 
 	// Test the connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
 	logger.Info("Redis connection established successfully")
 
-	return &Cache{
-		client: client,
-		logger: logger,
+	return &RedisCache{
+		client:     client,
+		logger:     logger,
+		defaultTTL: cfg.DefaultTTL,
 	}, nil
 }
 
 // Close gracefully closes the Redis connection
-func (c *Cache) Close() error {
+func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
 
 // Set stores a key-value pair in Redis with optional TTL
-func (c *Cache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+func (c *RedisCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
 	if ttl == 0 {
 		ttl = DefaultTTL
 	}
@@ -82,13 +132,78 @@ func (c *Cache) Set(ctx context.Context, key string, value any, ttl time.Duratio
 	return nil
 }
 
-// Get retrieves a value from Redis by key
-func (c *Cache) Get(ctx context.Context, key string, dest any) error {
+// SetWithTags stores a key-value pair and adds key to the set of each tag.
+// A tag set lives as long as its longest-lived key.
+func (c *RedisCache) SetWithTags(ctx context.Context, key string, value any, ttl time.Duration, tags ...string) error {
+	if len(tags) == 0 {
+		return c.Set(ctx, key, value, ttl)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagPrefix+tag, key)
+			// NX gives a new set the TTL of its first key, GT extends it for
+			// longer-lived ones
+			pipe.ExpireNX(ctx, tagPrefix+tag, ttl)
+			pipe.ExpireGT(ctx, tagPrefix+tag, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("failed to set tagged cache key", "key", key, "tags", tags, "error", err)
+		return err
+	}
+
+	c.logger.Debug("cache set successful", "key", key, "ttl", ttl, "tags", tags)
+	return nil
+}
+
+// InvalidateTags deletes the keys registered under tags
+func (c *RedisCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	_, err := c.invalidateTags(ctx, tags)
+	return err
+}
+
+// invalidateTags deletes the keys registered under tags and returns them
+func (c *RedisCache) invalidateTags(ctx context.Context, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	sets := make([]string, len(tags))
+	for i, tag := range tags {
+		sets[i] = tagPrefix + tag
+	}
+	keys, err := invalidateTagsScript.Run(ctx, c.client, sets).StringSlice()
+	if err != nil {
+		c.logger.Error("failed to invalidate cache tags", "tags", tags, "error", err)
+		return nil, err
+	}
+
+	c.logger.Debug("cache tags invalidated", "tags", tags, "count", len(keys))
+	return keys, nil
+}
+
+// Get retrieves a value from Redis by key, returning ErrCacheMiss when it is not there
+func (c *RedisCache) Get(ctx context.Context, key string, dest any) error {
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err != nil {
-		if err == redis.Nil {
+		if errors.Is(err, redis.Nil) {
 			c.logger.Debug("cache miss", "key", key)
-			return redis.Nil
+			return ErrCacheMiss
 		}
 		c.logger.Error("failed to get cache key", "key", key, "error", err)
 		return err
@@ -103,7 +218,7 @@ func (c *Cache) Get(ctx context.Context, key string, dest any) error {
 }
 
 // Delete removes a key from Redis
-func (c *Cache) Delete(ctx context.Context, key string) error {
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	err := c.client.Del(ctx, key).Err()
 	if err != nil {
 		c.logger.Error("failed to delete cache key", "key", key, "error", err)
@@ -114,28 +229,80 @@ func (c *Cache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
-// DeletePattern removes all keys matching a pattern
-func (c *Cache) DeletePattern(ctx context.Context, pattern string) error {
-	keys, err := c.client.Keys(ctx, pattern).Result()
+// DeletePattern removes all keys matching a pattern. It walks the keyspace
+// with SCAN, like FlushPattern, rather than blocking the server with KEYS.
+func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
+	_, err := c.FlushPattern(ctx, pattern)
+	return err
+}
+
+// CountPattern counts the keys matching a pattern. It walks the keyspace with
+// SCAN so that large databases do not block the server.
+func (c *RedisCache) CountPattern(ctx context.Context, pattern string) (int64, error) {
+	var count int64
+	err := c.scan(ctx, pattern, func(keys []string) error {
+		count += int64(len(keys))
+		return nil
+	})
 	if err != nil {
-		c.logger.Error("failed to get keys for pattern", "pattern", pattern, "error", err)
+		c.logger.Error("failed to count keys for pattern", "pattern", pattern, "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FlushPattern removes the keys matching a pattern batch by batch and returns
+// how many were deleted
+func (c *RedisCache) FlushPattern(ctx context.Context, pattern string) (int64, error) {
+	var deleted int64
+	err := c.scan(ctx, pattern, func(keys []string) error {
+		n, err := c.client.Del(ctx, keys...).Result()
+		deleted += n
 		return err
+	})
+	if err != nil {
+		c.logger.Error("failed to flush keys for pattern", "pattern", pattern, "error", err)
+		return deleted, err
 	}
 
-	if len(keys) > 0 {
-		err = c.client.Del(ctx, keys...).Err()
+	c.logger.Info("cache keys flushed", "pattern", pattern, "count", deleted)
+	return deleted, nil
+}
+
+// DBSize returns the number of keys in the current database
+func (c *RedisCache) DBSize(ctx context.Context) (int64, error) {
+	size, err := c.client.DBSize(ctx).Result()
+	if err != nil {
+		c.logger.Error("failed to get database size", "error", err)
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// scan calls fn with each non-empty batch of keys matching pattern
+func (c *RedisCache) scan(ctx context.Context, pattern string, fn func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
 		if err != nil {
-			c.logger.Error("failed to delete keys by pattern", "pattern", pattern, "error", err)
 			return err
 		}
-		c.logger.Debug("cache delete pattern successful", "pattern", pattern, "count", len(keys))
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
 	}
-
-	return nil
 }
 
 // Exists checks if a key exists in Redis
-func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
+func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	exists, err := c.client.Exists(ctx, key).Result()
 	if err != nil {
 		c.logger.Error("failed to check key existence", "key", key, "error", err)
@@ -146,7 +313,7 @@ func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
 }
 
 // TTL gets the remaining time to live for a key
-func (c *Cache) TTL(ctx context.Context, key string) (time.Duration, error) {
+func (c *RedisCache) TTL(ctx context.Context, key string) (time.Duration, error) {
 	ttl, err := c.client.TTL(ctx, key).Result()
 	if err != nil {
 		c.logger.Error("failed to get TTL", "key", key, "error", err)
@@ -157,7 +324,7 @@ func (c *Cache) TTL(ctx context.Context, key string) (time.Duration, error) {
 }
 
 // FlushDB clears all keys in the current database
-func (c *Cache) FlushDB(ctx context.Context) error {
+func (c *RedisCache) FlushDB(ctx context.Context) error {
 	err := c.client.FlushDB(ctx).Err()
 	if err != nil {
 		c.logger.Error("failed to flush database", "error", err)
@@ -168,7 +335,18 @@ func (c *Cache) FlushDB(ctx context.Context) error {
 	return nil
 }
 
+// Info returns the Redis INFO report
+func (c *RedisCache) Info(ctx context.Context) (string, error) {
+	info, err := c.client.Info(ctx).Result()
+	if err != nil {
+		c.logger.Error("failed to get Redis info", "error", err)
+		return "", err
+	}
+
+	return info, nil
+}
+
 // GetClient returns the underlying Redis client for advanced operations
-func (c *Cache) GetClient() *redis.Client {
+func (c *RedisCache) GetClient() *redis.Client {
 	return c.client
 }
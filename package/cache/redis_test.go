@@ -4,12 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/MitulShah1/golang-rest-api-template/package/logger"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redismock/v9"
-	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,7 +40,7 @@ func TestCache_SetAndGet(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -85,7 +86,7 @@ func TestCache_Get_CacheMiss(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -99,7 +100,7 @@ func TestCache_Get_CacheMiss(t *testing.T) {
 	// Test Get with cache miss
 	var result map[string]any
 	err := cache.Get(ctx, key, &result)
-	assert.Equal(t, redis.Nil, err)
+	assert.ErrorIs(t, err, ErrCacheMiss)
 
 	// Verify all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -110,7 +111,7 @@ func TestCache_Delete(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -162,7 +163,7 @@ func TestCache_DeletePattern(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -171,8 +172,8 @@ func TestCache_DeletePattern(t *testing.T) {
 	pattern := "test_pattern_*"
 	keys := []string{"test_pattern_1", "test_pattern_2", "test_pattern_3"}
 
-	// Set up mock expectations for Keys
-	mock.ExpectKeys(pattern).SetVal(keys)
+	// Set up mock expectations for Scan
+	mock.ExpectScan(0, pattern, scanBatchSize).SetVal(keys, 0)
 
 	// Set up mock expectations for Del
 	mock.ExpectDel(keys...).SetVal(int64(len(keys)))
@@ -190,7 +191,7 @@ func TestCache_DeletePattern_NoKeys(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -198,8 +199,8 @@ func TestCache_DeletePattern_NoKeys(t *testing.T) {
 	ctx := context.Background()
 	pattern := "test_pattern_*"
 
-	// Set up mock expectations for Keys (no keys found)
-	mock.ExpectKeys(pattern).SetVal([]string{})
+	// Set up mock expectations for Scan (no keys found)
+	mock.ExpectScan(0, pattern, scanBatchSize).SetVal([]string{}, 0)
 
 	// Test DeletePattern with no matching keys
 	err := cache.DeletePattern(ctx, pattern)
@@ -214,7 +215,7 @@ func TestCache_TTL(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -240,7 +241,7 @@ func TestCache_FlushDB(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -263,7 +264,7 @@ func TestCache_Set_WithCustomTTL(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -293,7 +294,7 @@ func TestCache_Set_WithZeroTTL(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -322,7 +323,7 @@ func TestCache_Set_Error(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -353,7 +354,7 @@ func TestCache_Get_Error(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -380,7 +381,7 @@ func TestCache_Delete_Error(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -412,7 +413,7 @@ func TestCache_GetClient(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 
 	// Create cache with mock client
-	cache := &Cache{
+	cache := &RedisCache{
 		client: db,
 		logger: logger.NewLogger(logger.DefaultOptions()),
 	}
@@ -424,3 +425,85 @@ func TestCache_GetClient(t *testing.T) {
 	// Verify no expectations were set (this is just a getter)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCache_CountPattern(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := &RedisCache{
+		client: db,
+		logger: logger.NewLogger(logger.DefaultOptions()),
+	}
+
+	ctx := context.Background()
+	pattern := "product:*"
+
+	// SCAN continues until the cursor returns to zero
+	mock.ExpectScan(0, pattern, scanBatchSize).SetVal([]string{"product:1", "product:2"}, 7)
+	mock.ExpectScan(7, pattern, scanBatchSize).SetVal([]string{"product:3"}, 0)
+
+	count, err := cache.CountPattern(ctx, pattern)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCache_FlushPattern(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := &RedisCache{
+		client: db,
+		logger: logger.NewLogger(logger.DefaultOptions()),
+	}
+
+	ctx := context.Background()
+	pattern := "product:*"
+
+	mock.ExpectScan(0, pattern, scanBatchSize).SetVal([]string{"product:1", "product:2"}, 7)
+	mock.ExpectDel("product:1", "product:2").SetVal(2)
+	mock.ExpectScan(7, pattern, scanBatchSize).SetVal([]string{}, 0)
+
+	deleted, err := cache.FlushPattern(ctx, pattern)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCache_FlushPattern_Error(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	cache := &RedisCache{
+		client: db,
+		logger: logger.NewLogger(logger.DefaultOptions()),
+	}
+
+	mock.ExpectScan(0, "product:*", scanBatchSize).SetErr(errors.New("connection refused"))
+
+	_, err := cache.FlushPattern(context.Background(), "product:*")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedisCache_Tags(t *testing.T) {
+	mr := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(mr.Addr())
+	require.NoError(t, err)
+	c, err := NewRedisCache(&RedisConfig{Host: host, Port: port}, logger.NewLogger(logger.DefaultOptions()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+
+	require.NoError(t, c.SetWithTags(ctx, "product:1", "Phone", time.Minute, "category:5", "product-list"))
+	require.NoError(t, c.SetWithTags(ctx, "product:list:a", "page", 5*time.Minute, "product-list"))
+	require.NoError(t, c.SetWithTags(ctx, "product:2", "Tablet", time.Minute, "category:6"))
+
+	// A tag set lives as long as its longest-lived key
+	assert.Equal(t, time.Minute, mr.TTL("tag:category:5"))
+	assert.Equal(t, 5*time.Minute, mr.TTL("tag:product-list"))
+
+	require.NoError(t, c.InvalidateTags(ctx, "product-list", "missing"))
+	assert.False(t, mr.Exists("product:1"))
+	assert.False(t, mr.Exists("product:list:a"))
+	assert.False(t, mr.Exists("tag:product-list"))
+	assert.True(t, mr.Exists("product:2"))
+
+	// Without tags it is a plain Set
+	require.NoError(t, c.SetWithTags(ctx, "product:3", "Laptop", 0))
+	assert.Equal(t, DefaultTTL, mr.TTL("product:3"))
+}
@@ -3,22 +3,41 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
+// Supported drivers, named as registered with database/sql
 const (
-	DriverName = "mysql"
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+const (
+	DriverName = DriverMySQL
 	MaxDBConn  = 10
 	MaxDBIdle  = 5
 	DBTimeout  = 5 * time.Second
+
+	// sqliteMemory is the SQLite file name of an in-memory database
+	sqliteMemory = ":memory:"
 )
 
+var ErrUnsupportedDriver = errors.New("unsupported database driver")
+
 // DBConfig holds the database configuration
 type DBConfig struct {
 	Driver             string
@@ -31,29 +50,52 @@ type DBConfig struct {
 	MaxConn            int
 	MaxIdle            int
 	ConnectionTimeeout time.Duration
+	// MultiStatements allows several statements in one Exec on MySQL, as
+	// migration scripts need. PostgreSQL and SQLite always allow them.
+	MultiStatements bool
+	// Replicas are the DSNs of read replicas, each with its own credentials;
+	// see ReplicaDSN
+	Replicas []string
+	// ReplicaCheckInterval is how often replicas are health checked
+	ReplicaCheckInterval time.Duration
+	// ReplicaMaxLag is the replication lag above which a replica is taken out
+	// of rotation; zero turns the lag check off
+	ReplicaMaxLag time.Duration
 }
 
-// Database wraps the sqlx.DB instance
+// Database wraps the sqlx.DB instance of the primary and the read replicas
 type Database struct {
 	DB *sqlx.DB
+
+	replicas []*replica
+	// next is the round-robin position among the replicas
+	next atomic.Uint64
+	// stopMonitor ends the replica health checks
+	stopMonitor context.CancelFunc
 }
 
 // NewDatabase initializes a new database connection
 func NewDatabase(dbCnfg *DBConfig) (*Database, error) {
 	cfg := DBConfig{
+		Driver:   NormalizeDriver(dbCnfg.Driver),
 		Host:     dbCnfg.Host,
 		Port:     dbCnfg.Port,
 		User:     dbCnfg.User,
 		Password: dbCnfg.Password,
 		DBName:   dbCnfg.DBName,
 		SSLMode:  dbCnfg.SSLMode,
+
+		MultiStatements: dbCnfg.MultiStatements,
 	}
 
 	if cfg.Driver == "" {
 		cfg.Driver = DriverName
 	}
 
-	dsn := getDSN(&cfg)
+	dsn, err := getDSN(&cfg)
+	if err != nil {
+		return nil, err
+	}
 	db, err := sqlx.Connect(cfg.Driver, dsn)
 	if err != nil {
 		return nil, err
@@ -70,31 +112,112 @@ func NewDatabase(dbCnfg *DBConfig) (*Database, error) {
 		dbCnfg.ConnectionTimeeout = DBTimeout
 	}
 
+	// Every connection to ":memory:" opens its own empty database, so keep one
+	// connection alive for the lifetime of the pool
+	if cfg.Driver == DriverSQLite && cfg.DBName == sqliteMemory {
+		dbCnfg.MaxConn, dbCnfg.MaxIdle, dbCnfg.ConnectionTimeeout = 1, 1, 0
+	}
+
 	db.SetMaxOpenConns(dbCnfg.MaxConn)
 	db.SetMaxIdleConns(dbCnfg.MaxIdle)
 	db.SetConnMaxLifetime(dbCnfg.ConnectionTimeeout)
 
-	return &Database{DB: db}, nil
+	d := &Database{DB: db}
+	if len(dbCnfg.Replicas) == 0 {
+		return d, nil
+	}
+	if cfg.Driver == DriverSQLite {
+		d.Close()
+		return nil, fmt.Errorf("%w: read replicas with %s", ErrUnsupportedDriver, cfg.Driver)
+	}
+
+	for i, dsn := range dbCnfg.Replicas {
+		r, err := openReplica(cfg.Driver, dsn, dbCnfg.ReplicaMaxLag)
+		if err != nil {
+			d.Close()
+			return nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+		r.db.SetMaxOpenConns(dbCnfg.MaxConn)
+		r.db.SetMaxIdleConns(dbCnfg.MaxIdle)
+		r.db.SetConnMaxLifetime(dbCnfg.ConnectionTimeeout)
+		d.replicas = append(d.replicas, r)
+	}
+
+	interval := dbCnfg.ReplicaCheckInterval
+	if interval <= 0 {
+		interval = DefaultReplicaCheckInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopMonitor = cancel
+	go d.monitorReplicas(ctx, interval)
+
+	return d, nil
+}
+
+// NormalizeDriver maps accepted driver aliases to the supported driver names
+func NormalizeDriver(driver string) string {
+	switch strings.ToLower(driver) {
+	case "postgresql", "pgx":
+		return DriverPostgres
+	case "sqlite3":
+		return DriverSQLite
+	default:
+		return strings.ToLower(driver)
+	}
+}
+
+// Driver returns the name of the driver the database was opened with
+func (d *Database) Driver() string {
+	return d.DB.DriverName()
+}
+
+// Builder returns a squirrel statement builder using the placeholder format
+// of the driver: $1, $2... for PostgreSQL and ? otherwise
+func (d *Database) Builder() squirrel.StatementBuilderType {
+	if d.Driver() == DriverPostgres {
+		return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	}
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question)
 }
 
-// Close gracefully shuts down the database connection
+// Close gracefully shuts down the primary and replica connections
 func (d *Database) Close() {
+	if d.stopMonitor != nil {
+		d.stopMonitor()
+	}
+	for _, r := range d.replicas {
+		_ = r.db.Close()
+	}
 	_ = d.DB.Close()
 }
 
 // getDSN builds the Data Source Name based on the driver
-func getDSN(cfg *DBConfig) string {
-	switch cfg.Driver {
-	case "postgres":
-		return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=%s",
-			cfg.User, cfg.Password, net.JoinHostPort(cfg.Host, cfg.Port), cfg.DBName, cfg.SSLMode)
-	case "mysql":
-		return fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true",
+func getDSN(cfg *DBConfig) (string, error) {
+	switch NormalizeDriver(cfg.Driver) {
+	case DriverPostgres:
+		sslMode := cfg.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.User, cfg.Password),
+			Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+			Path:     cfg.DBName,
+			RawQuery: "sslmode=" + url.QueryEscape(sslMode),
+		}
+		return dsn.String(), nil
+	case DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true",
 			cfg.User, cfg.Password, net.JoinHostPort(cfg.Host, cfg.Port), cfg.DBName)
-	case "sqlite3":
-		return cfg.DBName // SQLite uses a simple file path as DSN
+		if cfg.MultiStatements {
+			dsn += "&multiStatements=true"
+		}
+		return dsn, nil
+	case DriverSQLite:
+		// SQLite uses a file path; foreign keys are off unless enabled per connection
+		return cfg.DBName + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", nil
 	default:
-		log.Fatalf("Unsupported database driver: %s", cfg.Driver)
-		return ""
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedDriver, cfg.Driver)
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/MitulShah1/golang-rest-api-template/package/database/mocks"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -75,6 +76,19 @@ func TestGetDSN(t *testing.T) {
 			},
 			expected: "root:password@tcp(localhost:3306)/testdb?parseTime=true",
 		},
+		{
+			name: "MySQL DSN with multiple statements",
+			config: DBConfig{
+				Driver:          "mysql",
+				Host:            "localhost",
+				Port:            "3306",
+				User:            "root",
+				Password:        "password",
+				DBName:          "testdb",
+				MultiStatements: true,
+			},
+			expected: "root:password@tcp(localhost:3306)/testdb?parseTime=true&multiStatements=true",
+		},
 		{
 			name: "PostgreSQL DSN",
 			config: DBConfig{
@@ -94,14 +108,61 @@ func TestGetDSN(t *testing.T) {
 				Driver: "sqlite3",
 				DBName: "test.db",
 			},
-			expected: "test.db",
+			expected: "test.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+		},
+		{
+			name: "PostgreSQL DSN escapes credentials",
+			config: DBConfig{
+				Driver:   "postgres",
+				Host:     "db",
+				Port:     "5432",
+				User:     "app",
+				Password: "p@ss/word",
+				DBName:   "shop",
+			},
+			expected: "postgres://app:p%40ss%2Fword@db:5432/shop?sslmode=disable",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dsn := getDSN(&tt.config)
+			dsn, err := getDSN(&tt.config)
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expected, dsn)
 		})
 	}
+
+	_, err := getDSN(&DBConfig{Driver: "oracle"})
+	assert.ErrorIs(t, err, ErrUnsupportedDriver)
+}
+
+// TestNewDatabase_SQLite opens an in-memory SQLite database with foreign keys enabled
+func TestNewDatabase_SQLite(t *testing.T) {
+	db, err := NewDatabase(&DBConfig{Driver: "sqlite3", DBName: ":memory:"})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	assert.Equal(t, DriverSQLite, db.Driver())
+
+	var enabled int
+	assert.NoError(t, db.DB.Get(&enabled, "PRAGMA foreign_keys"))
+	assert.Equal(t, 1, enabled)
+}
+
+// TestDatabase_Builder checks the placeholder format used for each driver
+func TestDatabase_Builder(t *testing.T) {
+	for driver, expected := range map[string]string{
+		DriverMySQL:    "SELECT * FROM products WHERE id = ?",
+		DriverPostgres: "SELECT * FROM products WHERE id = $1",
+		DriverSQLite:   "SELECT * FROM products WHERE id = ?",
+	} {
+		mockDB, _, err := mocks.NewMockDB()
+		assert.NoError(t, err)
+		db := &Database{DB: sqlx.NewDb(mockDB.DB, driver)}
+
+		query, _, err := db.Builder().Select("*").From("products").Where("id = ?", 1).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, query, driver)
+		mockDB.Close()
+	}
 }
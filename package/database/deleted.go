@@ -0,0 +1,16 @@
+package database
+
+import "context"
+
+type deletedKey struct{}
+
+// WithDeleted returns a context whose reads include soft-deleted rows
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deletedKey{}, true)
+}
+
+// IncludeDeleted reports whether reads made with ctx include soft-deleted rows
+func IncludeDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(deletedKey{}).(bool)
+	return include
+}
@@ -0,0 +1,455 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// MigrationsTable records the applied schema version. It has the layout
+	// golang-migrate uses, so the migrate CLI and the server can be mixed.
+	MigrationsTable = "schema_migrations"
+
+	// DefaultMigrateLockTimeout is how long a migration waits for another
+	// instance holding the migration lock
+	DefaultMigrateLockTimeout = time.Minute
+
+	// migrationLockName names the MySQL and PostgreSQL advisory lock
+	migrationLockName = "schema_migrations"
+	// lockRetryInterval is how often a PostgreSQL advisory lock is retried
+	lockRetryInterval = 250 * time.Millisecond
+)
+
+var (
+	ErrMigrationLocked  = errors.New("another instance is migrating the database")
+	ErrDirtyDatabase    = errors.New("database is dirty")
+	ErrSchemaAhead      = errors.New("database schema is newer than this binary")
+	ErrUnknownMigration = errors.New("unknown migration version")
+)
+
+// migrationFile matches golang-migrate file names such as 000001_init.up.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is one numbered schema change with its up and down scripts
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String formats the migration as its file name prefix, such as 000001_init
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus describes the schema version of a database
+type MigrationStatus struct {
+	// Version is the last applied migration, 0 when none is applied
+	Version uint64
+	// Dirty is set when a migration failed part way through
+	Dirty bool
+	// Latest is the last migration known to this binary
+	Latest uint64
+	// Pending lists the migrations not applied yet
+	Pending []Migration
+}
+
+// Ahead reports whether the database was migrated by a newer binary
+func (s MigrationStatus) Ahead() bool {
+	return s.Version > s.Latest
+}
+
+// Migrator applies the migrations of the database driver. Every change runs
+// on one connection holding an advisory lock, so instances starting together
+// migrate one after the other. SQLite has no advisory locks and relies on a
+// single process owning the database file.
+type Migrator struct {
+	db         *Database
+	migrations []Migration
+
+	// LockTimeout bounds the wait for the migration lock
+	LockTimeout time.Duration
+	// OnStep, when set, is called after each migration is applied or rolled back
+	OnStep func(m Migration, up bool)
+}
+
+// NewMigrator creates a migrator for db. source holds one directory of
+// golang-migrate files per driver, as migrations.FS does.
+func NewMigrator(db *Database, source fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(source, db.Driver())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, LockTimeout: DefaultMigrateLockTimeout}, nil
+}
+
+// Migrations returns the known migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status reads the schema version of the database
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	var status MigrationStatus
+	err := m.run(ctx, false, func(conn *sqlx.Conn) error {
+		var err error
+		status, err = m.status(ctx, conn)
+		return err
+	})
+	return status, err
+}
+
+// CheckVersion returns the schema status, failing with ErrSchemaAhead when
+// the database was migrated past the migrations of this binary and with
+// ErrDirtyDatabase when a migration failed part way through
+func (m *Migrator) CheckVersion(ctx context.Context) (MigrationStatus, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return status, err
+	}
+	if status.Dirty {
+		return status, fmt.Errorf("%w at version %d", ErrDirtyDatabase, status.Version)
+	}
+	if status.Ahead() {
+		return status, fmt.Errorf("%w: database at version %d, latest known %d", ErrSchemaAhead, status.Version, status.Latest)
+	}
+	return status, nil
+}
+
+// Up applies every pending migration and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.Goto(ctx, m.latest())
+}
+
+// Down rolls back the last steps migrations and returns how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	applied := 0
+	err := m.run(ctx, true, func(conn *sqlx.Conn) error {
+		status, err := m.cleanStatus(ctx, conn)
+		if err != nil {
+			return err
+		}
+		idx := m.index(status.Version)
+		if idx < 0 && status.Version > 0 {
+			return fmt.Errorf("%w: database at version %d", ErrUnknownMigration, status.Version)
+		}
+		for ; idx >= 0 && applied < steps; idx-- {
+			if err := m.down(ctx, conn, idx); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Goto migrates up or down to version, 0 rolling back every migration. It
+// returns how many migrations were applied or rolled back.
+func (m *Migrator) Goto(ctx context.Context, version uint64) (int, error) {
+	if version > 0 && m.index(version) < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownMigration, version)
+	}
+
+	applied := 0
+	err := m.run(ctx, true, func(conn *sqlx.Conn) error {
+		status, err := m.cleanStatus(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if status.Ahead() {
+			return fmt.Errorf("%w: database at version %d, latest known %d", ErrSchemaAhead, status.Version, status.Latest)
+		}
+		current := m.index(status.Version)
+		if current < 0 && status.Version > 0 {
+			return fmt.Errorf("%w: database at version %d", ErrUnknownMigration, status.Version)
+		}
+
+		target := m.index(version)
+		for i := current + 1; i <= target; i++ {
+			if err := m.up(ctx, conn, i); err != nil {
+				return err
+			}
+			applied++
+		}
+		for i := current; i > target; i-- {
+			if err := m.down(ctx, conn, i); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Force records version as applied and clean without running any script. It
+// is used to recover from a dirty database after fixing it by hand; 0 clears
+// the version.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	return m.run(ctx, true, func(conn *sqlx.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+// run calls fn with a dedicated connection on which the migrations table
+// exists, holding the migration lock when locked is set
+func (m *Migrator) run(ctx context.Context, locked bool, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.DB.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if locked {
+		unlock, err := m.lock(ctx, conn)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	if _, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+MigrationsTable+
+		" (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"); err != nil {
+		return fmt.Errorf("creating %s: %w", MigrationsTable, err)
+	}
+	return fn(conn)
+}
+
+// lock takes the advisory lock on conn and returns the function releasing it
+func (m *Migrator) lock(ctx context.Context, conn *sqlx.Conn) (func(), error) {
+	timeout := m.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultMigrateLockTimeout
+	}
+
+	switch m.db.Driver() {
+	case DriverMySQL:
+		var got sql.NullInt64
+		seconds := int(timeout.Round(time.Second) / time.Second)
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, seconds).Scan(&got); err != nil {
+			return nil, fmt.Errorf("taking migration lock: %w", err)
+		}
+		if got.Int64 != 1 {
+			return nil, ErrMigrationLocked
+		}
+		return func() {
+			_, _ = conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", migrationLockName)
+		}, nil
+	case DriverPostgres:
+		key := int64(crc32.ChecksumIEEE([]byte(migrationLockName)))
+		deadline := time.Now().Add(timeout)
+		for {
+			var got bool
+			if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&got); err != nil {
+				return nil, fmt.Errorf("taking migration lock: %w", err)
+			}
+			if got {
+				break
+			}
+			if time.Now().After(deadline) {
+				return nil, ErrMigrationLocked
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(lockRetryInterval):
+			}
+		}
+		return func() {
+			_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		}, nil
+	default:
+		return func() {}, nil
+	}
+}
+
+// status reads the recorded version on conn
+func (m *Migrator) status(ctx context.Context, conn *sqlx.Conn) (MigrationStatus, error) {
+	status := MigrationStatus{Latest: m.latest()}
+
+	query, args, err := m.db.Builder().Select("version", "dirty").From(MigrationsTable).Limit(1).ToSql()
+	if err != nil {
+		return status, err
+	}
+	var version int64
+	err = conn.QueryRowContext(ctx, query, args...).Scan(&version, &status.Dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return status, fmt.Errorf("reading schema version: %w", err)
+	}
+	status.Version = uint64(version)
+
+	for _, mig := range m.migrations {
+		if mig.Version > status.Version {
+			status.Pending = append(status.Pending, mig)
+		}
+	}
+	return status, nil
+}
+
+// cleanStatus reads the recorded version, failing when it is dirty
+func (m *Migrator) cleanStatus(ctx context.Context, conn *sqlx.Conn) (MigrationStatus, error) {
+	status, err := m.status(ctx, conn)
+	if err != nil {
+		return status, err
+	}
+	if status.Dirty {
+		return status, fmt.Errorf("%w at version %d: fix the schema, then force a version", ErrDirtyDatabase, status.Version)
+	}
+	return status, nil
+}
+
+// setVersion replaces the recorded version; 0 records none
+func (m *Migrator) setVersion(ctx context.Context, conn *sqlx.Conn, version uint64, dirty bool) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := m.db.Builder().Delete(MigrationsTable).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("recording schema version: %w", err)
+	}
+	if version > 0 {
+		query, args, err = m.db.Builder().Insert(MigrationsTable).
+			Columns("version", "dirty").
+			Values(int64(version), dirty).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("recording schema version: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// up applies the migration at index i. The version is marked dirty while the
+// script runs, so a failure part way through is visible.
+func (m *Migrator) up(ctx context.Context, conn *sqlx.Conn, i int) error {
+	mig := m.migrations[i]
+	if err := m.setVersion(ctx, conn, mig.Version, true); err != nil {
+		return err
+	}
+	if err := execScript(ctx, conn, mig.Up); err != nil {
+		return fmt.Errorf("migration %s up: %w", mig, err)
+	}
+	if err := m.setVersion(ctx, conn, mig.Version, false); err != nil {
+		return err
+	}
+	if m.OnStep != nil {
+		m.OnStep(mig, true)
+	}
+	return nil
+}
+
+// down rolls back the migration at index i
+func (m *Migrator) down(ctx context.Context, conn *sqlx.Conn, i int) error {
+	mig := m.migrations[i]
+	if err := m.setVersion(ctx, conn, mig.Version, true); err != nil {
+		return err
+	}
+	if err := execScript(ctx, conn, mig.Down); err != nil {
+		return fmt.Errorf("migration %s down: %w", mig, err)
+	}
+	var previous uint64
+	if i > 0 {
+		previous = m.migrations[i-1].Version
+	}
+	if err := m.setVersion(ctx, conn, previous, false); err != nil {
+		return err
+	}
+	if m.OnStep != nil {
+		m.OnStep(mig, false)
+	}
+	return nil
+}
+
+// index returns the position of version in the migrations, or -1
+func (m *Migrator) index(version uint64) int {
+	for i, mig := range m.migrations {
+		if mig.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// latest returns the highest known version, 0 when there are no migrations
+func (m *Migrator) latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// execScript runs a migration script, skipping empty ones
+func execScript(ctx context.Context, conn *sqlx.Conn, script string) error {
+	if strings.TrimSpace(script) == "" {
+		return nil
+	}
+	_, err := conn.ExecContext(ctx, script)
+	return err
+}
+
+// loadMigrations reads the migrations in the driver directory of source
+func loadMigrations(source fs.FS, driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, driver)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s migrations: %w", driver, err)
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s: invalid version", entry.Name())
+		}
+		script, err := fs.ReadFile(source, path.Join(driver, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		} else if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, mig.Name, match[2])
+		}
+		if match[3] == "up" {
+			mig.Up = string(script)
+		} else {
+			mig.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/database/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteMemory(t *testing.T) *Database {
+	t.Helper()
+	db, err := NewDatabase(&DBConfig{Driver: DriverSQLite, DBName: sqliteMemory})
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+	return db
+}
+
+// testMigrations is a small migration set for the sqlite driver
+var testMigrations = fstest.MapFS{
+	"sqlite/000001_widgets.up.sql":   {Data: []byte("CREATE TABLE widgets (id INTEGER PRIMARY KEY);")},
+	"sqlite/000001_widgets.down.sql": {Data: []byte("DROP TABLE widgets;")},
+	"sqlite/000002_gadgets.up.sql":   {Data: []byte("CREATE TABLE gadgets (id INTEGER PRIMARY KEY);")},
+	"sqlite/000002_gadgets.down.sql": {Data: []byte("DROP TABLE gadgets;")},
+	"sqlite/000003_broken.up.sql":    {Data: []byte("CREATE TABLE broken (;")},
+	"sqlite/000003_broken.down.sql":  {Data: []byte("")},
+	"sqlite/README.md":               {Data: []byte("not a migration")},
+}
+
+func tableExists(t *testing.T, db *Database, name string) bool {
+	t.Helper()
+	var count int
+	require.NoError(t, db.DB.Get(&count, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name))
+	return count == 1
+}
+
+func TestMigrator_Embedded(t *testing.T) {
+	db := newSQLiteMemory(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.Migrations())
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, status.Version)
+	assert.Len(t, status.Pending, len(m.Migrations()))
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(m.Migrations()), applied)
+	assert.True(t, tableExists(t, db, "products"))
+
+	status, err = m.CheckVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, status.Latest, status.Version)
+	assert.Empty(t, status.Pending)
+
+	// Every down script undoes its up script
+	applied, err = m.Goto(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, len(m.Migrations()), applied)
+	assert.False(t, tableExists(t, db, "products"))
+}
+
+func TestMigrator_GotoAndDown(t *testing.T) {
+	db := newSQLiteMemory(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+	require.Len(t, m.Migrations(), 3)
+	assert.Equal(t, "000002_gadgets", m.Migrations()[1].String())
+
+	var steps []string
+	m.OnStep = func(mig Migration, up bool) {
+		steps = append(steps, mig.Name)
+	}
+
+	applied, err := m.Goto(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	assert.Equal(t, []string{"widgets", "gadgets"}, steps)
+	assert.True(t, tableExists(t, db, "gadgets"))
+
+	applied, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	assert.False(t, tableExists(t, db, "gadgets"))
+	assert.True(t, tableExists(t, db, "widgets"))
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), status.Version)
+	assert.Len(t, status.Pending, 2)
+
+	_, err = m.Goto(ctx, 7)
+	assert.ErrorIs(t, err, ErrUnknownMigration)
+}
+
+func TestMigrator_DirtyAndForce(t *testing.T) {
+	db := newSQLiteMemory(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	applied, err := m.Up(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "000003_broken up")
+	assert.Equal(t, 2, applied)
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), status.Version)
+	assert.True(t, status.Dirty)
+
+	_, err = m.CheckVersion(ctx)
+	assert.ErrorIs(t, err, ErrDirtyDatabase)
+	_, err = m.Down(ctx, 1)
+	assert.ErrorIs(t, err, ErrDirtyDatabase)
+
+	require.NoError(t, m.Force(ctx, 2))
+	status, err = m.CheckVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), status.Version)
+	assert.False(t, status.Dirty)
+}
+
+func TestMigrator_SchemaAhead(t *testing.T) {
+	db := newSQLiteMemory(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+	require.NoError(t, m.Force(ctx, 42))
+
+	status, err := m.CheckVersion(ctx)
+	assert.ErrorIs(t, err, ErrSchemaAhead)
+	assert.True(t, status.Ahead())
+
+	_, err = m.Up(ctx)
+	assert.ErrorIs(t, err, ErrSchemaAhead)
+}
+
+func TestLoadMigrations_Errors(t *testing.T) {
+	_, err := loadMigrations(fstest.MapFS{}, DriverSQLite)
+	assert.Error(t, err)
+
+	_, err = loadMigrations(fstest.MapFS{
+		"sqlite/000001_a.up.sql":   {Data: []byte("")},
+		"sqlite/000001_b.down.sql": {Data: []byte("")},
+	}, DriverSQLite)
+	assert.ErrorContains(t, err, "conflicting names")
+}
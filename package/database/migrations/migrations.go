@@ -0,0 +1,11 @@
+// Package migrations holds the SQL migrations of every supported database
+// driver, one directory per driver named after it: mysql, postgres and sqlite.
+// Each directory uses the golang-migrate file layout.
+package migrations
+
+import "embed"
+
+// FS contains the migration files of every driver
+//
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id            INT AUTO_INCREMENT PRIMARY KEY,
+    prefix        VARCHAR(32) NOT NULL,
+    salt          CHAR(32) NOT NULL,
+    key_hash      CHAR(64) NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+    user_id       INT NOT NULL,
+    scopes        VARCHAR(1024) NOT NULL DEFAULT '',
+    expires_at    TIMESTAMP NULL DEFAULT NULL,
+    last_used_at  TIMESTAMP NULL DEFAULT NULL,
+    revoked_at    TIMESTAMP NULL DEFAULT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_api_keys_prefix (prefix),
+    INDEX idx_api_keys_user (user_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE categories DROP FOREIGN KEY fk_categories_parent;
+ALTER TABLE products DROP FOREIGN KEY fk_products_category;
+
+DROP INDEX idx_categories_parent_id ON categories;
+DROP INDEX idx_products_category_id ON products;
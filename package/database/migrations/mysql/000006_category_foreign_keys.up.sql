@@ -0,0 +1,13 @@
+-- Rows written before this migration are not checked, so existing orphans do
+-- not block it; every insert, update and delete from now on is.
+SET FOREIGN_KEY_CHECKS = 0;
+
+CREATE INDEX idx_products_category_id ON products (category_id);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+
+ALTER TABLE products
+    ADD CONSTRAINT fk_products_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE RESTRICT;
+ALTER TABLE categories
+    ADD CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories (id) ON DELETE RESTRICT;
+
+SET FOREIGN_KEY_CHECKS = 1;
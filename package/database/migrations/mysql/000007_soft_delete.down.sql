@@ -0,0 +1,9 @@
+-- Soft-deleted rows would become visible again, so they are removed first
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_categories_deleted_at ON categories;
+DROP INDEX idx_products_deleted_at ON products;
+
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE products DROP COLUMN deleted_at;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS ft_products_name_description;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS ft_products_name_description ON products
+    USING GIN (to_tsvector('english', name || ' ' || COALESCE(description, '')));
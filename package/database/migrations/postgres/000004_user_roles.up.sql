@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id       INT NOT NULL,
+    role          VARCHAR(64) NOT NULL,
+    created_at    TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role)
+);
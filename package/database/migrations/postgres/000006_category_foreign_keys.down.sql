@@ -0,0 +1,5 @@
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS fk_categories_parent;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_category;
+
+DROP INDEX IF EXISTS idx_categories_parent_id;
+DROP INDEX IF EXISTS idx_products_category_id;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS products (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    name          VARCHAR(255) NOT NULL,
+    description   TEXT,
+    price         DECIMAL(10,2) NOT NULL,
+    stock         INT DEFAULT 0,
+    category_id   INT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    name          VARCHAR(255) NOT NULL,
+    parent_id     INT,
+    description   TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS products_set_updated_at AFTER UPDATE ON products
+    FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE products SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS categories_set_updated_at AFTER UPDATE ON categories
+    FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE categories SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
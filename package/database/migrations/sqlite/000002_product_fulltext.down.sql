@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_products_name;
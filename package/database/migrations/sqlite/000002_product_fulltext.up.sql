@@ -0,0 +1,2 @@
+-- SQLite has no FULLTEXT index; product search uses LIKE matching on this driver.
+CREATE INDEX IF NOT EXISTS idx_products_name ON products (name);
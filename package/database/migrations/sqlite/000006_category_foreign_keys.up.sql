@@ -0,0 +1,50 @@
+-- SQLite cannot add a foreign key to an existing table, so both tables are
+-- rebuilt. Checks are off while copying, so existing orphans do not block the
+-- migration; every insert, update and delete from now on is checked.
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE categories_new (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    name          VARCHAR(255) NOT NULL,
+    parent_id     INT REFERENCES categories (id) ON DELETE RESTRICT,
+    description   TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO categories_new (id, name, parent_id, description, created_at, updated_at)
+    SELECT id, name, parent_id, description, created_at, updated_at FROM categories;
+DROP TABLE categories;
+ALTER TABLE categories_new RENAME TO categories;
+
+CREATE TABLE products_new (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    name          VARCHAR(255) NOT NULL,
+    description   TEXT,
+    price         DECIMAL(10,2) NOT NULL,
+    stock         INT DEFAULT 0,
+    category_id   INT REFERENCES categories (id) ON DELETE RESTRICT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO products_new (id, name, description, price, stock, category_id, created_at, updated_at)
+    SELECT id, name, description, price, stock, category_id, created_at, updated_at FROM products;
+DROP TABLE products;
+ALTER TABLE products_new RENAME TO products;
+
+CREATE INDEX IF NOT EXISTS idx_products_name ON products (name);
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+CREATE TRIGGER IF NOT EXISTS products_set_updated_at AFTER UPDATE ON products
+    FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE products SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS categories_set_updated_at AFTER UPDATE ON categories
+    FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE categories SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+PRAGMA foreign_keys = ON;
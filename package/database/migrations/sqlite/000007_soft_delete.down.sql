@@ -0,0 +1,9 @@
+-- Soft-deleted rows would become visible again, so they are removed first
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+DROP INDEX IF EXISTS idx_products_deleted_at;
+
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE products DROP COLUMN deleted_at;
@@ -8,12 +8,13 @@ import (
 )
 
 type Logger struct {
-	log *zap.Logger
+	log   *zap.Logger
+	level zap.AtomicLevel
 }
 
 // NewLogger initializes the logger with given options
 func NewLogger(opts LoggerOptions) *Logger {
-	logger := &Logger{}
+	logger := &Logger{level: zap.NewAtomicLevel()}
 
 	if opts.NoOp {
 		logger.log = zap.NewNop() // NoOp logger when logging is disabled
@@ -29,10 +30,9 @@ func NewLogger(opts LoggerOptions) *Logger {
 
 	// Configure logging level
 	if opts.Debug {
-		cfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
-	} else {
-		cfg.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		logger.level.SetLevel(zapcore.DebugLevel)
 	}
+	cfg.Level = logger.level
 
 	// Enable caller and stack trace if configured
 	cfg.EncoderConfig.TimeKey = "timestamp"
@@ -61,6 +61,22 @@ func NewLogger(opts LoggerOptions) *Logger {
 	return logger
 }
 
+// SetLevel changes the minimum level of written entries while the logger is
+// in use. level is one of debug, info, warn or error.
+func (lg Logger) SetLevel(level string) error {
+	l, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	lg.level.SetLevel(l)
+	return nil
+}
+
+// Level returns the current minimum level
+func (lg Logger) Level() string {
+	return lg.level.String()
+}
+
 // Info logs an informational message with key-value pairs
 func (lg Logger) Info(msg string, keysAndValues ...any) {
 	lg.log.Sugar().Infow(msg, keysAndValues...)
@@ -77,3 +77,18 @@ func TestNewLogger(t *testing.T) {
 		})
 	}
 }
+
+func TestLogger_SetLevel(t *testing.T) {
+	logger := NewLogger(LoggerOptions{JSON: true})
+	assert.Equal(t, "info", logger.Level())
+
+	assert.NoError(t, logger.SetLevel("debug"))
+	assert.Equal(t, "debug", logger.Level())
+	assert.True(t, logger.log.Core().Enabled(zapcore.DebugLevel))
+
+	assert.NoError(t, logger.SetLevel("error"))
+	assert.False(t, logger.log.Core().Enabled(zapcore.WarnLevel))
+
+	assert.Error(t, logger.SetLevel("loud"))
+	assert.Equal(t, "error", logger.Level())
+}
@@ -0,0 +1,49 @@
+// Package middleware provides HTTP middleware components for the application.
+// It includes authentication, CORS, logging, and telemetry middleware.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+)
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyVerifier checks an API key and returns the principal it acts for,
+// with its permissions already resolved from the key's scopes
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// APIKeyMiddleware authenticates requests that carry an X-API-Key header and
+// places the principal on the request context. Requests without the header are
+// passed on unchanged, so it is chained in front of AuthMiddleware, which
+// accepts requests already authenticated here.
+func APIKeyMiddleware(keys APIKeyVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := keys.VerifyAPIKey(r.Context(), key)
+			switch {
+			case errors.Is(err, auth.ErrInvalidAPIKey):
+				response.SendProblem(w, r, http.StatusUnauthorized, "Invalid or expired API key")
+				return
+			case err != nil:
+				response.SendProblem(w, r, http.StatusUnauthorized, "Authentication failed")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
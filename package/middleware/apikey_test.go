@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// apiKeyFunc adapts a function to APIKeyVerifier
+type apiKeyFunc func(ctx context.Context, key string) (*auth.Principal, error)
+
+func (f apiKeyFunc) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	return f(ctx, key)
+}
+
+var testAPIKeys = apiKeyFunc(func(_ context.Context, key string) (*auth.Principal, error) {
+	switch key {
+	case "ak_valid.secret":
+		return &auth.Principal{
+			Subject:     "ci",
+			Permissions: []auth.Permission{auth.PermProductWrite},
+			Method:      auth.MethodAPIKey,
+		}, nil
+	case "ak_broken.secret":
+		return nil, errors.New("database error")
+	}
+	return nil, auth.ErrInvalidAPIKey
+})
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	handler := APIKeyMiddleware(testAPIKeys)(
+		AuthMiddleware(AuthConfig{Basic: testCredentials, Policy: auth.DefaultPolicy()})(
+			RequirePermission(auth.PermProductWrite, func(w http.ResponseWriter, r *http.Request) {
+				principal, _ := auth.PrincipalFromContext(r.Context())
+				_, _ = w.Write([]byte(principal.Method))
+			}),
+		),
+	)
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		basicAuth      bool
+		expectedStatus int
+		expectedMethod string
+	}{
+		{"Valid API key", "ak_valid.secret", false, http.StatusOK, auth.MethodAPIKey},
+		{"Invalid API key", "ak_other.secret", false, http.StatusUnauthorized, ""},
+		{"Verifier error", "ak_broken.secret", false, http.StatusUnauthorized, ""},
+		{"Invalid API key is not rescued by Basic", "ak_other.secret", true, http.StatusUnauthorized, ""},
+		{"No API key falls through to Basic", "", true, http.StatusOK, auth.MethodBasic},
+		{"No credentials", "", false, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", http.NoBody)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.basicAuth {
+				req.SetBasicAuth("admin", "password")
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedMethod != "" {
+				assert.Equal(t, tt.expectedMethod, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddleware_ScopesLimitPermissions(t *testing.T) {
+	handler := APIKeyMiddleware(testAPIKeys)(
+		AuthMiddleware(AuthConfig{Policy: auth.DefaultPolicy()})(
+			RequirePermission(auth.PermProductDelete, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}),
+		),
+	)
+
+	req := httptest.NewRequest("DELETE", "/", http.NoBody)
+	req.Header.Set(APIKeyHeader, "ak_valid.secret")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
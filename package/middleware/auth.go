@@ -0,0 +1,139 @@
+// Package middleware provides HTTP middleware components for the application.
+// It includes authentication, CORS, logging, and telemetry middleware.
+package middleware
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+)
+
+// TokenVerifier verifies bearer access tokens and returns the principal they were issued to
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (*auth.Principal, error)
+}
+
+// AuthConfig selects the authentication schemes accepted by AuthMiddleware.
+// A nil Tokens or Basic field disables that scheme. Policy expands the
+// principal's roles into permissions; without it the principal has none.
+type AuthConfig struct {
+	Tokens TokenVerifier
+	Basic  auth.CredentialVerifier
+	Policy *auth.Policy
+}
+
+// AuthMiddleware authenticates requests with a Bearer token or Basic credentials
+// and places the authenticated principal on the request context. Requests already
+// authenticated by an earlier middleware, such as APIKeyMiddleware, are passed on.
+func AuthMiddleware(cfg AuthConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.PrincipalFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				unauthorized(w, r, cfg, "Unauthorized")
+				return
+			}
+
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) != 2 {
+				unauthorized(w, r, cfg, "Invalid authentication format")
+				return
+			}
+
+			var (
+				principal *auth.Principal
+				message   string
+			)
+			switch {
+			case strings.EqualFold(parts[0], "Bearer") && cfg.Tokens != nil:
+				principal, message = authenticateBearer(cfg.Tokens, parts[1])
+			case strings.EqualFold(parts[0], "Basic") && cfg.Basic != nil:
+				principal, message = authenticateBasic(r, cfg.Basic, parts[1])
+			default:
+				message = "Invalid authentication format"
+			}
+
+			if principal == nil {
+				unauthorized(w, r, cfg, message)
+				return
+			}
+
+			if cfg.Policy != nil {
+				principal.Permissions = cfg.Policy.Permissions(principal.Roles)
+			}
+
+			// Proceed to next handler
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// RequirePermission wraps a handler so that it only runs for callers granted perm.
+// It is attached per route in RegisterHandlers, behind AuthMiddleware.
+func RequirePermission(perm auth.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			response.SendProblem(w, r, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !principal.HasPermission(perm) {
+			response.SendProblem(w, r, http.StatusForbidden, "Forbidden: missing permission "+string(perm))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func authenticateBearer(tokens TokenVerifier, token string) (*auth.Principal, string) {
+	principal, err := tokens.VerifyAccessToken(strings.TrimSpace(token))
+	if err != nil {
+		return nil, "Invalid or expired token"
+	}
+	return principal, ""
+}
+
+func authenticateBasic(r *http.Request, verifier auth.CredentialVerifier, encoded string) (*auth.Principal, string) {
+	// Basic Auth Format: "Basic base64(username:password)"
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "Invalid base64 encoding"
+	}
+
+	credentials := strings.SplitN(string(decoded), ":", 2)
+	if len(credentials) != 2 {
+		return nil, "Invalid credentials format"
+	}
+
+	principal, err := verifier.VerifyCredentials(r.Context(), credentials[0], credentials[1])
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return nil, "Unauthorized"
+	case errors.Is(err, auth.ErrAccountLocked):
+		return nil, "Account is temporarily locked"
+	case err != nil:
+		return nil, "Authentication failed"
+	}
+	principal.Method = auth.MethodBasic
+	return principal, ""
+}
+
+// unauthorized advertises the enabled schemes and sends a 401 response
+func unauthorized(w http.ResponseWriter, r *http.Request, cfg AuthConfig, message string) {
+	if cfg.Tokens != nil {
+		w.Header().Add("WWW-Authenticate", `Bearer realm="api"`)
+	}
+	if cfg.Basic != nil {
+		w.Header().Add("WWW-Authenticate", `Basic realm="api"`)
+	}
+	response.SendProblem(w, r, http.StatusUnauthorized, message)
+}
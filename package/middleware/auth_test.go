@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// credentialFunc adapts a function to auth.CredentialVerifier
+type credentialFunc func(ctx context.Context, username, password string) (*auth.Principal, error)
+
+func (f credentialFunc) VerifyCredentials(ctx context.Context, username, password string) (*auth.Principal, error) {
+	return f(ctx, username, password)
+}
+
+var testCredentials = credentialFunc(func(_ context.Context, username, password string) (*auth.Principal, error) {
+	switch {
+	case username == "locked":
+		return nil, auth.ErrAccountLocked
+	case username != "admin" || password != "password":
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Principal{Subject: "admin", Roles: []string{"admin"}}, nil
+})
+
+func TestAuthMiddleware(t *testing.T) {
+	tokens, err := auth.NewTokenManager(auth.JWTConfig{Secret: "test-secret", Issuer: "test"})
+	require.NoError(t, err)
+
+	otherTokens, err := auth.NewTokenManager(auth.JWTConfig{Secret: "other-secret", Issuer: "test"})
+	require.NoError(t, err)
+
+	validToken, _, err := tokens.IssueAccessToken(&auth.Principal{Subject: "admin", Roles: []string{"admin"}})
+	require.NoError(t, err)
+
+	forgedToken, _, err := otherTokens.IssueAccessToken(&auth.Principal{Subject: "admin"})
+	require.NoError(t, err)
+
+	cfg := AuthConfig{
+		Tokens: tokens,
+		Basic:  testCredentials,
+	}
+
+	tests := []struct {
+		name           string
+		authHeader     string
+		expectedStatus int
+		shouldProceed  bool
+		expectedMethod string
+	}{
+		{
+			name:           "Valid credentials",
+			authHeader:     "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:password")),
+			expectedStatus: http.StatusOK,
+			shouldProceed:  true,
+			expectedMethod: auth.MethodBasic,
+		},
+		{
+			name:           "Valid bearer token",
+			authHeader:     "Bearer " + validToken,
+			expectedStatus: http.StatusOK,
+			shouldProceed:  true,
+			expectedMethod: auth.MethodJWT,
+		},
+		{
+			name:           "Missing auth header",
+			authHeader:     "",
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Invalid bearer token",
+			authHeader:     "Bearer token123",
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Bearer token signed with another key",
+			authHeader:     "Bearer " + forgedToken,
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Unknown scheme",
+			authHeader:     "Digest token123",
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Invalid base64 encoding",
+			authHeader:     "Basic invalid-base64",
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Invalid credentials format",
+			authHeader:     "Basic " + base64.StdEncoding.EncodeToString([]byte("invalid")),
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Wrong username",
+			authHeader:     "Basic " + base64.StdEncoding.EncodeToString([]byte("wronguser:password")),
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Locked account",
+			authHeader:     "Basic " + base64.StdEncoding.EncodeToString([]byte("locked:password")),
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+		{
+			name:           "Wrong password",
+			authHeader:     "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:wrongpassword")),
+			expectedStatus: http.StatusUnauthorized,
+			shouldProceed:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextCalled := false
+			var principal *auth.Principal
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				principal, _ = auth.PrincipalFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := AuthMiddleware(cfg)(nextHandler)
+			req := httptest.NewRequest("GET", "/", http.NoBody)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.shouldProceed, nextCalled)
+			if tt.shouldProceed {
+				require.NotNil(t, principal)
+				assert.Equal(t, "admin", principal.Subject)
+				assert.Equal(t, tt.expectedMethod, principal.Method)
+				assert.True(t, principal.HasRole("admin"))
+			} else {
+				assert.NotEmpty(t, rr.Header().Values("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_DisabledScheme(t *testing.T) {
+	handler := AuthMiddleware(AuthConfig{
+		Basic: testCredentials,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/", http.NoBody)
+	req.Header.Set("Authorization", "Bearer token123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, []string{`Basic realm="api"`}, rr.Header().Values("WWW-Authenticate"))
+}
+
+func TestRequirePermission(t *testing.T) {
+	policy := auth.DefaultPolicy()
+	handler := AuthMiddleware(AuthConfig{Basic: credentialFunc(func(_ context.Context, username, _ string) (*auth.Principal, error) {
+		return &auth.Principal{Subject: username, Roles: []string{username}}, nil
+	}), Policy: policy})(RequirePermission(auth.PermProductDelete, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name           string
+		role           string
+		expectedStatus int
+	}{
+		{"Admin has every permission", auth.RoleAdmin, http.StatusNoContent},
+		{"Editor may delete products", auth.RoleEditor, http.StatusNoContent},
+		{"No roles", "nobody", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/", http.NoBody)
+			req.SetBasicAuth(tt.role, "password")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.JSONEq(t, `{"type":"/problems/forbidden","title":"Forbidden","status":403,"detail":"Forbidden: missing permission product:delete","instance":"/"}`, rr.Body.String())
+			}
+		})
+	}
+
+	t.Run("Without principal", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		RequirePermission(auth.PermProductDelete, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not run")
+		})(rr, httptest.NewRequest("DELETE", "/", http.NoBody))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
@@ -2,17 +2,62 @@
 // It includes authentication, CORS, logging, and telemetry middleware.
 package middleware
 
-import "net/http"
+import (
+	"net/http"
+	"slices"
+	"sync/atomic"
+)
 
 // CorsMiddleware is a middleware function that adds CORS headers to the response.
 // It allows all origins, methods, and headers, and handles preflight requests.
 // The middleware then calls the next handler in the chain.
 func CorsMiddleware(next http.Handler) http.Handler {
+	return CorsWithOrigins([]string{"*"})(next)
+}
+
+// CorsWithOrigins returns a CORS middleware that only allows the given origins.
+// "*" in the list allows any origin. For any other list the request Origin is
+// echoed back when it matches, and no Access-Control-Allow-Origin header is
+// sent when it does not.
+func CorsWithOrigins(origins []string) func(http.Handler) http.Handler {
+	return NewCors(origins).Middleware
+}
+
+// Cors is a CORS middleware whose allowed origins can be changed while it is serving
+type Cors struct {
+	origins atomic.Pointer[[]string]
+}
+
+// NewCors creates a CORS middleware allowing the given origins
+func NewCors(origins []string) *Cors {
+	c := &Cors{}
+	c.SetAllowedOrigins(origins)
+	return c
+}
+
+// SetAllowedOrigins replaces the allowed origins. Requests already being
+// served keep the previous list.
+func (c *Cors) SetAllowedOrigins(origins []string) {
+	origins = slices.Clone(origins)
+	c.origins.Store(&origins)
+}
+
+// Middleware adds CORS headers to the response and answers preflight requests
+func (c *Cors) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origins := *c.origins.Load()
+
 		// Allow CORS
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if slices.Contains(origins, "*") {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); origin != "" && slices.Contains(origins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
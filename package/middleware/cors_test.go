@@ -23,7 +23,7 @@ func TestCorsMiddleware(t *testing.T) {
 			expectedHeaders: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
 				"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE, OPTIONS",
-				"Access-Control-Allow-Headers": "Content-Type, Authorization",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization, X-API-Key",
 			},
 			shouldCallNext: true,
 		},
@@ -34,7 +34,7 @@ func TestCorsMiddleware(t *testing.T) {
 			expectedHeaders: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
 				"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE, OPTIONS",
-				"Access-Control-Allow-Headers": "Content-Type, Authorization",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization, X-API-Key",
 			},
 			shouldCallNext: false,
 		},
@@ -45,7 +45,7 @@ func TestCorsMiddleware(t *testing.T) {
 			expectedHeaders: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
 				"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE, OPTIONS",
-				"Access-Control-Allow-Headers": "Content-Type, Authorization",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization, X-API-Key",
 			},
 			shouldCallNext: true,
 		},
@@ -74,3 +74,60 @@ func TestCorsMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestCorsWithOrigins(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := CorsWithOrigins([]string{"https://app.example.com"})(next)
+
+	tests := []struct {
+		name           string
+		origin         string
+		expectedOrigin string
+	}{
+		{name: "allowed origin", origin: "https://app.example.com", expectedOrigin: "https://app.example.com"},
+		{name: "other origin", origin: "https://evil.example.com", expectedOrigin: ""},
+		{name: "no origin", origin: "", expectedOrigin: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expectedOrigin, rr.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "Origin", rr.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestCors_SetAllowedOrigins(t *testing.T) {
+	cors := NewCors([]string{"https://old.example.com"})
+	handler := cors.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	get := func(origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set("Origin", origin)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal(t, "https://old.example.com", get("https://old.example.com"))
+	assert.Equal(t, "", get("https://new.example.com"))
+
+	cors.SetAllowedOrigins([]string{"https://new.example.com"})
+
+	assert.Equal(t, "", get("https://old.example.com"))
+	assert.Equal(t, "https://new.example.com", get("https://new.example.com"))
+
+	cors.SetAllowedOrigins([]string{"*"})
+	assert.Equal(t, "*", get("https://any.example.com"))
+}
@@ -0,0 +1,56 @@
+// Package middleware provides HTTP middleware components for the application.
+// It includes authentication, CORS, logging, and telemetry middleware.
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MitulShah1/golang-rest-api-template/internal/response"
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+)
+
+// ReadYourWrites starts a database session for each request, so that reads
+// after a write in the same request go to the primary instead of a replica
+// that may not have the write yet
+func ReadYourWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(database.WithSession(r.Context())))
+	})
+}
+
+// IncludeDeletedParam is the query parameter asking for soft-deleted rows too
+const IncludeDeletedParam = "include_deleted"
+
+// IncludeDeleted lets callers holding perm see soft-deleted rows by passing
+// include_deleted=true. Other callers asking for them are refused.
+func IncludeDeleted(perm auth.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		value := r.URL.Query().Get(IncludeDeletedParam)
+		if value == "" {
+			next(w, r)
+			return
+		}
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			response.SendProblem(w, r, http.StatusBadRequest, "Invalid "+IncludeDeletedParam+" value, use true or false")
+			return
+		}
+		if !include {
+			next(w, r)
+			return
+		}
+
+		principal, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			response.SendProblem(w, r, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !principal.HasPermission(perm) {
+			response.SendProblem(w, r, http.StatusForbidden, "Forbidden: missing permission "+string(perm))
+			return
+		}
+		next(w, r.WithContext(database.WithDeleted(r.Context())))
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MitulShah1/golang-rest-api-template/package/auth"
+	"github.com/MitulShah1/golang-rest-api-template/package/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	var before, after bool
+	handler := ReadYourWrites(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		before = database.UsePrimary(r.Context())
+		database.MarkWritten(r.Context())
+		after = database.UsePrimary(r.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, before)
+	assert.True(t, after)
+
+	// Each request starts a new session
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, before)
+}
+
+func TestIncludeDeleted(t *testing.T) {
+	var included bool
+	handler := IncludeDeleted(auth.PermProductDelete, func(w http.ResponseWriter, r *http.Request) {
+		included = database.IncludeDeleted(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+	policy := auth.DefaultPolicy()
+
+	tests := []struct {
+		name           string
+		query          string
+		roles          []string
+		expectedStatus int
+		expectIncluded bool
+	}{
+		{"Not asked", "", nil, http.StatusNoContent, false},
+		{"Asked for false", "?include_deleted=false", nil, http.StatusNoContent, false},
+		{"Invalid value", "?include_deleted=maybe", nil, http.StatusBadRequest, false},
+		{"Without principal", "?include_deleted=true", nil, http.StatusUnauthorized, false},
+		{"Missing permission", "?include_deleted=true", []string{"viewer"}, http.StatusForbidden, false},
+		{"Editor", "?include_deleted=true", []string{auth.RoleEditor}, http.StatusNoContent, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			included = false
+			req := httptest.NewRequest(http.MethodGet, "/products"+tt.query, http.NoBody)
+			if tt.roles != nil {
+				principal := &auth.Principal{Subject: "user", Roles: tt.roles, Permissions: policy.Permissions(tt.roles)}
+				req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+			}
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectIncluded, included)
+		})
+	}
+}